@@ -54,6 +54,9 @@ func SetupRouter(
 			beautyConf.ApiVersion,
 			authCoreHandler,
 			handler.NewAnalysisHandler(beautyService, authCoreMiddleware),
+			handler.NewAdminHandler(beautyService, authCoreMiddleware),
+			handler.NewLeaderboardHandler(beautyService, authCoreMiddleware),
+			handler.NewCollectionHandler(beautyService, authCoreMiddleware),
 		),
 	)
 
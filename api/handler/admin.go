@@ -0,0 +1,55 @@
+// File:		admin.go
+// Created by:	Hoven
+// Created on:	2025-05-22
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-puzzles/puzzles/pgin"
+	"github.com/yazl-tech/beauty-rating-server/service/dto"
+)
+
+type AdminHandlerApp interface {
+	GetAnalystWeights(ctx context.Context) (*dto.AnalystWeightsResponse, error)
+	UpdateAnalystWeights(ctx context.Context, req *dto.UpdateAnalystWeightsRequest) (*dto.AnalystWeightsResponse, error)
+	GetExperimentReport(ctx context.Context, req *dto.GetExperimentReportRequest) (*dto.ExperimentReportResponse, error)
+}
+
+type AdminHandler struct {
+	adminApp   AdminHandlerApp
+	middleware UserMiddleware
+}
+
+func NewAdminHandler(adminApp AdminHandlerApp, middleware UserMiddleware) *AdminHandler {
+	return &AdminHandler{
+		adminApp:   adminApp,
+		middleware: middleware,
+	}
+}
+
+func (ah *AdminHandler) Init(router gin.IRouter) {
+	adminGrp := router.Group("admin")
+	adminGrp.Use(ah.middleware.UserLoginRequired(), ah.middleware.GrpcTokenRequired())
+	adminGrp.GET("analyst/weights", pgin.ResponseHandler(ah.getAnalystWeightsHandler))
+	adminGrp.PUT("analyst/weights", pgin.RequestResponseHandler(ah.updateAnalystWeightsHandler))
+	adminGrp.GET("experiments/:experiment/report", pgin.RequestResponseHandler(ah.getExperimentReportHandler))
+}
+
+func (ah *AdminHandler) getAnalystWeightsHandler(ctx *gin.Context) (*dto.AnalystWeightsResponse, error) {
+	return ah.adminApp.GetAnalystWeights(ctx.Request.Context())
+}
+
+func (ah *AdminHandler) updateAnalystWeightsHandler(ctx *gin.Context, req *dto.UpdateAnalystWeightsRequest) (*dto.AnalystWeightsResponse, error) {
+	return ah.adminApp.UpdateAnalystWeights(ctx.Request.Context(), req)
+}
+
+func (ah *AdminHandler) getExperimentReportHandler(ctx *gin.Context, req *dto.GetExperimentReportRequest) (*dto.ExperimentReportResponse, error) {
+	return ah.adminApp.GetExperimentReport(ctx.Request.Context(), req)
+}
@@ -12,6 +12,7 @@ import (
 	"context"
 	"mime/multipart"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-puzzles/puzzles/pgin"
@@ -21,14 +22,29 @@ import (
 
 type AnalysisHandlerApp interface {
 	DoAnalysis(ctx context.Context, userId int, fh *multipart.FileHeader) (*dto.DoAnalysisResponse, error)
-	GetImage(ctx context.Context, imageId string, rw http.ResponseWriter, req *http.Request)
-	GetAnalysisDetails(ctx context.Context, userId int) (*dto.GetDetailsResponse, error)
+	StreamAnalysis(ctx context.Context, userId int, fh *multipart.FileHeader, emit dto.EventEmitter) (*dto.DoAnalysisResponse, error)
+	SubmitAnalysisJob(ctx context.Context, userId int, fh *multipart.FileHeader) (*dto.DoAnalysisResponse, error)
+	GetAnalysisJob(ctx context.Context, userId, jobId int) (*dto.GetAnalysisJobResponse, error)
+	GetImage(ctx context.Context, imageId, size string, rw http.ResponseWriter, req *http.Request)
+	GetAnalysisDetails(ctx context.Context, userId int, req *dto.GetDetailsRequest) (*dto.GetDetailsResponse, error)
 	ShareAnalysisDetail(ctx context.Context, userId, reportId int) (*dto.ShareDetailResponse, error)
 	GetShareDetail(ctx context.Context, shareToken *dto.GetShareDetailRequest) (*dto.GetDetailResponse, error)
 	DoFavorite(ctx context.Context, userId int, recordId int) error
 	DoUnfavorite(ctx context.Context, userId int, recordId int) error
-	GetFavoriteDetails(ctx context.Context, userId int) (*dto.GetDetailsResponse, error)
+	GetFavoriteDetails(ctx context.Context, userId int, req *dto.GetDetailsRequest) (*dto.GetDetailsResponse, error)
+	GetStats(ctx context.Context, userId int, req *dto.GetStatsRequest) (*dto.GetStatsResponse, error)
 	DeleteAnalysis(ctx context.Context, userId int, recordId int) error
+	Reanalyze(ctx context.Context, userId int, req *dto.ReanalyzeRequest) (*dto.GetDetailResponse, error)
+	GetAnalysisVersions(ctx context.Context, userId, reportId int) (*dto.GetVersionsResponse, error)
+	SetPrimaryVersion(ctx context.Context, userId int, req *dto.SetPrimaryVersionRequest) (*dto.GetDetailResponse, error)
+	CompareImages(ctx context.Context, userId int, before, after *multipart.FileHeader) (*dto.CompareResponse, error)
+	CompareReports(ctx context.Context, userId int, req *dto.CompareRequest) (*dto.CompareResponse, error)
+	GetComparison(ctx context.Context, userId, compareId int) (*dto.CompareResponse, error)
+	GetComparisons(ctx context.Context, userId int, req *dto.GetComparisonsRequest) (*dto.GetComparisonsResponse, error)
+	FavoriteComparison(ctx context.Context, userId, compareId int) error
+	UnFavoriteComparison(ctx context.Context, userId, compareId int) error
+	ShareComparison(ctx context.Context, userId, compareId int) (*dto.ShareDetailResponse, error)
+	GetShareComparison(ctx context.Context, req *dto.GetShareComparisonRequest) (*dto.CompareResponse, error)
 }
 
 type AnalysisHandler struct {
@@ -47,15 +63,28 @@ func (ah *AnalysisHandler) Init(router gin.IRouter) {
 	analysisGrp := router.Group("analysis")
 	analysisGrp.GET("image/:imageId", pgin.RequestHandler(ah.getImageHandler))
 	analysisGrp.GET("share/detail", pgin.RequestResponseHandler(ah.getShareDetail))
+	analysisGrp.GET("compare/share", pgin.RequestResponseHandler(ah.getShareComparisonHandler))
 
 	needLoginGrp := router.Group("analysis", ah.middleware.UserLoginRequired())
 	needLoginGrp.POST("", pgin.ResponseHandler(ah.doAnalysisHandler))
-	needLoginGrp.GET("", pgin.ResponseHandler(ah.getAnalysisDetails))
+	needLoginGrp.POST("stream", ah.streamAnalysisHandler)
+	needLoginGrp.GET("", pgin.RequestResponseHandler(ah.getAnalysisDetails))
+	needLoginGrp.GET("jobs/:jobId", pgin.RequestResponseHandler(ah.getAnalysisJobHandler))
 	needLoginGrp.POST("share/detail/:reportId", pgin.RequestResponseHandler(ah.shareAnalusysDetail))
-	needLoginGrp.GET("favorite", pgin.ResponseHandler(ah.getFavoriteDetails))
+	needLoginGrp.GET("favorite", pgin.RequestResponseHandler(ah.getFavoriteDetails))
+	needLoginGrp.GET("stats", pgin.RequestResponseHandler(ah.getStatsHandler))
 	needLoginGrp.POST("favorite/:reportId", pgin.RequestWithErrorHandler(ah.doFavoriteHandler))
 	needLoginGrp.POST("unfavorite/:reportId", pgin.RequestWithErrorHandler(ah.doUnFavoriteHandler))
 	needLoginGrp.DELETE(":reportId", pgin.RequestWithErrorHandler(ah.deleteAnalysisHandler))
+	needLoginGrp.POST(":reportId/reanalyze", pgin.RequestResponseHandler(ah.reanalyzeHandler))
+	needLoginGrp.GET(":reportId/versions", pgin.RequestResponseHandler(ah.getVersionsHandler))
+	needLoginGrp.PUT(":reportId/versions/:version/primary", pgin.RequestResponseHandler(ah.setPrimaryVersionHandler))
+	needLoginGrp.POST("compare", pgin.ResponseHandler(ah.compareHandler))
+	needLoginGrp.GET("compare", pgin.RequestResponseHandler(ah.getComparisonsHandler))
+	needLoginGrp.GET("compare/:compareId", pgin.RequestResponseHandler(ah.getComparisonHandler))
+	needLoginGrp.POST("compare/favorite/:compareId", pgin.RequestWithErrorHandler(ah.favoriteComparisonHandler))
+	needLoginGrp.POST("compare/unfavorite/:compareId", pgin.RequestWithErrorHandler(ah.unFavoriteComparisonHandler))
+	needLoginGrp.POST("compare/share/:compareId", pgin.RequestResponseHandler(ah.shareComparisonHandler))
 }
 
 func (ah *AnalysisHandler) shareAnalusysDetail(ctx *gin.Context, req *dto.ShareDetailRequest) (*dto.ShareDetailResponse, error) {
@@ -71,26 +100,35 @@ func (ah *AnalysisHandler) getShareDetail(ctx *gin.Context, req *dto.GetShareDet
 	return ah.analysisApp.GetShareDetail(ctx.Request.Context(), req)
 }
 
-func (ah *AnalysisHandler) getFavoriteDetails(ctx *gin.Context) (*dto.GetDetailsResponse, error) {
+func (ah *AnalysisHandler) getFavoriteDetails(ctx *gin.Context, req *dto.GetDetailsRequest) (*dto.GetDetailsResponse, error) {
 	userId, err := ah.middleware.GetCurrentUserId(ctx)
 	if err != nil {
 		return nil, exception.ErrUnauthorized
 	}
 
-	return ah.analysisApp.GetFavoriteDetails(ctx.Request.Context(), userId)
+	return ah.analysisApp.GetFavoriteDetails(ctx.Request.Context(), userId, req)
 }
 
-func (ah *AnalysisHandler) getAnalysisDetails(ctx *gin.Context) (*dto.GetDetailsResponse, error) {
+func (ah *AnalysisHandler) getAnalysisDetails(ctx *gin.Context, req *dto.GetDetailsRequest) (*dto.GetDetailsResponse, error) {
 	userId, err := ah.middleware.GetCurrentUserId(ctx)
 	if err != nil {
 		return nil, exception.ErrUnauthorized
 	}
 
-	return ah.analysisApp.GetAnalysisDetails(ctx.Request.Context(), userId)
+	return ah.analysisApp.GetAnalysisDetails(ctx.Request.Context(), userId, req)
+}
+
+func (ah *AnalysisHandler) getStatsHandler(ctx *gin.Context, req *dto.GetStatsRequest) (*dto.GetStatsResponse, error) {
+	userId, err := ah.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	return ah.analysisApp.GetStats(ctx.Request.Context(), userId, req)
 }
 
 func (ah *AnalysisHandler) getImageHandler(ctx *gin.Context, req *dto.GetImageRequest) {
-	ah.analysisApp.GetImage(ctx.Request.Context(), req.ImageId, ctx.Writer, ctx.Request)
+	ah.analysisApp.GetImage(ctx.Request.Context(), req.ImageId, req.Size, ctx.Writer, ctx.Request)
 }
 
 func (ah *AnalysisHandler) doAnalysisHandler(ctx *gin.Context) (*dto.DoAnalysisResponse, error) {
@@ -104,9 +142,65 @@ func (ah *AnalysisHandler) doAnalysisHandler(ctx *gin.Context) (*dto.DoAnalysisR
 		return nil, exception.ErrUploadAvatar
 	}
 
+	// async=true 时只提交任务并返回任务 id，通过 GET /analysis/jobs/:jobId 轮询结果
+	if async, _ := strconv.ParseBool(ctx.Query("async")); async {
+		return ah.analysisApp.SubmitAnalysisJob(ctx.Request.Context(), userId, fh)
+	}
+
 	return ah.analysisApp.DoAnalysis(ctx.Request.Context(), userId, fh)
 }
 
+// streamAnalysisHandler 客户端 Accept 包含 text/event-stream 时以 SSE 推送分析进度，
+// 最后的 result 事件与 POST /analysis 的响应体相同；不支持流式的客户端直接得到 POST /analysis 的响应
+func (ah *AnalysisHandler) streamAnalysisHandler(ctx *gin.Context) {
+	if !acceptEventStream(ctx) {
+		pgin.ResponseHandler(ah.doAnalysisHandler)(ctx)
+		return
+	}
+
+	// 建立事件流之前的错误仍以普通响应返回，客户端可以直接根据 HTTP 状态码处理
+	userId, fh, err := ah.streamRequest(ctx)
+	if err != nil {
+		ctx.JSON(errorRet(err))
+		return
+	}
+
+	stream := newEventStream(ctx)
+	defer stream.Close()
+
+	resp, err := ah.analysisApp.StreamAnalysis(ctx.Request.Context(), userId, fh, stream.Emit)
+	if err != nil {
+		_, ret := errorRet(err)
+		stream.Emit(dto.EventError, ret)
+		return
+	}
+
+	stream.Emit(dto.EventResult, pgin.SuccessRet(resp))
+}
+
+func (ah *AnalysisHandler) streamRequest(ctx *gin.Context) (int, *multipart.FileHeader, error) {
+	userId, err := ah.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return 0, nil, exception.ErrUnauthorized
+	}
+
+	fh, err := ctx.FormFile("image")
+	if err != nil {
+		return 0, nil, exception.ErrUploadAvatar
+	}
+
+	return userId, fh, nil
+}
+
+func (ah *AnalysisHandler) getAnalysisJobHandler(ctx *gin.Context, req *dto.GetAnalysisJobRequest) (*dto.GetAnalysisJobResponse, error) {
+	userId, err := ah.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	return ah.analysisApp.GetAnalysisJob(ctx.Request.Context(), userId, req.JobId)
+}
+
 func (ah *AnalysisHandler) doFavoriteHandler(ctx *gin.Context, req *dto.DoFavoriteRequest) error {
 	userId, err := ah.middleware.GetCurrentUserId(ctx)
 	if err != nil {
@@ -133,3 +227,100 @@ func (ah *AnalysisHandler) deleteAnalysisHandler(ctx *gin.Context, req *dto.Dele
 
 	return ah.analysisApp.DeleteAnalysis(ctx.Request.Context(), userId, req.ReportId)
 }
+
+func (ah *AnalysisHandler) reanalyzeHandler(ctx *gin.Context, req *dto.ReanalyzeRequest) (*dto.GetDetailResponse, error) {
+	userId, err := ah.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	return ah.analysisApp.Reanalyze(ctx.Request.Context(), userId, req)
+}
+
+func (ah *AnalysisHandler) getVersionsHandler(ctx *gin.Context, req *dto.GetVersionsRequest) (*dto.GetVersionsResponse, error) {
+	userId, err := ah.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	return ah.analysisApp.GetAnalysisVersions(ctx.Request.Context(), userId, req.ReportId)
+}
+
+func (ah *AnalysisHandler) setPrimaryVersionHandler(ctx *gin.Context, req *dto.SetPrimaryVersionRequest) (*dto.GetDetailResponse, error) {
+	userId, err := ah.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	return ah.analysisApp.SetPrimaryVersion(ctx.Request.Context(), userId, req)
+}
+
+// compareHandler 上传了 before 和 after 两张照片时对比照片，否则对比 beforeReportId 和 afterReportId 两份报告
+func (ah *AnalysisHandler) compareHandler(ctx *gin.Context) (*dto.CompareResponse, error) {
+	userId, err := ah.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	before, beforeErr := ctx.FormFile("before")
+	after, afterErr := ctx.FormFile("after")
+	if beforeErr == nil && afterErr == nil {
+		return ah.analysisApp.CompareImages(ctx.Request.Context(), userId, before, after)
+	}
+
+	req := new(dto.CompareRequest)
+	if err := ctx.ShouldBind(req); err != nil {
+		return nil, exception.ErrCompareInput
+	}
+
+	return ah.analysisApp.CompareReports(ctx.Request.Context(), userId, req)
+}
+
+func (ah *AnalysisHandler) getComparisonsHandler(ctx *gin.Context, req *dto.GetComparisonsRequest) (*dto.GetComparisonsResponse, error) {
+	userId, err := ah.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	return ah.analysisApp.GetComparisons(ctx.Request.Context(), userId, req)
+}
+
+func (ah *AnalysisHandler) getComparisonHandler(ctx *gin.Context, req *dto.GetComparisonRequest) (*dto.CompareResponse, error) {
+	userId, err := ah.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	return ah.analysisApp.GetComparison(ctx.Request.Context(), userId, req.CompareId)
+}
+
+func (ah *AnalysisHandler) favoriteComparisonHandler(ctx *gin.Context, req *dto.GetComparisonRequest) error {
+	userId, err := ah.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return exception.ErrUnauthorized
+	}
+
+	return ah.analysisApp.FavoriteComparison(ctx.Request.Context(), userId, req.CompareId)
+}
+
+func (ah *AnalysisHandler) unFavoriteComparisonHandler(ctx *gin.Context, req *dto.GetComparisonRequest) error {
+	userId, err := ah.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return exception.ErrUnauthorized
+	}
+
+	return ah.analysisApp.UnFavoriteComparison(ctx.Request.Context(), userId, req.CompareId)
+}
+
+func (ah *AnalysisHandler) shareComparisonHandler(ctx *gin.Context, req *dto.GetComparisonRequest) (*dto.ShareDetailResponse, error) {
+	userId, err := ah.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	return ah.analysisApp.ShareComparison(ctx.Request.Context(), userId, req.CompareId)
+}
+
+func (ah *AnalysisHandler) getShareComparisonHandler(ctx *gin.Context, req *dto.GetShareComparisonRequest) (*dto.CompareResponse, error) {
+	return ah.analysisApp.GetShareComparison(ctx.Request.Context(), req)
+}
@@ -0,0 +1,136 @@
+// File:		collection.go
+// Created by:	Hoven
+// Created on:	2025-06-15
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-puzzles/puzzles/pgin"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"github.com/yazl-tech/beauty-rating-server/service/dto"
+)
+
+type CollectionHandlerApp interface {
+	GetCollections(ctx context.Context, userId int) (*dto.GetCollectionsResponse, error)
+	GetCollection(ctx context.Context, userId, collectionId int) (*dto.CollectionResponse, error)
+	CreateCollection(ctx context.Context, userId int, req *dto.CreateCollectionRequest) (*dto.CollectionResponse, error)
+	UpdateCollection(ctx context.Context, userId int, req *dto.UpdateCollectionRequest) (*dto.CollectionResponse, error)
+	DeleteCollection(ctx context.Context, userId, collectionId int) error
+	SortCollections(ctx context.Context, userId int, req *dto.SortCollectionsRequest) (*dto.GetCollectionsResponse, error)
+	GetCollectionDetails(ctx context.Context, userId int, req *dto.GetCollectionDetailsRequest) (*dto.GetDetailsResponse, error)
+	AddToCollection(ctx context.Context, userId int, req *dto.CollectionItemRequest) error
+	RemoveFromCollection(ctx context.Context, userId int, req *dto.CollectionItemRequest) error
+}
+
+type CollectionHandler struct {
+	collectionApp CollectionHandlerApp
+	middleware    UserMiddleware
+}
+
+func NewCollectionHandler(collectionApp CollectionHandlerApp, middleware UserMiddleware) *CollectionHandler {
+	return &CollectionHandler{
+		collectionApp: collectionApp,
+		middleware:    middleware,
+	}
+}
+
+func (ch *CollectionHandler) Init(router gin.IRouter) {
+	needLoginGrp := router.Group("analysis/collections", ch.middleware.UserLoginRequired())
+	needLoginGrp.GET("", pgin.ResponseHandler(ch.getCollectionsHandler))
+	needLoginGrp.POST("", pgin.RequestResponseHandler(ch.createCollectionHandler))
+	needLoginGrp.PUT("order", pgin.RequestResponseHandler(ch.sortCollectionsHandler))
+	needLoginGrp.GET(":collectionId", pgin.RequestResponseHandler(ch.getCollectionHandler))
+	needLoginGrp.PUT(":collectionId", pgin.RequestResponseHandler(ch.updateCollectionHandler))
+	needLoginGrp.DELETE(":collectionId", pgin.RequestWithErrorHandler(ch.deleteCollectionHandler))
+	needLoginGrp.GET(":collectionId/reports", pgin.RequestResponseHandler(ch.getCollectionDetailsHandler))
+	needLoginGrp.POST(":collectionId/reports/:reportId", pgin.RequestWithErrorHandler(ch.addToCollectionHandler))
+	needLoginGrp.DELETE(":collectionId/reports/:reportId", pgin.RequestWithErrorHandler(ch.removeFromCollectionHandler))
+}
+
+func (ch *CollectionHandler) getCollectionsHandler(ctx *gin.Context) (*dto.GetCollectionsResponse, error) {
+	userId, err := ch.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	return ch.collectionApp.GetCollections(ctx.Request.Context(), userId)
+}
+
+func (ch *CollectionHandler) createCollectionHandler(ctx *gin.Context, req *dto.CreateCollectionRequest) (*dto.CollectionResponse, error) {
+	userId, err := ch.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	return ch.collectionApp.CreateCollection(ctx.Request.Context(), userId, req)
+}
+
+func (ch *CollectionHandler) sortCollectionsHandler(ctx *gin.Context, req *dto.SortCollectionsRequest) (*dto.GetCollectionsResponse, error) {
+	userId, err := ch.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	return ch.collectionApp.SortCollections(ctx.Request.Context(), userId, req)
+}
+
+func (ch *CollectionHandler) getCollectionHandler(ctx *gin.Context, req *dto.CollectionRequest) (*dto.CollectionResponse, error) {
+	userId, err := ch.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	return ch.collectionApp.GetCollection(ctx.Request.Context(), userId, req.CollectionId)
+}
+
+func (ch *CollectionHandler) updateCollectionHandler(ctx *gin.Context, req *dto.UpdateCollectionRequest) (*dto.CollectionResponse, error) {
+	userId, err := ch.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	return ch.collectionApp.UpdateCollection(ctx.Request.Context(), userId, req)
+}
+
+func (ch *CollectionHandler) deleteCollectionHandler(ctx *gin.Context, req *dto.CollectionRequest) error {
+	userId, err := ch.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return exception.ErrUnauthorized
+	}
+
+	return ch.collectionApp.DeleteCollection(ctx.Request.Context(), userId, req.CollectionId)
+}
+
+func (ch *CollectionHandler) getCollectionDetailsHandler(ctx *gin.Context, req *dto.GetCollectionDetailsRequest) (*dto.GetDetailsResponse, error) {
+	userId, err := ch.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return nil, exception.ErrUnauthorized
+	}
+
+	return ch.collectionApp.GetCollectionDetails(ctx.Request.Context(), userId, req)
+}
+
+func (ch *CollectionHandler) addToCollectionHandler(ctx *gin.Context, req *dto.CollectionItemRequest) error {
+	userId, err := ch.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return exception.ErrUnauthorized
+	}
+
+	return ch.collectionApp.AddToCollection(ctx.Request.Context(), userId, req)
+}
+
+func (ch *CollectionHandler) removeFromCollectionHandler(ctx *gin.Context, req *dto.CollectionItemRequest) error {
+	userId, err := ch.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return exception.ErrUnauthorized
+	}
+
+	return ch.collectionApp.RemoveFromCollection(ctx.Request.Context(), userId, req)
+}
@@ -0,0 +1,69 @@
+// File:		leaderboard.go
+// Created by:	Hoven
+// Created on:	2025-06-14
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-puzzles/puzzles/pgin"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"github.com/yazl-tech/beauty-rating-server/service/dto"
+)
+
+type LeaderboardHandlerApp interface {
+	JoinLeaderboard(ctx context.Context, req *dto.LeaderboardAppRequest) (*dto.LeaderboardMemberResponse, error)
+	LeaveLeaderboard(ctx context.Context, userId int, req *dto.LeaderboardAppRequest) error
+	GetLeaderboard(ctx context.Context, req *dto.GetLeaderboardRequest) (*dto.GetLeaderboardResponse, error)
+	GetMyLeaderboardRank(ctx context.Context, req *dto.GetMyRankRequest) (*dto.GetMyRankResponse, error)
+}
+
+type LeaderboardHandler struct {
+	leaderboardApp LeaderboardHandlerApp
+	middleware     UserMiddleware
+}
+
+func NewLeaderboardHandler(leaderboardApp LeaderboardHandlerApp, middleware UserMiddleware) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		leaderboardApp: leaderboardApp,
+		middleware:     middleware,
+	}
+}
+
+func (lh *LeaderboardHandler) Init(router gin.IRouter) {
+	needLoginGrp := router.Group("leaderboard", lh.middleware.UserLoginRequired())
+	needLoginGrp.POST("optout", pgin.RequestWithErrorHandler(lh.leaveHandler))
+	needLoginGrp.GET(":window", pgin.RequestResponseHandler(lh.getLeaderboardHandler))
+
+	// 加入榜单和查询排名时需要读取用户资料
+	profileGrp := router.Group("leaderboard", lh.middleware.UserLoginRequired(), lh.middleware.GrpcTokenRequired())
+	profileGrp.POST("optin", pgin.RequestResponseHandler(lh.joinHandler))
+	profileGrp.GET(":window/me", pgin.RequestResponseHandler(lh.getMyRankHandler))
+}
+
+func (lh *LeaderboardHandler) joinHandler(ctx *gin.Context, req *dto.LeaderboardAppRequest) (*dto.LeaderboardMemberResponse, error) {
+	return lh.leaderboardApp.JoinLeaderboard(ctx.Request.Context(), req)
+}
+
+func (lh *LeaderboardHandler) leaveHandler(ctx *gin.Context, req *dto.LeaderboardAppRequest) error {
+	userId, err := lh.middleware.GetCurrentUserId(ctx)
+	if err != nil {
+		return exception.ErrUnauthorized
+	}
+
+	return lh.leaderboardApp.LeaveLeaderboard(ctx.Request.Context(), userId, req)
+}
+
+func (lh *LeaderboardHandler) getLeaderboardHandler(ctx *gin.Context, req *dto.GetLeaderboardRequest) (*dto.GetLeaderboardResponse, error) {
+	return lh.leaderboardApp.GetLeaderboard(ctx.Request.Context(), req)
+}
+
+func (lh *LeaderboardHandler) getMyRankHandler(ctx *gin.Context, req *dto.GetMyRankRequest) (*dto.GetMyRankResponse, error) {
+	return lh.leaderboardApp.GetMyLeaderboardRank(ctx.Request.Context(), req)
+}
@@ -0,0 +1,81 @@
+// File:		stream.go
+// Created by:	Hoven
+// Created on:	2025-05-30
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-puzzles/puzzles/pgin"
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+)
+
+const eventStreamMime = "text/event-stream"
+
+func acceptEventStream(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), eventStreamMime)
+}
+
+// errorRet 返回错误对应的 HTTP 状态码和响应体，业务异常使用其错误码和提示，其余错误按 400 处理
+func errorRet(err error) (int, *pgin.Ret) {
+	plog.Errorf("stream analysis error: %v", err)
+
+	be := new(exception.BeautyException)
+	if !errors.As(err, &be) {
+		return http.StatusBadRequest, pgin.ErrorRet(http.StatusBadRequest, err)
+	}
+
+	status := be.Code()
+	if http.StatusText(status) == "" {
+		status = http.StatusBadRequest
+	}
+	return status, pgin.ErrorRet(be.Code(), be.Message())
+}
+
+// eventStream 以 Server-Sent Events 向客户端推送事件，Emit 可被多个 goroutine 并发调用
+type eventStream struct {
+	mu     sync.Mutex
+	c      *gin.Context
+	closed bool
+}
+
+func newEventStream(c *gin.Context) *eventStream {
+	c.Header("Content-Type", eventStreamMime)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	return &eventStream{c: c}
+}
+
+func (es *eventStream) Emit(event string, data any) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	if es.closed {
+		return
+	}
+
+	es.c.SSEvent(event, data)
+	es.c.Writer.Flush()
+}
+
+// Close 之后的 Emit 直接丢弃，避免请求结束后仍在运行的分析器写入已关闭的连接
+func (es *eventStream) Close() {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	es.closed = true
+}
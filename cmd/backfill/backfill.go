@@ -0,0 +1,68 @@
+// File:		backfill.go
+// Created by:	Hoven
+// Created on:	2025-06-01
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package main
+
+import (
+	"context"
+
+	"github.com/go-puzzles/puzzles/pflags"
+	"github.com/go-puzzles/puzzles/pgorm"
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/yazl-tech/beauty-rating-server/config"
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
+	"github.com/yazl-tech/beauty-rating-server/pkg/imageproc"
+	"github.com/yazl-tech/beauty-rating-server/pkg/oss/minio"
+
+	analysisRepo "github.com/yazl-tech/beauty-rating-server/pkg/dal/analysis"
+)
+
+var (
+	beautyConfFlag = pflags.Struct("beautyConf", (*config.BeautyConfig)(nil), "beauty configuration")
+	mysqlConfFlag  = pflags.Struct("mysqlAuth", (*pgorm.MysqlConfig)(nil), "mysql auth config")
+	minioConfFlag  = pflags.Struct("minioAuth", (*minio.MinioConfig)(nil), "minio auth config")
+	batchSizeFlag  = pflags.Int("batchSize", 100, "records per batch")
+	percentileFlag = pflags.Bool("percentiles", false, "compute percentiles for unranked records instead of backfilling image variants")
+)
+
+// 为历史分析记录补齐缩略图和中图，或补齐排名百分位，与服务使用同一份配置文件
+func main() {
+	pflags.Parse()
+
+	beautyConf := new(config.BeautyConfig)
+	plog.PanicError(beautyConfFlag(beautyConf))
+	minioConf := new(minio.MinioConfig)
+	plog.PanicError(minioConfFlag(minioConf))
+	mysqlConf := new(pgorm.MysqlConfig)
+	plog.PanicError(mysqlConfFlag(mysqlConf))
+
+	plog.PanicError(pgorm.RegisterSqlModelWithConf(mysqlConf, model.AllTables()...))
+	plog.PanicError(pgorm.AutoMigrate(mysqlConf))
+	db := pgorm.GetDbByConf(mysqlConf)
+	repo := analysisRepo.NewAnalysisRepo(db)
+
+	if percentileFlag() {
+		ranked, err := analysis.BackfillPercentiles(context.Background(), repo, beautyConf.RankByGender, batchSizeFlag())
+		plog.PanicError(err)
+		plog.Infof("backfill percentiles finished, %d records ranked", ranked)
+		return
+	}
+
+	images := analysis.NewImageStore(
+		minio.NewMinioOss(minioConf),
+		imageproc.NewProcessor(
+			imageproc.WithMaxDimension(beautyConf.ImageMaxDimension),
+			imageproc.WithMaxPixels(beautyConf.ImageMaxPixels),
+		),
+	)
+
+	done, err := analysis.BackfillImageVariants(context.Background(), repo, images, batchSizeFlag())
+	plog.PanicError(err)
+	plog.Infof("backfill image variants finished, %d records updated", done)
+}
@@ -25,6 +25,12 @@ func main() {
 	// 直接使用模型
 	g.ApplyBasic(
 		&model.Analysis{},
+		&model.AnalysisJob{},
+		&model.AnalysisVersion{},
+		&model.AnalysisComparison{},
+		&model.LeaderboardMember{},
+		&model.AnalysisCollection{},
+		&model.AnalysisCollectionItem{},
 	)
 
 	g.Execute()
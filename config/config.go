@@ -10,29 +10,143 @@ package config
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/go-puzzles/puzzles/putils"
 	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
 )
 
+// ExperimentConfig 分析器 A/B 实验，Key 为空时不开启实验，按 AnalystWeights 选择分析器
+type ExperimentConfig struct {
+	// Key 实验标识，修改后所有用户重新分组
+	Key      string
+	Variants []VariantConfig
+}
+
+// VariantConfig 实验分组，由分析器及其配置组成
+type VariantConfig struct {
+	Name    string
+	Weight  int
+	Analyst analyst.AnalystType
+	// AiModel 仅对 AI 分析器生效，为 AiModels 中的名称，为空时使用第一项
+	AiModel string
+	// PromptVersion 仅对 AI 分析器生效，为空时使用该模型配置的提示词版本
+	PromptVersion string
+}
+
+// AiModelConfig 一个 AI 分析器，BeautyConfig.AiModels 中的每一项都会作为独立的分析器参与选择
+//
+// AiModels 为空时使用 AiModel 生成名为 AiAnalyst 的一项，权重始终跟随 AnalystWeights 中 ai 的权重，
+// 热更新 ai 的权重后同样生效
+type AiModelConfig struct {
+	// Name 分析器名称，调整权重和查看指标时使用，为空时使用 Model
+	Name  string
+	Model string
+	// Temperature 采样温度，为空时使用 1
+	Temperature *float32
+	// PromptVersion 为空时使用 AiPromptVersion
+	PromptVersion string
+	// Timeout 单次分析的超时时间，单位秒，为 0 时只受 AnalystTimeout 限制
+	Timeout int
+	Weight  int
+
+	// legacy 由 AiModel 生成的一项，不使用 Weight
+	legacy bool
+}
+
 type BeautyConfig struct {
 	ApiTls         bool
 	ApiHost        string
 	ApiPrefix      string
 	ApiVersion     string
 	ApiPort        int
+	MetricsPort    int
 	AuthCoreSrv    string
 	TokenKey       string
 	ShareSecretKey string
 	AiModel        string
+	AiModels       []AiModelConfig
+	// AiPromptVersion AI 分析器使用的提示词版本，对应模板文件名
+	AiPromptVersion string
+	// AiPromptDir 额外的提示词模板目录，同名版本覆盖内置模板，模板变量放在同名的 .json 文件中
+	AiPromptDir    string
 	AiBotSrv       string
 	AnalystWeights map[analyst.AnalystType]int
+	// EnsembleWeights 组合分析器中各成员分析器的权重，图片质量分析器的分数与颜值分不可比，
+	// 权重大于 0 时只作为参考成员记录结果，不参与合并
+	EnsembleWeights map[analyst.AnalystType]int
+	// EnsembleTimeout 组合分析器等待成员返回的最长时间，单位秒
+	EnsembleTimeout int
+	RankByGender    bool
+	// AnalystTimeout 单个分析器调用超时时间，单位秒
+	AnalystTimeout int
+	// BreakerThreshold 分析器连续失败多少次后熔断
+	BreakerThreshold int
+	// BreakerCooldown 熔断后多久放行探测请求，单位秒
+	BreakerCooldown int
+	// ResultCacheScope 相同图片复用历史结果的范围：off/user/global
+	ResultCacheScope string
+	// ResultCacheTTL 相同图片复用历史结果的有效期，单位秒
+	ResultCacheTTL int
+	// ImageMaxDimension 上传图片长边超过该像素数时等比缩小
+	ImageMaxDimension int
+	// ImageMaxPixels 上传图片宽高乘积超过该值时直接拒绝，不再解码
+	ImageMaxPixels int
+	// JobWorkers 异步分析任务的 worker 数量
+	JobWorkers int
+	// JobQueueSize 异步分析任务队列长度
+	JobQueueSize int
+	// JobStaleTimeout running 状态的任务超过多久没有更新视为已中断，单位秒
+	JobStaleTimeout int
+	// Moderators 评分前依次执行的图片审核器：rule/ai，为空时不审核
+	Moderators []string
+	// ModerationFailClosed 审核器出错时拒绝图片，默认跳过出错的审核器照常评分
+	ModerationFailClosed bool
+	// MockSalt 模拟分析器计算随机种子时附加的盐
+	MockSalt string
+	// MockPhrasePack 模拟分析器使用的文案包 JSON 文件，为空时使用内置文案
+	MockPhrasePack string
+	// LeaderboardAiOnly 排行榜只统计 AI 分析器给出且没有降级的分数，默认统计所有报告
+	LeaderboardAiOnly bool
+	Experiment        ExperimentConfig
+
+	reloadHooks []func(*BeautyConfig)
+}
+
+// OnReload 注册配置热更新后的回调
+func (bc *BeautyConfig) OnReload(fn func(*BeautyConfig)) {
+	bc.reloadHooks = append(bc.reloadHooks, fn)
+}
+
+// Reload 由 pflags 在远程配置变更并重新解析后调用
+func (bc *BeautyConfig) Reload() {
+	for _, fn := range bc.reloadHooks {
+		fn(bc)
+	}
 }
 
 func (bc *BeautyConfig) AnalystWeight(at analyst.AnalystType) int {
 	return bc.AnalystWeights[at]
 }
 
+// AnalystWeightByName AI 分析器按 AiModels 中的权重，其他分析器按类型的权重
+func (bc *BeautyConfig) AnalystWeightByName(name string, at analyst.AnalystType) int {
+	if at != analyst.TypeAi {
+		return bc.AnalystWeight(at)
+	}
+
+	for _, m := range bc.AiModels {
+		if m.Name != name {
+			continue
+		}
+		if m.legacy {
+			return bc.AnalystWeight(analyst.TypeAi)
+		}
+		return m.Weight
+	}
+	return 0
+}
+
 func (bc *BeautyConfig) SetDefault() {
 	if bc.ApiHost == "" {
 		bc.ApiHost = "localhost:28084"
@@ -50,10 +164,19 @@ func (bc *BeautyConfig) SetDefault() {
 		bc.ApiPort = 8080
 	}
 
+	// /metrics 与 API 分开监听，该端口只应在内网开放
+	if bc.MetricsPort == 0 {
+		bc.MetricsPort = 9090
+	}
+
 	if bc.AuthCoreSrv == "" {
 		bc.AuthCoreSrv = "auth-core"
 	}
 
+	if bc.AiPromptVersion == "" {
+		bc.AiPromptVersion = "v1"
+	}
+
 	if bc.AiBotSrv == "" {
 		bc.AiBotSrv = "ai-bot"
 	}
@@ -62,12 +185,89 @@ func (bc *BeautyConfig) SetDefault() {
 		bc.ShareSecretKey = putils.RandString(7)
 	}
 
+	if bc.AnalystTimeout == 0 {
+		bc.AnalystTimeout = 60
+	}
+
+	if bc.BreakerThreshold == 0 {
+		bc.BreakerThreshold = 5
+	}
+
+	if bc.BreakerCooldown == 0 {
+		bc.BreakerCooldown = 30
+	}
+
+	if bc.ResultCacheScope == "" {
+		bc.ResultCacheScope = "user"
+	}
+
+	if bc.ResultCacheTTL == 0 {
+		bc.ResultCacheTTL = 30 * 24 * 3600
+	}
+
+	if bc.ImageMaxDimension == 0 {
+		bc.ImageMaxDimension = 2048
+	}
+
+	if bc.ImageMaxPixels == 0 {
+		bc.ImageMaxPixels = 40_000_000
+	}
+
+	if bc.JobWorkers == 0 {
+		bc.JobWorkers = 4
+	}
+
+	if bc.JobQueueSize == 0 {
+		bc.JobQueueSize = 128
+	}
+
+	if bc.JobStaleTimeout == 0 {
+		bc.JobStaleTimeout = 300
+	}
+
+	if bc.Moderators == nil {
+		bc.Moderators = []string{"rule"}
+	}
+
+	if bc.EnsembleTimeout == 0 {
+		bc.EnsembleTimeout = 45
+	}
+
+	if bc.EnsembleWeights == nil {
+		bc.EnsembleWeights = map[analyst.AnalystType]int{
+			analyst.TypeAi:        2,
+			analyst.TypeHeuristic: 1,
+		}
+	}
+
 	if bc.AnalystWeights == nil {
 		bc.AnalystWeights = map[analyst.AnalystType]int{
 			analyst.TypeMock: 80,
 			analyst.TypeAi:   20,
 		}
 	}
+
+	if len(bc.AiModels) == 0 && bc.AiModel != "" {
+		bc.AiModels = []AiModelConfig{{
+			Name:   "AiAnalyst",
+			Model:  bc.AiModel,
+			legacy: true,
+		}}
+	}
+
+	for i := range bc.AiModels {
+		m := &bc.AiModels[i]
+		if m.Name == "" {
+			m.Name = m.Model
+		}
+		if m.PromptVersion == "" {
+			m.PromptVersion = bc.AiPromptVersion
+		}
+	}
+
+	if bc.AiModel == "" && len(bc.AiModels) > 0 {
+		bc.AiModel = bc.AiModels[0].Model
+	}
 }
 
 func (bc *BeautyConfig) Validate() error {
@@ -79,5 +279,16 @@ func (bc *BeautyConfig) Validate() error {
 		return errors.New("missing aiModel")
 	}
 
+	names := make(map[string]bool, len(bc.AiModels))
+	for _, m := range bc.AiModels {
+		if m.Model == "" {
+			return fmt.Errorf("aiModels: %v missing model", m.Name)
+		}
+		if names[m.Name] {
+			return fmt.Errorf("duplicate aiModels name: %v", m.Name)
+		}
+		names[m.Name] = true
+	}
+
 	return nil
 }
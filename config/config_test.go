@@ -0,0 +1,65 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+)
+
+func TestAnalystWeightByName_Reload(t *testing.T) {
+	bc := &BeautyConfig{
+		AiModel: "legacy-model",
+		AnalystWeights: map[analyst.AnalystType]int{
+			analyst.TypeAi:        20,
+			analyst.TypeHeuristic: 1,
+		},
+	}
+	bc.SetDefault()
+
+	var weights []int
+	bc.OnReload(func(bc *BeautyConfig) {
+		weights = append(weights, bc.AnalystWeightByName("AiAnalyst", analyst.TypeAi))
+	})
+
+	if w := bc.AnalystWeightByName("AiAnalyst", analyst.TypeAi); w != 20 {
+		t.Fatalf("期望只配置 aiModel 时使用 ai 的权重 20，实际 %d", w)
+	}
+
+	// 热更新时重新解析的配置只修改了 ai 的权重，之前生成的 AiModels 保留
+	bc.AnalystWeights = map[analyst.AnalystType]int{
+		analyst.TypeAi:        5,
+		analyst.TypeHeuristic: 1,
+	}
+	bc.SetDefault()
+	bc.Reload()
+
+	if len(weights) != 1 || weights[0] != 5 {
+		t.Errorf("期望热更新后使用新的 ai 权重 5，实际 %v", weights)
+	}
+	if w := bc.AnalystWeightByName("HeuristicAnalyst", analyst.TypeHeuristic); w != 1 {
+		t.Errorf("期望其他分析器按类型的权重 1，实际 %d", w)
+	}
+}
+
+func TestAnalystWeightByName_AiModels(t *testing.T) {
+	bc := &BeautyConfig{
+		AiModels: []AiModelConfig{
+			{Name: "lite", Model: "lite-model", Weight: 15},
+			{Model: "pro-model", Weight: 5},
+		},
+	}
+	bc.SetDefault()
+
+	for _, c := range []struct {
+		name   string
+		weight int
+	}{
+		{"lite", 15},
+		{"pro-model", 5},
+		{"AiAnalyst", 0},
+	} {
+		if w := bc.AnalystWeightByName(c.name, analyst.TypeAi); w != c.weight {
+			t.Errorf("%v: 期望权重 %d，实际 %d", c.name, c.weight, w)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// File:		admin.go
+// Created by:	Hoven
+// Created on:	2025-05-22
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package admin
+
+import "time"
+
+type ChangeSource string
+
+const (
+	ChangeSourceStartup ChangeSource = "startup"
+	ChangeSourceConfig  ChangeSource = "config"
+	ChangeSourceAdmin   ChangeSource = "admin"
+)
+
+type WeightsChange struct {
+	Source       ChangeSource   `json:"source"`
+	OperatorId   int            `json:"operatorId,omitempty"`
+	OperatorName string         `json:"operatorName,omitempty"`
+	Weights      map[string]int `json:"weights"`
+	ChangedAt    time.Time      `json:"changedAt"`
+}
+
+type AnalystWeights struct {
+	Weights    map[string]int `json:"weights"`
+	LastChange *WeightsChange `json:"lastChange"`
+}
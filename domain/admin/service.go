@@ -0,0 +1,157 @@
+// File:		service.go
+// Created by:	Hoven
+// Created on:	2025-05-22
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package admin
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"github.com/yazl-tech/beauty-rating-server/domain/user"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+)
+
+// WeightSelector 支持运行时原子替换权重的分析器选择器
+type WeightSelector interface {
+	Weights() map[string]int
+	SetWeights(weights map[string]int) error
+}
+
+// ExperimentReporter 提供 A/B 实验各分组的效果统计
+type ExperimentReporter interface {
+	GetExperimentReport(ctx context.Context, experiment string) (*analysis.ExperimentReport, error)
+}
+
+type Service interface {
+	GetAnalystWeights(ctx context.Context, operator *user.User) (*AnalystWeights, error)
+	UpdateAnalystWeights(ctx context.Context, operator *user.User, weights map[string]int) (*AnalystWeights, error)
+	ApplyConfigWeights(weights map[string]int)
+	GetExperimentReport(ctx context.Context, operator *user.User, experiment string) (*analysis.ExperimentReport, error)
+}
+
+var _ Service = (*DefaultAdminService)(nil)
+
+type DefaultAdminService struct {
+	selector WeightSelector
+	reporter ExperimentReporter
+
+	mu         sync.Mutex
+	lastChange *WeightsChange
+	// configWeights 最近一次从配置读取到的权重，用于判断配置热更新时权重是否变化
+	configWeights map[string]int
+}
+
+func NewAdminService(selector WeightSelector, reporter ExperimentReporter) *DefaultAdminService {
+	weights := selector.Weights()
+
+	return &DefaultAdminService{
+		selector:      selector,
+		reporter:      reporter,
+		configWeights: weights,
+		lastChange: &WeightsChange{
+			Source:    ChangeSourceStartup,
+			Weights:   weights,
+			ChangedAt: time.Now(),
+		},
+	}
+}
+
+func (as *DefaultAdminService) checkAdmin(operator *user.User) error {
+	if operator == nil || !operator.Role.IsAdmin() {
+		return exception.ErrForbidden
+	}
+
+	return nil
+}
+
+func (as *DefaultAdminService) current() *AnalystWeights {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	return &AnalystWeights{
+		Weights:    as.selector.Weights(),
+		LastChange: as.lastChange,
+	}
+}
+
+func (as *DefaultAdminService) GetAnalystWeights(ctx context.Context, operator *user.User) (*AnalystWeights, error) {
+	if err := as.checkAdmin(operator); err != nil {
+		return nil, err
+	}
+
+	return as.current(), nil
+}
+
+func (as *DefaultAdminService) setWeights(change *WeightsChange) error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	if err := as.selector.SetWeights(change.Weights); err != nil {
+		return exception.Wrap(exception.ErrInvalidAnalystWeights.Code(), err)
+	}
+
+	change.Weights = as.selector.Weights()
+	as.lastChange = change
+	return nil
+}
+
+func (as *DefaultAdminService) UpdateAnalystWeights(ctx context.Context, operator *user.User, weights map[string]int) (*AnalystWeights, error) {
+	if err := as.checkAdmin(operator); err != nil {
+		return nil, err
+	}
+
+	err := as.setWeights(&WeightsChange{
+		Source:       ChangeSourceAdmin,
+		OperatorId:   operator.ID,
+		OperatorName: operator.Name,
+		Weights:      weights,
+		ChangedAt:    time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plog.Infoc(ctx, "analyst weights updated by admin: %v(%v), weights: %v", operator.Name, operator.ID, weights)
+	return as.current(), nil
+}
+
+// ApplyConfigWeights 在配置热更新时调用，仅当配置中的权重确实发生变化时才覆盖当前权重
+func (as *DefaultAdminService) ApplyConfigWeights(weights map[string]int) {
+	as.mu.Lock()
+	changed := !maps.Equal(as.configWeights, weights)
+	as.configWeights = weights
+	as.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	err := as.setWeights(&WeightsChange{
+		Source:    ChangeSourceConfig,
+		Weights:   weights,
+		ChangedAt: time.Now(),
+	})
+	if err != nil {
+		plog.Errorf("apply analyst weights from config: %v failed: %v", weights, err)
+		return
+	}
+
+	plog.Infof("analyst weights updated by config: %v", weights)
+}
+
+func (as *DefaultAdminService) GetExperimentReport(ctx context.Context, operator *user.User, experiment string) (*analysis.ExperimentReport, error) {
+	if err := as.checkAdmin(operator); err != nil {
+		return nil, err
+	}
+
+	return as.reporter.GetExperimentReport(ctx, experiment)
+}
@@ -0,0 +1,176 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"testing"
+
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"github.com/yazl-tech/beauty-rating-server/domain/user"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst/heuristic"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst/mock"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+)
+
+type fakeReporter struct{}
+
+func (fakeReporter) GetExperimentReport(_ context.Context, experiment string) (*analysis.ExperimentReport, error) {
+	return &analysis.ExperimentReport{Experiment: experiment}, nil
+}
+
+var (
+	admin    = &user.User{ID: 1, Name: "管理员", Role: user.RoleAdmin}
+	initial  = map[string]int{"MockAnalyst": 3, "HeuristicAnalyst": 1}
+	reversed = map[string]int{"MockAnalyst": 1, "HeuristicAnalyst": 3}
+)
+
+func newTestService() *DefaultAdminService {
+	selector := analyst.NewAnalystSelector(
+		analyst.WithAnalysts(mock.NewMockAnalyst(), initial["MockAnalyst"]),
+		analyst.WithAnalysts(heuristic.NewHeuristicAnalyst(), initial["HeuristicAnalyst"]),
+	)
+	return NewAdminService(selector, fakeReporter{})
+}
+
+func TestCheckAdmin(t *testing.T) {
+	as := newTestService()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name     string
+		operator *user.User
+		want     error
+	}{
+		{"未登录", nil, exception.ErrForbidden},
+		{"未知角色", &user.User{ID: 2, Role: user.RoleUnknown}, exception.ErrForbidden},
+		{"普通用户", &user.User{ID: 2, Role: user.RoleUser}, exception.ErrForbidden},
+		{"会员", &user.User{ID: 2, Role: user.RolePro}, exception.ErrForbidden},
+		{"管理员", admin, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := as.GetAnalystWeights(ctx, tc.operator); err != tc.want {
+				t.Errorf("获取权重期望 %v，实际: %v", tc.want, err)
+			}
+			if _, err := as.GetExperimentReport(ctx, tc.operator, "exp"); err != tc.want {
+				t.Errorf("获取实验报告期望 %v，实际: %v", tc.want, err)
+			}
+			if tc.want == nil {
+				return
+			}
+
+			if _, err := as.UpdateAnalystWeights(ctx, tc.operator, reversed); err != tc.want {
+				t.Errorf("修改权重期望 %v，实际: %v", tc.want, err)
+			}
+			if got := as.selector.Weights(); !maps.Equal(got, initial) {
+				t.Errorf("期望没有权限时权重不变，实际: %v", got)
+			}
+		})
+	}
+}
+
+func TestUpdateAnalystWeights(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name    string
+		weights map[string]int
+		want    map[string]int
+		invalid bool
+	}{
+		{"全部修改", reversed, reversed, false},
+		{"部分修改", map[string]int{"HeuristicAnalyst": 0}, map[string]int{"MockAnalyst": 3, "HeuristicAnalyst": 0}, false},
+		{"负数权重", map[string]int{"HeuristicAnalyst": -1}, initial, true},
+		{"未知分析器", map[string]int{"AiAnalyst": 1}, initial, true},
+		{"全部为零", map[string]int{"MockAnalyst": 0, "HeuristicAnalyst": 0}, initial, true},
+		// 其中一项不合法时其余项也不生效
+		{"部分不合法", map[string]int{"MockAnalyst": 5, "HeuristicAnalyst": -1}, initial, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			as := newTestService()
+			before := as.current().LastChange
+
+			ret, err := as.UpdateAnalystWeights(ctx, admin, tc.weights)
+			if tc.invalid {
+				var be *exception.BeautyException
+				if !errors.As(err, &be) || be.Code() != exception.ErrInvalidAnalystWeights.Code() {
+					t.Fatalf("期望权重不合法，实际: %v", err)
+				}
+				if last := as.current().LastChange; last != before {
+					t.Errorf("期望修改失败时不记录变更，实际: %+v", last)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("期望修改成功，实际错误: %v", err)
+				}
+				if last := ret.LastChange; last.Source != ChangeSourceAdmin || last.OperatorId != admin.ID || !maps.Equal(last.Weights, tc.want) {
+					t.Errorf("变更记录不符合预期: %+v", last)
+				}
+			}
+
+			if got := as.selector.Weights(); !maps.Equal(got, tc.want) {
+				t.Errorf("期望权重为 %v，实际: %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestApplyConfigWeights(t *testing.T) {
+	as := newTestService()
+
+	// 配置中的权重没有变化时不覆盖管理员的修改
+	if _, err := as.UpdateAnalystWeights(context.Background(), admin, reversed); err != nil {
+		t.Fatal(err)
+	}
+	as.ApplyConfigWeights(initial)
+	if got := as.current(); !maps.Equal(got.Weights, reversed) || got.LastChange.Source != ChangeSourceAdmin {
+		t.Errorf("期望配置未变化时保留管理员的修改，实际: %+v", got)
+	}
+
+	as.ApplyConfigWeights(map[string]int{"MockAnalyst": 2, "HeuristicAnalyst": 2})
+	if got := as.current(); got.Weights["MockAnalyst"] != 2 || got.LastChange.Source != ChangeSourceConfig {
+		t.Errorf("期望配置变化时使用配置中的权重，实际: %+v", got)
+	}
+
+	as.ApplyConfigWeights(map[string]int{"MockAnalyst": -1})
+	if got := as.current(); got.Weights["MockAnalyst"] != 2 || got.LastChange.Source != ChangeSourceConfig || got.LastChange.Weights["MockAnalyst"] != 2 {
+		t.Errorf("期望配置不合法时保留原权重，实际: %+v", got)
+	}
+}
+
+func TestUpdateAnalystWeights_Concurrent(t *testing.T) {
+	as := newTestService()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			weights := initial
+			if i%2 == 0 {
+				weights = reversed
+			}
+			for range 100 {
+				if _, err := as.UpdateAnalystWeights(ctx, admin, weights); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+
+	// 权重整体替换，读到的只能是某一次修改后的完整权重
+	for range 200 {
+		ret, err := as.GetAnalystWeights(ctx, admin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !maps.Equal(ret.Weights, initial) && !maps.Equal(ret.Weights, reversed) {
+			t.Fatalf("读到了不完整的权重: %v", ret.Weights)
+		}
+	}
+	wg.Wait()
+}
@@ -16,17 +16,39 @@ import (
 )
 
 type AnalysisDetail struct {
-	ID           int           `json:"id,omitempty"`
-	UserID       int           `json:"userId,omitempty"`
-	ImageUrl     string        `json:"imageUrl,omitempty"`
-	Score        int           `json:"score,omitempty"`
-	Percentile   int           `json:"percentile,omitempty"`
-	Date         time.Time     `json:"date,omitempty"`
-	Description  string        `json:"description,omitempty"`
-	Tags         []string      `json:"tags,omitempty"`
-	ScoreDetails []ScoreDetail `json:"scoreDetails,omitempty"`
-	IsFavorite   bool          `json:"isFavorite,omitempty"`
-	AnalyisType  int           `json:"analyisType"`
+	ID            int           `json:"id,omitempty"`
+	UserID        int           `json:"userId,omitempty"`
+	ImageUrl      string        `json:"imageUrl,omitempty"`
+	ThumbnailUrl  string        `json:"thumbnailUrl,omitempty"`
+	MediumUrl     string        `json:"mediumUrl,omitempty"`
+	Score         int           `json:"score,omitempty"`
+	Percentile    int           `json:"percentile"`
+	Date          time.Time     `json:"date,omitempty"`
+	Description   string        `json:"description,omitempty"`
+	Tags          []string      `json:"tags,omitempty"`
+	ScoreDetails  []ScoreDetail `json:"scoreDetails,omitempty"`
+	IsFavorite    bool          `json:"isFavorite,omitempty"`
+	AnalyisType   int           `json:"analyisType"`
+	Version       int           `json:"version,omitempty"`
+	Gender        int           `json:"-"`
+	AnalystName   string        `json:"-"`
+	IsFallback    bool          `json:"-"`
+	ImageHash     string        `json:"-"`
+	PromptVersion string        `json:"-"`
+	Model         string        `json:"-"`
+	HasVariants   bool          `json:"-"`
+	// ModerationStatus 审核结论，待复核的记录照常展示
+	ModerationStatus string `json:"-"`
+	ModerationReason string `json:"-"`
+	// Experiment 产出结果时用户所在的实验及分组，未开启实验时为空
+	Experiment string `json:"-"`
+	Variant    string `json:"-"`
+	// IsShared 用户是否分享过该报告
+	IsShared bool `json:"-"`
+	// JobID 由异步任务产出时为任务 id，同一任务只会产出一份报告，同步分析为 0
+	JobID int `json:"-"`
+	// Contributions 组合分析器各成员的结果，其他分析器产出的报告为空
+	Contributions []Contribution `json:"-"`
 }
 
 type ScoreDetail struct {
@@ -47,6 +69,31 @@ func parseAnalystDetails(d []analyst.Detail) []ScoreDetail {
 	return result
 }
 
+// Contribution 组合分析器中单个成员的结果，用于审计合并后的分数
+type Contribution struct {
+	AnalystName string `json:"analystName"`
+	AnalystType int    `json:"analystType"`
+	Weight      int    `json:"weight"`
+	Score       int    `json:"score,omitempty"`
+	Reference   bool   `json:"reference,omitempty"`
+	Err         string `json:"err,omitempty"`
+}
+
+func parseContributions(c []analyst.Contribution) []Contribution {
+	var result []Contribution
+	for _, contribution := range c {
+		result = append(result, Contribution{
+			AnalystName: contribution.AnalystName,
+			AnalystType: int(contribution.AnalystType),
+			Weight:      contribution.Weight,
+			Score:       contribution.Score,
+			Reference:   contribution.Reference,
+			Err:         contribution.Err,
+		})
+	}
+	return result
+}
+
 type ShareDetailToken struct {
 	DetailId int    `json:"detailId"`
 	Expires  int64  `json:"expires"`
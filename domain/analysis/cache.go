@@ -0,0 +1,78 @@
+// File:		cache.go
+// Created by:	Hoven
+// Created on:	2025-05-23
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/go-puzzles/puzzles/plog"
+)
+
+// ResultCacheScope 决定同一张图片的历史结果在什么范围内复用
+type ResultCacheScope string
+
+const (
+	ResultCacheOff    ResultCacheScope = "off"
+	ResultCacheUser   ResultCacheScope = "user"
+	ResultCacheGlobal ResultCacheScope = "global"
+)
+
+// HashImage 计算图片内容的 sha256，用于识别重复上传的同一张图片
+func HashImage(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// lookupCachedDetail 查找 TTL 内同一图片的历史分析结果，未命中或查询失败时返回 nil；
+// 只复用与调用者处于同一实验分组的结果，避免实验中的用户拿到其他分组分析器的结果
+func (as *DefaultAnalysisService) lookupCachedDetail(ctx context.Context, userId int, imageHash string) *AnalysisDetail {
+	scope := ResultCacheScope(as.beautyConf.ResultCacheScope)
+	if scope != ResultCacheUser && scope != ResultCacheGlobal {
+		return nil
+	}
+
+	ownerId := 0
+	if scope == ResultCacheUser {
+		ownerId = userId
+	}
+	since := time.Now().Add(-time.Duration(as.beautyConf.ResultCacheTTL) * time.Second)
+	_, experiment, variant := as.pickAnalyst(userId)
+
+	cached, err := as.repo.GetLatestDetailByHash(ctx, imageHash, ownerId, experiment, variant, since)
+	if err != nil {
+		plog.Warnc(ctx, "lookup cached detail by hash: %v failed: %v", imageHash, err)
+		return nil
+	}
+	if cached == nil {
+		return nil
+	}
+
+	plog.Debugc(ctx, "reuse analysis result of detail: %v for image hash: %v", cached.ID, imageHash)
+	return &AnalysisDetail{
+		Score:         cached.Score,
+		Percentile:    cached.Percentile,
+		Description:   cached.Description,
+		Tags:          cached.Tags,
+		ScoreDetails:  cached.ScoreDetails,
+		AnalyisType:   cached.AnalyisType,
+		AnalystName:   cached.AnalystName,
+		IsFallback:    cached.IsFallback,
+		PromptVersion: cached.PromptVersion,
+		Model:         cached.Model,
+		Contributions: cached.Contributions,
+
+		ModerationStatus: cached.ModerationStatus,
+		ModerationReason: cached.ModerationReason,
+		Experiment:       cached.Experiment,
+		Variant:          cached.Variant,
+	}
+}
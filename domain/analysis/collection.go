@@ -0,0 +1,412 @@
+// File:		collection.go
+// Created by:	Hoven
+// Created on:	2025-06-15
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysis
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/go-puzzles/puzzles/putils"
+	"github.com/pkg/errors"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultCollectionName 默认收藏夹，收藏和取消收藏报告操作的都是该收藏夹，第一次收藏时创建
+	DefaultCollectionName = "我的收藏"
+
+	maxCollectionNameLen = 32
+)
+
+// Collection 用户的收藏夹，一份报告可以加入多个收藏夹
+type Collection struct {
+	ID        int    `json:"id"`
+	UserID    int    `json:"userId,omitempty"`
+	Name      string `json:"name"`
+	IsDefault bool   `json:"isDefault"`
+	// CoverReportID 封面报告，为 0 时使用收藏夹中最新的报告
+	CoverReportID int       `json:"coverReportId"`
+	CoverUrl      string    `json:"coverUrl,omitempty"`
+	Position      int       `json:"position"`
+	ReportCount   int64     `json:"reportCount"`
+	Date          time.Time `json:"date"`
+}
+
+type CollectionItem struct {
+	CollectionID int
+	ReportID     int
+	UserID       int
+	Date         time.Time
+}
+
+// CollectionSummary 收藏夹的报告数量和封面报告，封面报告只包含 ID、ImageUrl 和 HasVariants
+type CollectionSummary struct {
+	CollectionID int
+	ReportCount  int64
+	Cover        *AnalysisDetail
+}
+
+// CollectionUpdate 修改收藏夹的名称或封面，为空的字段保持不变
+type CollectionUpdate struct {
+	Name          *string
+	CoverReportID *int
+}
+
+func normalizeCollectionName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxCollectionNameLen {
+		return "", exception.ErrInvalidCollectionName
+	}
+	if name == DefaultCollectionName {
+		return "", exception.ErrCollectionNameExists
+	}
+	return name, nil
+}
+
+func (as *DefaultAnalysisService) getUserCollection(ctx context.Context, userId, collectionId int) (*Collection, error) {
+	c, err := as.repo.GetUserCollection(ctx, userId, collectionId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.ErrCollectionNotFound
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "getUserCollection. userId=%v, collectionId=%v", userId, collectionId)
+	}
+
+	return c, nil
+}
+
+// defaultCollection 返回用户的默认收藏夹，create 为 false 且用户从未收藏过报告时返回 nil
+func (as *DefaultAnalysisService) defaultCollection(ctx context.Context, userId int, create bool) (*Collection, error) {
+	c, err := as.repo.GetDefaultCollection(ctx, userId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getDefaultCollection. userId=%v", userId)
+	}
+	if c != nil || !create {
+		return c, nil
+	}
+
+	c = &Collection{UserID: userId, Name: DefaultCollectionName, IsDefault: true, Date: time.Now()}
+	if err := as.repo.CreateCollection(ctx, c); err != nil {
+		// 并发收藏时可能已由其他请求创建
+		existing, getErr := as.repo.GetDefaultCollection(ctx, userId)
+		if getErr != nil || existing == nil {
+			return nil, errors.Wrapf(err, "createDefaultCollection. userId=%v", userId)
+		}
+		return existing, nil
+	}
+
+	return c, nil
+}
+
+// markFavorites 根据报告是否在默认收藏夹中设置 IsFavorite
+func (as *DefaultAnalysisService) markFavorites(ctx context.Context, userId int, details ...*AnalysisDetail) {
+	if len(details) == 0 {
+		return
+	}
+
+	c, err := as.defaultCollection(ctx, userId, false)
+	if err != nil {
+		plog.Warnc(ctx, "load default collection of user: %v failed: %v", userId, err)
+		return
+	}
+	if c == nil {
+		return
+	}
+
+	ids := putils.Convert(details, func(d *AnalysisDetail) int { return d.ID })
+	collected, err := as.repo.FilterCollectedReports(ctx, c.ID, ids)
+	if err != nil {
+		plog.Warnc(ctx, "filter favorite reports of user: %v failed: %v", userId, err)
+		return
+	}
+
+	for _, d := range details {
+		d.IsFavorite = slices.Contains(collected, d.ID)
+	}
+}
+
+func (as *DefaultAnalysisService) Favorite(ctx context.Context, userId int, detailId int) error {
+	if _, err := as.getUserDetail(ctx, userId, detailId); err != nil {
+		return err
+	}
+
+	c, err := as.defaultCollection(ctx, userId, true)
+	if err != nil {
+		return err
+	}
+
+	return as.repo.AddCollectionItem(ctx, &CollectionItem{
+		CollectionID: c.ID,
+		ReportID:     detailId,
+		UserID:       userId,
+		Date:         time.Now(),
+	})
+}
+
+func (as *DefaultAnalysisService) UnFavorite(ctx context.Context, userId int, detailId int) error {
+	if _, err := as.getUserDetail(ctx, userId, detailId); err != nil {
+		return err
+	}
+
+	c, err := as.defaultCollection(ctx, userId, false)
+	if err != nil || c == nil {
+		return err
+	}
+
+	return as.repo.RemoveCollectionItem(ctx, c.ID, detailId)
+}
+
+func (as *DefaultAnalysisService) GetFavoriteDetails(ctx context.Context, userId int, query *DetailQuery) (*DetailPage, error) {
+	c, err := as.defaultCollection(ctx, userId, false)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		if err := query.Normalize(); err != nil {
+			return nil, err
+		}
+		return &DetailPage{Details: []*AnalysisDetail{}}, nil
+	}
+
+	query.CollectionId = c.ID
+	return as.queryDetails(ctx, userId, query)
+}
+
+// fillCollections 填充收藏夹的报告数量和封面地址，封面优先使用缩略图
+func (as *DefaultAnalysisService) fillCollections(ctx context.Context, collections ...*Collection) {
+	if len(collections) == 0 {
+		return
+	}
+
+	ids := putils.Convert(collections, func(c *Collection) int { return c.ID })
+	summaries, err := as.repo.GetCollectionSummaries(ctx, ids)
+	if err != nil {
+		plog.Warnc(ctx, "load summaries of collections: %v failed: %v", ids, err)
+		return
+	}
+
+	byId := make(map[int]*CollectionSummary, len(summaries))
+	for _, s := range summaries {
+		byId[s.CollectionID] = s
+	}
+
+	for _, c := range collections {
+		s, ok := byId[c.ID]
+		if !ok {
+			continue
+		}
+		c.ReportCount = s.ReportCount
+		if s.Cover == nil {
+			continue
+		}
+
+		size := ImageOriginal
+		if s.Cover.HasVariants {
+			size = ImageThumb
+		}
+		if c.CoverUrl, err = as.imageUrl(ctx, s.Cover.ImageUrl, size); err != nil {
+			plog.Warnc(ctx, "presignedUrl: %v cover failed: %v", s.Cover.ImageUrl, err)
+		}
+	}
+}
+
+// GetCollections 默认收藏夹排在最前，其余按用户设置的顺序返回
+func (as *DefaultAnalysisService) GetCollections(ctx context.Context, userId int) ([]*Collection, error) {
+	collections, err := as.repo.GetUserCollections(ctx, userId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getUserCollections. userId=%v", userId)
+	}
+
+	as.fillCollections(ctx, collections...)
+	return collections, nil
+}
+
+func (as *DefaultAnalysisService) GetCollection(ctx context.Context, userId, collectionId int) (*Collection, error) {
+	c, err := as.getUserCollection(ctx, userId, collectionId)
+	if err != nil {
+		return nil, err
+	}
+
+	as.fillCollections(ctx, c)
+	return c, nil
+}
+
+// checkCollectionName 同一用户的收藏夹不能重名
+func (as *DefaultAnalysisService) checkCollectionName(collections []*Collection, name string, excludeId int) error {
+	for _, c := range collections {
+		if c.ID != excludeId && c.Name == name {
+			return exception.ErrCollectionNameExists
+		}
+	}
+	return nil
+}
+
+// CreateCollection 新建的收藏夹排在已有收藏夹之后
+func (as *DefaultAnalysisService) CreateCollection(ctx context.Context, userId int, name string) (*Collection, error) {
+	name, err := normalizeCollectionName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	collections, err := as.repo.GetUserCollections(ctx, userId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getUserCollections. userId=%v", userId)
+	}
+	if err := as.checkCollectionName(collections, name, 0); err != nil {
+		return nil, err
+	}
+
+	c := &Collection{UserID: userId, Name: name, Position: len(collections), Date: time.Now()}
+	if err := as.repo.CreateCollection(ctx, c); err != nil {
+		return nil, errors.Wrapf(err, "createCollection. userId=%v, name=%v", userId, name)
+	}
+
+	return c, nil
+}
+
+// UpdateCollection 默认收藏夹只能修改封面，封面报告必须在收藏夹中
+func (as *DefaultAnalysisService) UpdateCollection(ctx context.Context, userId, collectionId int, update *CollectionUpdate) (*Collection, error) {
+	c, err := as.getUserCollection(ctx, userId, collectionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil && *update.Name != c.Name {
+		if c.IsDefault {
+			return nil, exception.ErrDefaultCollection
+		}
+
+		name, err := normalizeCollectionName(*update.Name)
+		if err != nil {
+			return nil, err
+		}
+		collections, err := as.repo.GetUserCollections(ctx, userId)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getUserCollections. userId=%v", userId)
+		}
+		if err := as.checkCollectionName(collections, name, c.ID); err != nil {
+			return nil, err
+		}
+		c.Name = name
+	}
+
+	if update.CoverReportID != nil && *update.CoverReportID != 0 {
+		collected, err := as.repo.FilterCollectedReports(ctx, c.ID, []int{*update.CoverReportID})
+		if err != nil {
+			return nil, errors.Wrapf(err, "filterCollectedReports. collectionId=%v", c.ID)
+		}
+		if len(collected) == 0 {
+			return nil, exception.ErrCoverNotInCollection
+		}
+	}
+	if update.CoverReportID != nil {
+		c.CoverReportID = *update.CoverReportID
+	}
+
+	if err := as.repo.UpdateCollection(ctx, c); err != nil {
+		return nil, errors.Wrapf(err, "updateCollection. collectionId=%v", c.ID)
+	}
+
+	as.fillCollections(ctx, c)
+	return c, nil
+}
+
+// DeleteCollection 删除收藏夹及其中的收藏关系，报告本身不受影响
+func (as *DefaultAnalysisService) DeleteCollection(ctx context.Context, userId, collectionId int) error {
+	c, err := as.getUserCollection(ctx, userId, collectionId)
+	if err != nil {
+		return err
+	}
+	if c.IsDefault {
+		return exception.ErrDefaultCollection
+	}
+
+	return as.repo.DeleteCollection(ctx, c.ID)
+}
+
+// SortCollections 按 collectionIds 的顺序排列收藏夹，必须包含用户除默认收藏夹外的所有收藏夹
+func (as *DefaultAnalysisService) SortCollections(ctx context.Context, userId int, collectionIds []int) ([]*Collection, error) {
+	collections, err := as.repo.GetUserCollections(ctx, userId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getUserCollections. userId=%v", userId)
+	}
+
+	collections = slices.DeleteFunc(collections, func(c *Collection) bool { return c.IsDefault })
+	if len(collectionIds) != len(collections) {
+		return nil, exception.ErrInvalidCollectionSort
+	}
+
+	positions := make(map[int]int, len(collectionIds))
+	for i, id := range collectionIds {
+		positions[id] = i
+	}
+	for _, c := range collections {
+		if _, ok := positions[c.ID]; !ok {
+			return nil, exception.ErrInvalidCollectionSort
+		}
+	}
+
+	if err := as.repo.SortCollections(ctx, userId, positions); err != nil {
+		return nil, errors.Wrapf(err, "sortCollections. userId=%v", userId)
+	}
+
+	return as.GetCollections(ctx, userId)
+}
+
+func (as *DefaultAnalysisService) GetCollectionDetails(ctx context.Context, userId, collectionId int, query *DetailQuery) (*DetailPage, error) {
+	c, err := as.getUserCollection(ctx, userId, collectionId)
+	if err != nil {
+		return nil, err
+	}
+
+	query.CollectionId = c.ID
+	return as.queryDetails(ctx, userId, query)
+}
+
+// AddToCollection 报告已在收藏夹中时不做处理
+func (as *DefaultAnalysisService) AddToCollection(ctx context.Context, userId, collectionId, reportId int) error {
+	c, err := as.getUserCollection(ctx, userId, collectionId)
+	if err != nil {
+		return err
+	}
+	if _, err := as.getUserDetail(ctx, userId, reportId); err != nil {
+		return err
+	}
+
+	return as.repo.AddCollectionItem(ctx, &CollectionItem{
+		CollectionID: c.ID,
+		ReportID:     reportId,
+		UserID:       userId,
+		Date:         time.Now(),
+	})
+}
+
+// RemoveFromCollection 移出的报告是收藏夹的封面时，封面改为使用最新的报告
+func (as *DefaultAnalysisService) RemoveFromCollection(ctx context.Context, userId, collectionId, reportId int) error {
+	c, err := as.getUserCollection(ctx, userId, collectionId)
+	if err != nil {
+		return err
+	}
+
+	if err := as.repo.RemoveCollectionItem(ctx, c.ID, reportId); err != nil {
+		return err
+	}
+
+	if c.CoverReportID == reportId {
+		c.CoverReportID = 0
+		if err := as.repo.UpdateCollection(ctx, c); err != nil {
+			plog.Warnc(ctx, "reset cover of collection: %v failed: %v", c.ID, err)
+		}
+	}
+	return nil
+}
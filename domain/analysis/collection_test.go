@@ -0,0 +1,301 @@
+package analysis
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"gorm.io/gorm"
+)
+
+// memCollections 内存中的收藏夹存储
+type memCollections struct {
+	mu          sync.Mutex
+	collections []*Collection
+	items       []*CollectionItem
+}
+
+// collectedReports 返回在满足 match 的收藏夹中的报告 id
+func (r *memCollections) collectedReports(match func(c *Collection) bool) map[int]bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collected := make(map[int]bool)
+	for _, item := range r.items {
+		for _, c := range r.collections {
+			if c.ID == item.CollectionID && match(c) {
+				collected[item.ReportID] = true
+			}
+		}
+	}
+	return collected
+}
+
+func (r *memCollections) findCollections(match func(c *Collection) bool) []*Collection {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var found []*Collection
+	for _, c := range r.collections {
+		if match(c) {
+			cp := *c
+			found = append(found, &cp)
+		}
+	}
+	return found
+}
+
+func (r *memCollections) CreateCollection(_ context.Context, collection *Collection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.collections {
+		if c.UserID == collection.UserID && c.Name == collection.Name {
+			return fmt.Errorf("duplicate collection: %v", collection.Name)
+		}
+	}
+	collection.ID = len(r.collections) + 1
+	saved := *collection
+	r.collections = append(r.collections, &saved)
+	return nil
+}
+
+func (r *memCollections) GetUserCollection(_ context.Context, userId, collectionId int) (*Collection, error) {
+	found := r.findCollections(func(c *Collection) bool { return c.ID == collectionId && c.UserID == userId })
+	if len(found) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return found[0], nil
+}
+
+func (r *memCollections) GetDefaultCollection(_ context.Context, userId int) (*Collection, error) {
+	found := r.findCollections(func(c *Collection) bool { return c.UserID == userId && c.IsDefault })
+	if len(found) == 0 {
+		return nil, nil
+	}
+	return found[0], nil
+}
+
+func (r *memCollections) GetUserCollections(_ context.Context, userId int) ([]*Collection, error) {
+	found := r.findCollections(func(c *Collection) bool { return c.UserID == userId })
+	slices.SortFunc(found, func(a, b *Collection) int {
+		if a.IsDefault != b.IsDefault {
+			if a.IsDefault {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(cmp.Compare(a.Position, b.Position), cmp.Compare(a.ID, b.ID))
+	})
+	return found, nil
+}
+
+func (r *memCollections) UpdateCollection(_ context.Context, collection *Collection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.collections {
+		if c.ID == collection.ID {
+			c.Name = collection.Name
+			c.CoverReportID = collection.CoverReportID
+		}
+	}
+	return nil
+}
+
+func (r *memCollections) SortCollections(_ context.Context, userId int, positions map[int]int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.collections {
+		if position, ok := positions[c.ID]; ok && c.UserID == userId {
+			c.Position = position
+		}
+	}
+	return nil
+}
+
+func (r *memCollections) DeleteCollection(_ context.Context, collectionId int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items = slices.DeleteFunc(r.items, func(item *CollectionItem) bool { return item.CollectionID == collectionId })
+	r.collections = slices.DeleteFunc(r.collections, func(c *Collection) bool { return c.ID == collectionId })
+	return nil
+}
+
+func (r *memCollections) AddCollectionItem(_ context.Context, item *CollectionItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, i := range r.items {
+		if i.CollectionID == item.CollectionID && i.ReportID == item.ReportID {
+			return nil
+		}
+	}
+	saved := *item
+	r.items = append(r.items, &saved)
+	return nil
+}
+
+func (r *memCollections) RemoveCollectionItem(_ context.Context, collectionId, reportId int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items = slices.DeleteFunc(r.items, func(item *CollectionItem) bool {
+		return item.CollectionID == collectionId && item.ReportID == reportId
+	})
+	return nil
+}
+
+func (r *memCollections) FilterCollectedReports(_ context.Context, collectionId int, reportIds []int) ([]int, error) {
+	collected := r.collectedReports(func(c *Collection) bool { return c.ID == collectionId })
+	return slices.DeleteFunc(slices.Clone(reportIds), func(id int) bool { return !collected[id] }), nil
+}
+
+// GetCollectionSummaries 需要读取报告，由 memRepo 实现
+func (r *memRepo) GetCollectionSummaries(_ context.Context, collectionIds []int) ([]*CollectionSummary, error) {
+	var summaries []*CollectionSummary
+	for _, c := range r.findCollections(func(c *Collection) bool { return slices.Contains(collectionIds, c.ID) }) {
+		collected := r.collectedReports(func(cc *Collection) bool { return cc.ID == c.ID })
+		details := r.find(func(d *AnalysisDetail) bool { return collected[d.ID] })
+		if len(details) == 0 {
+			continue
+		}
+
+		cover := slices.MaxFunc(details, func(a, b *AnalysisDetail) int { return cmp.Compare(a.ID, b.ID) })
+		if i := slices.IndexFunc(details, func(d *AnalysisDetail) bool { return d.ID == c.CoverReportID }); i != -1 {
+			cover = details[i]
+		}
+		summaries = append(summaries, &CollectionSummary{CollectionID: c.ID, ReportCount: int64(len(details)), Cover: cover})
+	}
+	return summaries, nil
+}
+
+// removeReport 从所有收藏夹中移出被删除的报告
+func (r *memCollections) removeReport(reportId int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items = slices.DeleteFunc(r.items, func(item *CollectionItem) bool { return item.ReportID == reportId })
+}
+
+func TestCollections(t *testing.T) {
+	ts := newTestService(t)
+	ctx := context.Background()
+
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	for i := range 3 {
+		d := &AnalysisDetail{UserID: 1, ImageUrl: fmt.Sprintf("%d.jpg", i+1), HasVariants: true, Score: 80 + i, Date: base.AddDate(0, 0, i)}
+		if err := ts.repo.CreateAnalysisDetail(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	other := &AnalysisDetail{UserID: 2, Date: base}
+	if err := ts.repo.CreateAnalysisDetail(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+
+	// 从未收藏过时没有默认收藏夹，收藏列表为空
+	page, err := ts.GetFavoriteDetails(ctx, 1, &DetailQuery{})
+	if err != nil || len(page.Details) != 0 {
+		t.Fatalf("期望收藏列表为空，实际: %+v, %v", page, err)
+	}
+
+	if err := ts.Favorite(ctx, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Favorite(ctx, 1, 1); err != nil {
+		t.Fatalf("期望重复收藏不报错，实际: %v", err)
+	}
+	if err := ts.Favorite(ctx, 1, other.ID); err != exception.ErrDetailNotFound {
+		t.Errorf("期望不能收藏其他用户的报告，实际: %v", err)
+	}
+
+	travel, err := ts.CreateCollection(ctx, 1, " 旅行 ")
+	if err != nil || travel.Name != "旅行" || travel.Position != 1 {
+		t.Fatalf("创建收藏夹结果不符合预期: %+v, %v", travel, err)
+	}
+	portrait, err := ts.CreateCollection(ctx, 1, "证件照")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]error{
+		"旅行":                  exception.ErrCollectionNameExists,
+		DefaultCollectionName: exception.ErrCollectionNameExists,
+		"  ":                  exception.ErrInvalidCollectionName,
+	} {
+		if _, err := ts.CreateCollection(ctx, 1, name); err != want {
+			t.Errorf("创建收藏夹 %q 期望错误 %v，实际: %v", name, want, err)
+		}
+	}
+
+	for _, id := range []int{1, 2} {
+		if err := ts.AddToCollection(ctx, 1, travel.ID, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ts.AddToCollection(ctx, 2, travel.ID, other.ID); err != exception.ErrCollectionNotFound {
+		t.Errorf("期望不能操作其他用户的收藏夹，实际: %v", err)
+	}
+
+	page, err = ts.GetCollectionDetails(ctx, 1, travel.ID, &DetailQuery{})
+	if err != nil || len(page.Details) != 2 || page.Details[0].ID != 2 || page.Details[0].IsFavorite || !page.Details[1].IsFavorite {
+		t.Fatalf("收藏夹中的报告不符合预期: %+v, %v", page, err)
+	}
+
+	name, cover, uncollected := "旅行照", 1, 3
+	if _, err := ts.UpdateCollection(ctx, 1, travel.ID, &CollectionUpdate{CoverReportID: &uncollected}); err != exception.ErrCoverNotInCollection {
+		t.Errorf("期望封面必须在收藏夹中，实际: %v", err)
+	}
+	travel, err = ts.UpdateCollection(ctx, 1, travel.ID, &CollectionUpdate{Name: &name, CoverReportID: &cover})
+	if err != nil || travel.Name != "旅行照" || travel.ReportCount != 2 || !strings.Contains(travel.CoverUrl, "/analysis/image/1.jpg") {
+		t.Fatalf("修改收藏夹结果不符合预期: %+v, %v", travel, err)
+	}
+
+	// 移出封面报告后使用最新的报告作为封面
+	if err := ts.RemoveFromCollection(ctx, 1, travel.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+	travel, err = ts.GetCollection(ctx, 1, travel.ID)
+	if err != nil || travel.CoverReportID != 0 || travel.ReportCount != 1 || !strings.Contains(travel.CoverUrl, "/analysis/image/2.jpg") {
+		t.Fatalf("移出封面后收藏夹不符合预期: %+v, %v", travel, err)
+	}
+
+	collections, err := ts.SortCollections(ctx, 1, []int{portrait.ID, travel.ID})
+	if err != nil || len(collections) != 3 || !collections[0].IsDefault || collections[1].ID != portrait.ID || collections[2].ID != travel.ID {
+		t.Fatalf("排序后的收藏夹不符合预期: %+v, %v", collections, err)
+	}
+	if _, err := ts.SortCollections(ctx, 1, []int{travel.ID, travel.ID}); err != exception.ErrInvalidCollectionSort {
+		t.Errorf("期望排序必须包含所有收藏夹，实际: %v", err)
+	}
+
+	favorites := collections[0]
+	if _, err := ts.UpdateCollection(ctx, 1, favorites.ID, &CollectionUpdate{Name: &name}); err != exception.ErrDefaultCollection {
+		t.Errorf("期望默认收藏夹不能改名，实际: %v", err)
+	}
+	if err := ts.DeleteCollection(ctx, 1, favorites.ID); err != exception.ErrDefaultCollection {
+		t.Errorf("期望默认收藏夹不能删除，实际: %v", err)
+	}
+
+	if err := ts.DeleteCollection(ctx, 1, travel.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.GetCollection(ctx, 1, travel.ID); err != exception.ErrCollectionNotFound {
+		t.Errorf("期望删除后收藏夹不存在，实际: %v", err)
+	}
+
+	if err := ts.UnFavorite(ctx, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	page, err = ts.GetFavoriteDetails(ctx, 1, &DetailQuery{})
+	if err != nil || len(page.Details) != 0 {
+		t.Errorf("期望取消收藏后收藏列表为空，实际: %+v, %v", page, err)
+	}
+}
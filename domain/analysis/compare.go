@@ -0,0 +1,303 @@
+// File:		compare.go
+// Created by:	Hoven
+// Created on:	2025-06-11
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysis
+
+import (
+	"context"
+	"crypto/hmac"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/pkg/errors"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"gorm.io/gorm"
+)
+
+// LabelDelta 同一评分项在前后两张照片中的变化
+type LabelDelta struct {
+	Label  string `json:"label"`
+	Before int    `json:"before"`
+	After  int    `json:"after"`
+	Delta  int    `json:"delta"`
+}
+
+// Comparison 前后两份报告的对比，分数及变化在对比时计算并保存，之后报告重新分析不影响对比结果
+type Comparison struct {
+	ID          int             `json:"id,omitempty"`
+	UserID      int             `json:"userId,omitempty"`
+	BeforeID    int             `json:"beforeId"`
+	AfterID     int             `json:"afterId"`
+	Before      *AnalysisDetail `json:"before,omitempty"`
+	After       *AnalysisDetail `json:"after,omitempty"`
+	BeforeScore int             `json:"beforeScore"`
+	AfterScore  int             `json:"afterScore"`
+	ScoreDelta  int             `json:"scoreDelta"`
+	LabelDeltas []LabelDelta    `json:"labelDeltas"`
+	Narrative   string          `json:"narrative"`
+	// IsFavorite 对比单独标记收藏：收藏夹的条目、封面和报告数量都以报告为单位，对比不是报告，不放入收藏夹
+	IsFavorite bool      `json:"isFavorite,omitempty"`
+	Date       time.Time `json:"date,omitempty"`
+	IsShared   bool      `json:"-"`
+}
+
+type ShareCompareToken struct {
+	CompareId int    `json:"compareId"`
+	Expires   int64  `json:"expires"`
+	Sig       string `json:"sig"`
+}
+
+func (st *ShareCompareToken) String() string {
+	return fmt.Sprintf("compareId=%d&expires=%d&sig=%s", st.CompareId, st.Expires, st.Sig)
+}
+
+// labelDeltas 按 before 中的顺序计算两份报告都有的评分项的变化
+func labelDeltas(before, after []ScoreDetail) []LabelDelta {
+	afterScores := make(map[string]int, len(after))
+	for _, d := range after {
+		afterScores[d.Label] = d.Score
+	}
+
+	deltas := make([]LabelDelta, 0, len(before))
+	for _, d := range before {
+		score, ok := afterScores[d.Label]
+		if !ok {
+			continue
+		}
+		deltas = append(deltas, LabelDelta{
+			Label:  d.Label,
+			Before: d.Score,
+			After:  score,
+			Delta:  score - d.Score,
+		})
+	}
+	return deltas
+}
+
+// compareNarrative 根据分数变化生成对比描述
+func compareNarrative(c *Comparison) string {
+	var sb strings.Builder
+	switch {
+	case c.ScoreDelta > 0:
+		fmt.Fprintf(&sb, "整体评分从 %d 分提升到 %d 分，提高了 %d 分。", c.BeforeScore, c.AfterScore, c.ScoreDelta)
+	case c.ScoreDelta < 0:
+		fmt.Fprintf(&sb, "整体评分从 %d 分变为 %d 分，降低了 %d 分。", c.BeforeScore, c.AfterScore, -c.ScoreDelta)
+	default:
+		fmt.Fprintf(&sb, "整体评分保持在 %d 分。", c.AfterScore)
+	}
+
+	var best, worst *LabelDelta
+	for i := range c.LabelDeltas {
+		d := &c.LabelDeltas[i]
+		if d.Delta > 0 && (best == nil || d.Delta > best.Delta) {
+			best = d
+		}
+		if d.Delta < 0 && (worst == nil || d.Delta < worst.Delta) {
+			worst = d
+		}
+	}
+
+	switch {
+	case best == nil && worst == nil:
+		sb.WriteString("各项评分没有明显变化，两张照片的状态很接近。")
+	case worst == nil:
+		fmt.Fprintf(&sb, "%s的变化最明显，提高了 %d 分，其他方面也没有退步。", best.Label, best.Delta)
+	case best == nil:
+		fmt.Fprintf(&sb, "%s下降最多，降低了 %d 分，可以参考之前的照片调整。", worst.Label, -worst.Delta)
+	default:
+		fmt.Fprintf(&sb, "%s的提升最明显，提高了 %d 分；%s降低了 %d 分，还有调整空间。", best.Label, best.Delta, worst.Label, -worst.Delta)
+	}
+
+	return sb.String()
+}
+
+func newComparison(userId int, before, after *AnalysisDetail) *Comparison {
+	c := &Comparison{
+		UserID:      userId,
+		BeforeID:    before.ID,
+		AfterID:     after.ID,
+		BeforeScore: before.Score,
+		AfterScore:  after.Score,
+		ScoreDelta:  after.Score - before.Score,
+		LabelDeltas: labelDeltas(before.ScoreDetails, after.ScoreDetails),
+		Date:        time.Now(),
+	}
+	c.Narrative = compareNarrative(c)
+	return c
+}
+
+// Compare 对比用户的两份报告，使用报告当前主版本的结果
+func (as *DefaultAnalysisService) Compare(ctx context.Context, userId, beforeId, afterId int) (*Comparison, error) {
+	if beforeId == afterId {
+		return nil, exception.ErrCompareSameReport
+	}
+
+	before, err := as.getUserDetail(ctx, userId, beforeId)
+	if err != nil {
+		return nil, err
+	}
+	after, err := as.getUserDetail(ctx, userId, afterId)
+	if err != nil {
+		return nil, err
+	}
+
+	c := newComparison(userId, before, after)
+	if err := as.repo.CreateComparison(ctx, c); err != nil {
+		return nil, err
+	}
+
+	c.Before = as.convertImage(ctx, before)
+	c.After = as.convertImage(ctx, after)
+	return c, nil
+}
+
+func (as *DefaultAnalysisService) comparedDetail(ctx context.Context, compareId, detailId int) *AnalysisDetail {
+	detail, err := as.repo.GetDetail(ctx, detailId)
+	if err != nil {
+		plog.Warnc(ctx, "load detail: %v of comparison: %v failed: %v", detailId, compareId, err)
+		return nil
+	}
+
+	return as.convertImage(ctx, detail)
+}
+
+// fillComparison 加载对比的前后报告，已删除的报告保持为空
+func (as *DefaultAnalysisService) fillComparison(ctx context.Context, c *Comparison) *Comparison {
+	c.Before = as.comparedDetail(ctx, c.ID, c.BeforeID)
+	c.After = as.comparedDetail(ctx, c.ID, c.AfterID)
+	return c
+}
+
+func (as *DefaultAnalysisService) getUserComparison(ctx context.Context, userId, compareId int) (*Comparison, error) {
+	c, err := as.repo.GetUserComparison(ctx, userId, compareId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.ErrComparisonNotFound
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "getUserComparison. userId=%v, compareId=%v", userId, compareId)
+	}
+
+	return c, nil
+}
+
+func (as *DefaultAnalysisService) GetComparison(ctx context.Context, userId, compareId int) (*Comparison, error) {
+	c, err := as.getUserComparison(ctx, userId, compareId)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.fillComparison(ctx, c), nil
+}
+
+// fillComparisons 一次查询加载所有对比的前后报告，已删除的报告保持为空
+func (as *DefaultAnalysisService) fillComparisons(ctx context.Context, comparisons []*Comparison) []*Comparison {
+	detailIds := make([]int, 0, 2*len(comparisons))
+	for _, c := range comparisons {
+		detailIds = append(detailIds, c.BeforeID, c.AfterID)
+	}
+
+	details, err := as.repo.GetDetails(ctx, detailIds)
+	if err != nil {
+		plog.Warnc(ctx, "load details of comparisons failed: %v", err)
+	}
+
+	detailMap := make(map[int]*AnalysisDetail, len(details))
+	for _, d := range as.convertImages(ctx, details) {
+		if d != nil {
+			detailMap[d.ID] = d
+		}
+	}
+
+	for _, c := range comparisons {
+		c.Before, c.After = detailMap[c.BeforeID], detailMap[c.AfterID]
+	}
+	return comparisons
+}
+
+// GetComparisons 多查询一条用于判断是否还有下一页，只加载当前页的前后报告
+func (as *DefaultAnalysisService) GetComparisons(ctx context.Context, userId int, query *ComparisonQuery) (*ComparisonPage, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	q := *query
+	q.Limit++
+	comparisons, err := as.repo.GetUserComparisons(ctx, userId, &q)
+	if err != nil {
+		return nil, err
+	}
+
+	page := new(ComparisonPage)
+	if len(comparisons) > query.Limit {
+		comparisons = comparisons[:query.Limit]
+		page.NextCursor = query.cursorOf(comparisons[len(comparisons)-1]).Encode()
+	}
+	page.Comparisons = as.fillComparisons(ctx, comparisons)
+
+	return page, nil
+}
+
+func (as *DefaultAnalysisService) setComparisonFavorite(ctx context.Context, userId, compareId int, favorite bool) error {
+	c, err := as.getUserComparison(ctx, userId, compareId)
+	if err != nil {
+		return err
+	}
+
+	c.IsFavorite = favorite
+	return as.repo.UpdateComparison(ctx, c)
+}
+
+func (as *DefaultAnalysisService) FavoriteComparison(ctx context.Context, userId, compareId int) error {
+	return as.setComparisonFavorite(ctx, userId, compareId, true)
+}
+
+func (as *DefaultAnalysisService) UnFavoriteComparison(ctx context.Context, userId, compareId int) error {
+	return as.setComparisonFavorite(ctx, userId, compareId, false)
+}
+
+func (as *DefaultAnalysisService) ShareComparison(ctx context.Context, userId, compareId int) (*ShareCompareToken, error) {
+	c, err := as.getUserComparison(ctx, userId, compareId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.IsShared {
+		c.IsShared = true
+		if err := as.repo.UpdateComparison(ctx, c); err != nil {
+			plog.Warnc(ctx, "mark comparison: %v shared failed: %v", compareId, err)
+		}
+	}
+
+	expires := time.Now().Add(24 * time.Hour).Unix()
+	return &ShareCompareToken{
+		CompareId: compareId,
+		Expires:   expires,
+		Sig:       as.signShare(fmt.Sprintf("compare/%d/%d", compareId, expires)),
+	}, nil
+}
+
+func (as *DefaultAnalysisService) GetShareComparison(ctx context.Context, token *ShareCompareToken) (*Comparison, error) {
+	if time.Now().Unix() > token.Expires {
+		return nil, exception.ErrShareExpires
+	}
+
+	expectedSig := as.signShare(fmt.Sprintf("compare/%d/%d", token.CompareId, token.Expires))
+	if !hmac.Equal([]byte(expectedSig), []byte(token.Sig)) {
+		return nil, exception.ErrShareTokenInvalidates
+	}
+
+	c, err := as.repo.GetComparison(ctx, token.CompareId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.ErrComparisonNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return as.fillComparison(ctx, c), nil
+}
@@ -0,0 +1,207 @@
+package analysis
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"github.com/yazl-tech/beauty-rating-server/pkg/fakebot"
+	"gorm.io/gorm"
+)
+
+// memComparisons 内存中的对比报告存储
+type memComparisons struct {
+	mu          sync.Mutex
+	comparisons []*Comparison
+}
+
+func (r *memComparisons) CreateComparison(_ context.Context, comparison *Comparison) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	comparison.ID = len(r.comparisons) + 1
+	saved := *comparison
+	r.comparisons = append(r.comparisons, &saved)
+	return nil
+}
+
+func (r *memComparisons) findComparison(match func(c *Comparison) bool) []*Comparison {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var found []*Comparison
+	for i := len(r.comparisons) - 1; i >= 0; i-- {
+		if match(r.comparisons[i]) {
+			cp := *r.comparisons[i]
+			found = append(found, &cp)
+		}
+	}
+	return found
+}
+
+func (r *memComparisons) GetComparison(_ context.Context, compareId int) (*Comparison, error) {
+	found := r.findComparison(func(c *Comparison) bool { return c.ID == compareId })
+	if len(found) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return found[0], nil
+}
+
+func (r *memComparisons) GetUserComparison(_ context.Context, userId, compareId int) (*Comparison, error) {
+	found := r.findComparison(func(c *Comparison) bool { return c.ID == compareId && c.UserID == userId })
+	if len(found) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return found[0], nil
+}
+
+func (r *memComparisons) GetUserComparisons(_ context.Context, userId int, query *ComparisonQuery) ([]*Comparison, error) {
+	found := r.findComparison(func(c *Comparison) bool {
+		return c.UserID == userId && (!query.FavoriteOnly || c.IsFavorite) && (query.After == nil || c.ID < query.After.ID)
+	})
+	return found[:min(len(found), query.Limit)], nil
+}
+
+func (r *memComparisons) UpdateComparison(_ context.Context, comparison *Comparison) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.comparisons {
+		if c.ID == comparison.ID {
+			c.IsFavorite = comparison.IsFavorite
+			c.IsShared = comparison.IsShared
+		}
+	}
+	return nil
+}
+
+func TestCompare(t *testing.T) {
+	ts := newTestService(t)
+	ctx := context.Background()
+
+	before := &AnalysisDetail{UserID: 1, Score: 80, ScoreDetails: []ScoreDetail{
+		{Label: "五官", Score: 85}, {Label: "妆容", Score: 75}, {Label: "发型", Score: 80},
+	}}
+	after := &AnalysisDetail{UserID: 1, Score: 86, ScoreDetails: []ScoreDetail{
+		{Label: "五官", Score: 85}, {Label: "妆容", Score: 90}, {Label: "发型", Score: 78},
+	}}
+	for _, d := range []*AnalysisDetail{before, after} {
+		if err := ts.repo.CreateAnalysisDetail(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, err := ts.Compare(ctx, 1, before.ID, after.ID)
+	if err != nil {
+		t.Fatalf("期望对比成功，实际错误: %v", err)
+	}
+	if c.ScoreDelta != 6 || len(c.LabelDeltas) != 3 || c.LabelDeltas[1].Delta != 15 || c.LabelDeltas[2].Delta != -2 {
+		t.Errorf("分数变化不符合预期: %+v", c)
+	}
+	if !strings.Contains(c.Narrative, "提高了 6 分") || !strings.Contains(c.Narrative, "妆容的提升最明显") {
+		t.Errorf("对比描述不符合预期: %v", c.Narrative)
+	}
+
+	if err := ts.FavoriteComparison(ctx, 1, c.ID); err != nil {
+		t.Fatal(err)
+	}
+	favorites, err := ts.GetComparisons(ctx, 1, &ComparisonQuery{FavoriteOnly: true})
+	if err != nil || len(favorites.Comparisons) != 1 || favorites.Comparisons[0].Before == nil || favorites.Comparisons[0].After == nil {
+		t.Fatalf("收藏的对比不符合预期: %+v, %v", favorites, err)
+	}
+
+	token, err := ts.ShareComparison(ctx, 1, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared, err := ts.GetShareComparison(ctx, token)
+	if err != nil || shared.ID != c.ID {
+		t.Fatalf("分享的对比不符合预期: %+v, %v", shared, err)
+	}
+	if _, err := ts.GetShareDetail(ctx, &ShareDetailToken{DetailId: token.CompareId, Expires: token.Expires, Sig: token.Sig}); err == nil {
+		t.Error("对比的分享签名不应能用于获取报告")
+	}
+
+	if _, err := ts.Compare(ctx, 2, before.ID, after.ID); err != exception.ErrDetailNotFound {
+		t.Errorf("期望不能对比其他用户的报告，实际 %v", err)
+	}
+}
+
+func TestDiscardAnalysis(t *testing.T) {
+	ts := newTestService(t, fakebot.WithReplies(fakebot.Reply{Content: aiContent}))
+	ctx := context.Background()
+
+	imageId, b, err := ts.images.Upload(ctx, "a.jpg", testImage(t, 300, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+	detail, err := ts.DoAnalysis(ctx, 1, 1, imageId, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.DiscardAnalysis(ctx, 2, detail.ID); err != exception.ErrDetailNotFound {
+		t.Errorf("期望不能放弃其他用户的报告，实际 %v", err)
+	}
+	if err := ts.DiscardAnalysis(ctx, 1, detail.ID); err != nil {
+		t.Fatalf("期望放弃报告成功，实际错误: %v", err)
+	}
+	if ts.repo.CheckDetailExists(ctx, 1, detail.ID) {
+		t.Error("期望放弃后报告不存在")
+	}
+	if ts.oss.Len() != 0 {
+		t.Errorf("期望放弃的报告的原图和缩略图从 oss 删除，剩余 %d 个对象", ts.oss.Len())
+	}
+}
+
+func TestGetComparisons_Paginate(t *testing.T) {
+	ts := newTestService(t)
+	ctx := context.Background()
+
+	var ids []int
+	for _, score := range []int{70, 80, 90} {
+		d := &AnalysisDetail{UserID: 1, Score: score}
+		if err := ts.repo.CreateAnalysisDetail(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, d.ID)
+	}
+	for _, pair := range [][2]int{{ids[0], ids[1]}, {ids[1], ids[2]}, {ids[0], ids[2]}} {
+		if _, err := ts.Compare(ctx, 1, pair[0], pair[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ts.repo.DeleteAnalysisDetail(ctx, 1, ids[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := ts.GetComparisons(ctx, 1, &ComparisonQuery{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Comparisons) != 2 || page.NextCursor == "" {
+		t.Fatalf("期望第一页 2 条且有下一页，实际: %+v", page)
+	}
+	latest := page.Comparisons[0]
+	if latest.BeforeID != ids[0] || latest.Before != nil || latest.After == nil || latest.After.Score != 90 {
+		t.Errorf("期望已删除的报告为空、其余报告正常加载，实际: %+v", latest)
+	}
+
+	after, err := ParseDetailCursor(page.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := ts.GetComparisons(ctx, 1, &ComparisonQuery{Limit: 2, After: after})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next.Comparisons) != 1 || next.NextCursor != "" || next.Comparisons[0].BeforeID != ids[0] || next.Comparisons[0].AfterID != ids[1] {
+		t.Errorf("期望第二页只有最早的一条对比，实际: %+v", next)
+	}
+
+	if _, err := ts.GetComparisons(ctx, 1, &ComparisonQuery{After: &DetailCursor{SortBy: SortByScore, ID: 1}}); err != exception.ErrInvalidCursor {
+		t.Errorf("期望按分数排序的游标不能用于对比列表，实际 %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+// File:		experiment.go
+// Created by:	Hoven
+// Created on:	2025-06-06
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysis
+
+import (
+	"context"
+	"math"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+)
+
+// VariantStats 实验分组的效果统计，降级到后备分析器的记录不计入分组的效果，只统计在 Fallbacks 中
+type VariantStats struct {
+	Variant      string  `json:"variant"`
+	Total        int64   `json:"total"`
+	AvgScore     float64 `json:"avgScore"`
+	Favorites    int64   `json:"favorites"`
+	Shares       int64   `json:"shares"`
+	FavoriteRate float64 `json:"favoriteRate"`
+	ShareRate    float64 `json:"shareRate"`
+	Fallbacks    int64   `json:"fallbacks"`
+}
+
+func round(v float64, precision int) float64 {
+	p := math.Pow10(precision)
+	return math.Round(v*p) / p
+}
+
+func (vs *VariantStats) calcRates() {
+	vs.AvgScore = round(vs.AvgScore, 2)
+	if vs.Total == 0 {
+		return
+	}
+
+	vs.FavoriteRate = round(float64(vs.Favorites)/float64(vs.Total), 4)
+	vs.ShareRate = round(float64(vs.Shares)/float64(vs.Total), 4)
+}
+
+type ExperimentReport struct {
+	Experiment string `json:"experiment"`
+	// Running 是否为当前正在进行的实验
+	Running  bool            `json:"running"`
+	Variants []*VariantStats `json:"variants"`
+}
+
+// pickAnalyst 开启实验时使用用户所在分组的分析器，否则使用默认的分析器
+func (as *DefaultAnalysisService) pickAnalyst(userId int) (a analyst.Analyst, experiment, variant string) {
+	if as.experiment == nil {
+		return as.analyst, "", ""
+	}
+
+	v := as.experiment.Assign(userId)
+	return v.Analyst, as.experiment.Key(), v.Name
+}
+
+// GetExperimentReport 汇总实验各分组的平均分、收藏率和分享率，当前实验中还没有数据的分组也会列出
+func (as *DefaultAnalysisService) GetExperimentReport(ctx context.Context, experiment string) (*ExperimentReport, error) {
+	stats, err := as.repo.GetVariantStats(ctx, experiment)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ExperimentReport{
+		Experiment: experiment,
+		Running:    as.experiment != nil && as.experiment.Key() == experiment,
+		Variants:   stats,
+	}
+
+	if report.Running {
+		seen := make(map[string]bool, len(stats))
+		for _, s := range stats {
+			seen[s.Variant] = true
+		}
+		for _, v := range as.experiment.Variants() {
+			if !seen[v.Name] {
+				report.Variants = append(report.Variants, &VariantStats{Variant: v.Name})
+			}
+		}
+	}
+
+	for _, s := range report.Variants {
+		s.calcRates()
+	}
+	return report, nil
+}
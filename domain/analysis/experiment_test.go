@@ -0,0 +1,136 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst/mock"
+	"github.com/yazl-tech/beauty-rating-server/pkg/experiment"
+)
+
+func (r *memRepo) MarkShared(_ context.Context, detailId int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range r.details {
+		if d.ID == detailId {
+			d.IsShared = true
+		}
+	}
+	return nil
+}
+
+func (r *memRepo) GetVariantStats(_ context.Context, experiment string) ([]*VariantStats, error) {
+	var (
+		stats     []*VariantStats
+		byName    = make(map[string]*VariantStats)
+		favorites = r.collectedReports(func(c *Collection) bool { return c.IsDefault })
+	)
+	for _, d := range r.find(func(d *AnalysisDetail) bool { return d.Experiment == experiment }) {
+		vs, ok := byName[d.Variant]
+		if !ok {
+			vs = &VariantStats{Variant: d.Variant}
+			byName[d.Variant] = vs
+			stats = append(stats, vs)
+		}
+		if d.IsFallback {
+			vs.Fallbacks++
+			continue
+		}
+		vs.AvgScore = (vs.AvgScore*float64(vs.Total) + float64(d.Score)) / float64(vs.Total+1)
+		vs.Total++
+		if favorites[d.ID] {
+			vs.Favorites++
+		}
+		if d.IsShared {
+			vs.Shares++
+		}
+	}
+	return stats, nil
+}
+
+// newExperimentService 返回开启了 a/b 两个分组实验的测试服务，以及分别落在两个分组中的用户
+func newExperimentService(t *testing.T) (ts *testService, userA, userB int) {
+	ts = newTestService(t)
+
+	exp, err := experiment.NewExperiment("exp",
+		&experiment.Variant{Name: "a", Weight: 1, Analyst: mock.NewMockAnalyst(mock.WithSalt("a"))},
+		&experiment.Variant{Name: "b", Weight: 1, Analyst: mock.NewMockAnalyst(mock.WithSalt("b"))},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts.experiment = exp
+
+	for userId := 1; userA == 0 || userB == 0; userId++ {
+		if exp.Assign(userId).Name == "a" {
+			userA = userId
+		} else {
+			userB = userId
+		}
+	}
+	return ts, userA, userB
+}
+
+func TestDoAnalysis_ExperimentCache(t *testing.T) {
+	ts, userA, userB := newExperimentService(t)
+	ts.beautyConf.ResultCacheScope = string(ResultCacheGlobal)
+	ctx := context.Background()
+
+	imageId, b, err := ts.images.Upload(ctx, "a.jpg", testImage(t, 300, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := ts.DoAnalysis(ctx, userA, 1, imageId, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ts.DoAnalysis(ctx, userB, 1, imageId, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Variant != "a" || second.Variant != "b" {
+		t.Errorf("期望不同分组的用户不复用彼此的结果，实际 %v, %v", first.Variant, second.Variant)
+	}
+
+	want, err := mock.NewMockAnalyst(mock.WithSalt("b")).DoAnalysis(ctx, imageId, imageId, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Score != want.Score {
+		t.Errorf("期望 b 组用户得到 b 组分析器的结果 %d，实际 %d", want.Score, second.Score)
+	}
+}
+
+func TestGetExperimentReport_Fallback(t *testing.T) {
+	ts, _, _ := newExperimentService(t)
+	ctx := context.Background()
+
+	for _, d := range []*AnalysisDetail{
+		{Experiment: "exp", Variant: "a", Score: 80, IsShared: true},
+		{Experiment: "exp", Variant: "a", Score: 90},
+		{Experiment: "exp", Variant: "a", Score: 60, IsShared: true, IsFallback: true},
+		{Experiment: "exp", Variant: "b", Score: 70, IsFallback: true},
+	} {
+		if err := ts.repo.CreateAnalysisDetail(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := ts.GetExperimentReport(ctx, "exp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Running || len(report.Variants) != 2 {
+		t.Fatalf("实验报告不符合预期: %+v", report)
+	}
+
+	a, b := report.Variants[0], report.Variants[1]
+	if a.Total != 2 || a.AvgScore != 85 || a.Shares != 1 || a.ShareRate != 0.5 || a.Fallbacks != 1 {
+		t.Errorf("a 组期望排除降级记录，实际 %+v", a)
+	}
+	if b.Total != 0 || b.AvgScore != 0 || b.Fallbacks != 1 {
+		t.Errorf("b 组期望只有降级记录，实际 %+v", b)
+	}
+}
@@ -0,0 +1,214 @@
+// File:		image.go
+// Created by:	Hoven
+// Created on:	2025-06-01
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/pkg/errors"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"github.com/yazl-tech/beauty-rating-server/pkg/imageproc"
+	"github.com/yazl-tech/beauty-rating-server/pkg/oss"
+)
+
+type ImageSize string
+
+const (
+	ImageOriginal ImageSize = "original"
+	ImageMedium   ImageSize = "medium"
+	ImageThumb    ImageSize = "thumb"
+)
+
+// imageVariants 上传时与原图一起生成的尺寸变体及其最大边长
+var imageVariants = []struct {
+	size         ImageSize
+	maxDimension int
+}{
+	{size: ImageThumb, maxDimension: 256},
+	{size: ImageMedium, maxDimension: 1024},
+}
+
+// ParseImageSize 未知的尺寸按原图处理
+func ParseImageSize(s string) ImageSize {
+	switch size := ImageSize(s); size {
+	case ImageThumb, ImageMedium:
+		return size
+	default:
+		return ImageOriginal
+	}
+}
+
+// ImageStore 管理分析图片及其尺寸变体在 oss 中的存储
+//
+// 原图存储为 {dir}/{imageId}，变体存储为 {dir}/{size}/{imageId}，imageId 由 oss 上传时生成。
+type ImageStore struct {
+	oss       oss.IOSS
+	processor *imageproc.Processor
+	dir       string
+}
+
+func NewImageStore(oss oss.IOSS, processor *imageproc.Processor) *ImageStore {
+	return &ImageStore{
+		oss:       oss,
+		processor: processor,
+		dir:       "analysis",
+	}
+}
+
+func (is *ImageStore) objectName(imageId string, size ImageSize) string {
+	if size == ImageOriginal {
+		return fmt.Sprintf("%s/%s", is.dir, imageId)
+	}
+	return fmt.Sprintf("%s/%s/%s", is.dir, size, imageId)
+}
+
+// Upload 预处理上传的图片后存储原图和所有尺寸变体，返回 imageId 和存储的原图内容，供后续分析使用
+func (is *ImageStore) Upload(ctx context.Context, filename string, raw []byte) (string, []byte, error) {
+	img, err := is.processor.Process(raw)
+	if errors.Is(err, imageproc.ErrNotImage) {
+		plog.Warnc(ctx, "reject upload: %v, err: %v", filename, err)
+		return "", nil, exception.ErrInvalidImage
+	} else if errors.Is(err, imageproc.ErrImageTooLarge) {
+		plog.Warnc(ctx, "reject upload: %v, err: %v", filename, err)
+		return "", nil, exception.ErrImageTooLarge
+	} else if err != nil {
+		return "", nil, errors.Wrap(err, "processAnalysisImage")
+	}
+	plog.Debugc(ctx, "preprocess image: %v, %d bytes -> %v %dx%d %d bytes",
+		filename, len(raw), img.Format, img.Width, img.Height, len(img.Data))
+
+	objName := strings.TrimSuffix(filename, filepath.Ext(filename)) + img.Ext()
+	imageId, err := is.oss.UploadFile(ctx, int64(len(img.Data)), is.dir, objName, bytes.NewReader(img.Data))
+	if err != nil {
+		return "", nil, errors.Wrap(err, "uploadAnalysisImage")
+	}
+
+	if err := is.putVariants(ctx, imageId, img); err != nil {
+		return "", nil, err
+	}
+
+	return imageId, img.Data, nil
+}
+
+func (is *ImageStore) putVariants(ctx context.Context, imageId string, img *imageproc.Image) error {
+	for _, v := range imageVariants {
+		variant, err := is.processor.Variant(img, v.maxDimension)
+		if err != nil {
+			return errors.Wrapf(err, "generate %v variant", v.size)
+		}
+
+		objName := is.objectName(imageId, v.size)
+		if err := is.oss.PutFile(ctx, int64(len(variant.Data)), objName, bytes.NewReader(variant.Data)); err != nil {
+			return errors.Wrapf(err, "upload %v variant", v.size)
+		}
+	}
+
+	return nil
+}
+
+// GenerateVariants 读取已存储的原图重新生成尺寸变体，用于补齐历史记录
+func (is *ImageStore) GenerateVariants(ctx context.Context, imageId string) error {
+	raw, err := is.Load(ctx, imageId)
+	if err != nil {
+		return err
+	}
+
+	img, err := is.processor.Process(raw)
+	if err != nil {
+		return errors.Wrapf(err, "process image: %v", imageId)
+	}
+
+	return is.putVariants(ctx, imageId, img)
+}
+
+// Remove 删除原图及其所有尺寸变体
+func (is *ImageStore) Remove(ctx context.Context, imageId string) error {
+	sizes := []ImageSize{ImageOriginal}
+	for _, v := range imageVariants {
+		sizes = append(sizes, v.size)
+	}
+
+	for _, size := range sizes {
+		objName := is.objectName(imageId, size)
+		if err := is.oss.RemoveFile(ctx, objName); err != nil {
+			return errors.Wrapf(err, "removeImage: %v", objName)
+		}
+	}
+
+	return nil
+}
+
+func (is *ImageStore) Load(ctx context.Context, imageId string) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	objName := is.objectName(imageId, ImageOriginal)
+	if err := is.oss.GetFile(ctx, objName, buf); err != nil {
+		return nil, errors.Wrapf(err, "loadImage: %v", objName)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (is *ImageStore) Presign(ctx context.Context, imageId string, size ImageSize, expires time.Duration) (*url.URL, error) {
+	u, err := is.oss.PresignedGetObject(ctx, is.objectName(imageId, size), expires)
+	if err != nil {
+		return nil, errors.Wrap(err, "presignedImage")
+	}
+
+	return u, nil
+}
+
+// Proxy 代理读取图片，size 参数不属于 oss 签名的一部分，转发前需要去掉
+func (is *ImageStore) Proxy(imageId string, size ImageSize, rw http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	query.Del("size")
+	req.URL.RawQuery = query.Encode()
+
+	is.oss.ProxyPresignedGetObject(is.objectName(imageId, size), rw, req)
+}
+
+// BackfillImageVariants 为没有尺寸变体的历史记录补齐变体，单条失败时跳过，返回处理成功的记录数
+func BackfillImageVariants(ctx context.Context, repo Repo, images *ImageStore, batchSize int) (int, error) {
+	var afterId, done int
+	for {
+		details, err := repo.GetDetailsWithoutVariants(ctx, afterId, batchSize)
+		if err != nil {
+			return done, errors.Wrap(err, "getDetailsWithoutVariants")
+		}
+		if len(details) == 0 {
+			return done, nil
+		}
+
+		for _, detail := range details {
+			if ctx.Err() != nil {
+				return done, ctx.Err()
+			}
+			afterId = detail.ID
+
+			if err := images.GenerateVariants(ctx, detail.ImageUrl); err != nil {
+				plog.Warnc(ctx, "generate variants for detail: %v failed: %v", detail.ID, err)
+				continue
+			}
+
+			if err := repo.MarkVariantsReady(ctx, detail.ID); err != nil {
+				return done, errors.Wrapf(err, "markVariantsReady: %v", detail.ID)
+			}
+			done++
+		}
+
+		plog.Infoc(ctx, "backfilled image variants for %d records, last detail: %v", done, afterId)
+	}
+}
@@ -0,0 +1,86 @@
+// File:		job.go
+// Created by:	Hoven
+// Created on:	2025-05-29
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysis
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+)
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// jobMaxAttempts 任务被中断后最多重新执行的次数，超过后标记为失败
+const jobMaxAttempts = 3
+
+// errJobLeaseLost 任务执行期间被扫描重新入队并由其他 worker 领取
+var errJobLeaseLost = errors.New("job lease lost")
+
+type AnalysisJob struct {
+	ID        int             `json:"id"`
+	UserID    int             `json:"userId"`
+	Status    JobStatus       `json:"status"`
+	DetailID  int             `json:"detailId,omitempty"`
+	Detail    *AnalysisDetail `json:"detail,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+	ImageUrl  string          `json:"-"`
+	Gender    int             `json:"-"`
+	Error     string          `json:"-"`
+	Message   string          `json:"-"`
+	Attempts  int             `json:"-"`
+}
+
+func (j *AnalysisJob) succeed(detailId int) {
+	j.Status = JobSucceeded
+	j.DetailID = detailId
+}
+
+// fail 记录失败原因，业务异常的提示信息可以直接展示给用户
+func (j *AnalysisJob) fail(err error) {
+	j.Status = JobFailed
+	j.Error = err.Error()
+
+	var be *exception.BeautyException
+	if errors.As(err, &be) {
+		j.Message = be.Message()
+	}
+}
+
+func (j *AnalysisJob) Finished() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed
+}
+
+// JobRepo 中 running 状态任务的更新都以领取时的执行次数作为租约，任务被重新领取后旧的 worker 无法再更新
+type JobRepo interface {
+	CreateJob(ctx context.Context, job *AnalysisJob) error
+	GetJob(ctx context.Context, jobId int) (*AnalysisJob, error)
+	GetUserJob(ctx context.Context, userId, jobId int) (*AnalysisJob, error)
+	// ClaimJob 将执行次数仍为 job.Attempts 的 pending 任务置为 running 并增加执行次数，
+	// 成功时同步更新 job，任务已被其他 worker 领取时返回 false
+	ClaimJob(ctx context.Context, job *AnalysisJob) (bool, error)
+	// HeartbeatJob 刷新 running 任务的更新时间，租约已失效时返回 false
+	HeartbeatJob(ctx context.Context, job *AnalysisJob) (bool, error)
+	// FinishJob 将 running 状态的任务按 job 中的 Status、DetailID、Error 和 Message 更新为最终状态，
+	// 租约已失效时返回 false
+	FinishJob(ctx context.Context, job *AnalysisJob) (bool, error)
+	// RequeueJob 将 running 状态的任务重新置为 pending
+	RequeueJob(ctx context.Context, job *AnalysisJob) error
+	// GetUnfinishedJobs 返回 before 之后没有更新过的 pending 和 running 任务
+	GetUnfinishedJobs(ctx context.Context, before time.Time) ([]*AnalysisJob, error)
+}
@@ -0,0 +1,61 @@
+// File:		moderation.go
+// Created by:	Hoven
+// Created on:	2025-06-02
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysis
+
+import (
+	"context"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"github.com/yazl-tech/beauty-rating-server/pkg/moderator"
+)
+
+func rejectException(category moderator.Category) error {
+	switch category {
+	case moderator.CategoryExplicit:
+		return exception.ErrImageExplicit
+	case moderator.CategoryNoPerson:
+		return exception.ErrImageNoPerson
+	case moderator.CategoryScreenshot:
+		return exception.ErrImageScreenshot
+	case moderator.CategoryLowQuality:
+		return exception.ErrImageLowQuality
+	default:
+		return exception.ErrImageRejected
+	}
+}
+
+// moderate 在评分前审核图片，被拒绝或审核出错时删除已上传的图片并返回面向用户的异常；
+// 审核器出错时是否放行由审核链的 fail open/fail closed 配置决定，审核链只在 fail closed 时返回错误
+func (as *DefaultAnalysisService) moderate(ctx context.Context, imageId string, b []byte) (*moderator.Result, error) {
+	ret, err := as.moderator.Moderate(ctx, b)
+	if err != nil {
+		plog.Errorc(ctx, "moderate image: %v failed, reject it: %v", imageId, err)
+		as.removeImage(ctx, imageId)
+		return nil, exception.ErrModerateImage
+	}
+
+	if ret.Verdict != moderator.VerdictReject {
+		if ret.Verdict == moderator.VerdictReview {
+			plog.Infoc(ctx, "image: %v needs review by %v, category: %v, reason: %v", imageId, ret.Moderator, ret.Category, ret.Reason)
+		}
+		return ret, nil
+	}
+
+	plog.Infoc(ctx, "image: %v rejected by %v, category: %v, reason: %v", imageId, ret.Moderator, ret.Category, ret.Reason)
+	as.removeImage(ctx, imageId)
+
+	return nil, rejectException(ret.Category)
+}
+
+func (as *DefaultAnalysisService) removeImage(ctx context.Context, imageId string) {
+	if err := as.images.Remove(ctx, imageId); err != nil {
+		plog.Warnc(ctx, "remove image: %v failed: %v", imageId, err)
+	}
+}
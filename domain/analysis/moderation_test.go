@@ -0,0 +1,49 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"github.com/yazl-tech/beauty-rating-server/pkg/fakebot"
+	"github.com/yazl-tech/beauty-rating-server/pkg/moderator"
+)
+
+type failedModerator struct{}
+
+func (failedModerator) Name() string { return "FailedModerator" }
+
+func (failedModerator) Moderate(context.Context, []byte) (*moderator.Result, error) {
+	return nil, errors.New("ai-bot down")
+}
+
+func TestDoAnalysis_ModerationFailure(t *testing.T) {
+	ctx := context.Background()
+
+	ts := newTestService(t, fakebot.WithReplies(fakebot.Reply{Content: aiContent}))
+	ts.moderator = moderator.NewChain([]moderator.Moderator{failedModerator{}})
+
+	imageId, b, err := ts.images.Upload(ctx, "a.jpg", testImage(t, 300, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+	detail, err := ts.DoAnalysis(ctx, 1, 1, imageId, b)
+	if err != nil || detail.ModerationStatus != "allow" {
+		t.Fatalf("fail open 期望审核出错时照常评分，实际 %+v, %v", detail, err)
+	}
+
+	ts = newTestService(t, fakebot.WithReplies(fakebot.Reply{Content: aiContent}))
+	ts.moderator = moderator.NewChain([]moderator.Moderator{failedModerator{}}, moderator.WithFailClosed())
+
+	imageId, b, err = ts.images.Upload(ctx, "a.jpg", testImage(t, 300, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.DoAnalysis(ctx, 1, 1, imageId, b); err != exception.ErrModerateImage {
+		t.Fatalf("fail closed 期望审核出错时拒绝，实际 %v", err)
+	}
+	if ts.bot.Calls() != 0 || ts.oss.Len() != 0 {
+		t.Errorf("审核出错的图片不应评分并应从 oss 删除，ai-bot 请求 %d 次，剩余 %d 个对象", ts.bot.Calls(), ts.oss.Len())
+	}
+}
@@ -0,0 +1,149 @@
+// File:		query.go
+// Created by:	Hoven
+// Created on:	2025-06-12
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysis
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type DetailSort string
+
+const (
+	SortByDate  DetailSort = "date"
+	SortByScore DetailSort = "score"
+)
+
+// DetailCursor 上一页最后一条报告在排序中的位置，排序字段相同时按 id 区分
+type DetailCursor struct {
+	SortBy DetailSort `json:"o"`
+	Asc    bool       `json:"a,omitempty"`
+	ID     int        `json:"i"`
+	Score  int        `json:"s,omitempty"`
+	Date   time.Time  `json:"d,omitempty"`
+}
+
+func (c *DetailCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func ParseDetailCursor(s string) (*DetailCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, exception.ErrInvalidCursor
+	}
+
+	c := new(DetailCursor)
+	if err := json.Unmarshal(b, c); err != nil || c.ID <= 0 {
+		return nil, exception.ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// DetailQuery 报告列表的筛选、排序和分页条件，零值表示不限制
+type DetailQuery struct {
+	// CollectionId 只返回该收藏夹中的报告
+	CollectionId int
+	SortBy       DetailSort
+	Asc          bool
+	MinScore     int
+	MaxScore     int
+	// Since、Until 报告创建时间范围 [Since, Until)
+	Since       time.Time
+	Until       time.Time
+	AnalystType *int
+	Tag         string
+	Limit       int
+	// After 从该位置之后开始返回，为空时从第一条开始
+	After *DetailCursor
+}
+
+// Normalize 补齐默认值并校验条件，游标必须与当前的排序方式一致
+func (q *DetailQuery) Normalize() error {
+	if q.SortBy == "" {
+		q.SortBy = SortByDate
+	}
+	if q.SortBy != SortByDate && q.SortBy != SortByScore {
+		return exception.ErrInvalidDetailQuery
+	}
+
+	q.Limit = pageSize(q.Limit)
+
+	if q.MaxScore > 0 && q.MinScore > q.MaxScore {
+		return exception.ErrInvalidDetailQuery
+	}
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Since.Before(q.Until) {
+		return exception.ErrInvalidDetailQuery
+	}
+
+	if q.After != nil && (q.After.SortBy != q.SortBy || q.After.Asc != q.Asc) {
+		return exception.ErrInvalidCursor
+	}
+	return nil
+}
+
+func (q *DetailQuery) cursorOf(d *AnalysisDetail) *DetailCursor {
+	c := &DetailCursor{SortBy: q.SortBy, Asc: q.Asc, ID: d.ID}
+	if q.SortBy == SortByScore {
+		c.Score = d.Score
+	} else {
+		c.Date = d.Date
+	}
+	return c
+}
+
+type DetailPage struct {
+	Details []*AnalysisDetail `json:"details"`
+	// NextCursor 下一页的游标，没有更多数据时为空
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// ComparisonQuery 对比记录列表的分页条件，对比记录只按时间倒序排列
+type ComparisonQuery struct {
+	FavoriteOnly bool
+	Limit        int
+	// After 从该位置之后开始返回，为空时从第一条开始
+	After *DetailCursor
+}
+
+// Normalize 补齐默认的分页数量，游标必须是按时间倒序翻页时产生的
+func (q *ComparisonQuery) Normalize() error {
+	q.Limit = pageSize(q.Limit)
+
+	if q.After != nil && (q.After.SortBy != SortByDate || q.After.Asc) {
+		return exception.ErrInvalidCursor
+	}
+	return nil
+}
+
+func (q *ComparisonQuery) cursorOf(c *Comparison) *DetailCursor {
+	return &DetailCursor{SortBy: SortByDate, ID: c.ID, Date: c.Date}
+}
+
+type ComparisonPage struct {
+	Comparisons []*Comparison `json:"comparisons"`
+	// NextCursor 下一页的游标，没有更多数据时为空
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	return min(limit, maxPageSize)
+}
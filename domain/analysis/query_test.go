@@ -0,0 +1,64 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+)
+
+func TestGetAnalysisDetials_Pagination(t *testing.T) {
+	ts := newTestService(t)
+	ctx := context.Background()
+
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	scores := []int{70, 85, 85, 92, 60}
+	for i, score := range scores {
+		d := &AnalysisDetail{UserID: 1, Score: score, Date: base.AddDate(0, 0, i)}
+		if err := ts.repo.CreateAnalysisDetail(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+		if i%2 == 0 {
+			if err := ts.Favorite(ctx, 1, d.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// 按分数从高到低分页，分数相同时按 id 排序，翻页不应重复或遗漏
+	var ids []int
+	query := &DetailQuery{SortBy: SortByScore, Limit: 2}
+	for range scores {
+		page, err := ts.GetAnalysisDetials(ctx, 1, query)
+		if err != nil {
+			t.Fatalf("期望查询成功，实际错误: %v", err)
+		}
+		for _, d := range page.Details {
+			ids = append(ids, d.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+
+		after, err := ParseDetailCursor(page.NextCursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		query = &DetailQuery{SortBy: SortByScore, Limit: 2, After: after}
+	}
+	if fmt.Sprint(ids) != "[4 3 2 1 5]" {
+		t.Errorf("分页结果不符合预期: %v", ids)
+	}
+
+	page, err := ts.GetFavoriteDetails(ctx, 1, &DetailQuery{Asc: true, MinScore: 65, Until: base.AddDate(0, 0, 4)})
+	if err != nil || len(page.Details) != 2 || page.Details[0].ID != 1 || page.Details[1].ID != 3 || page.NextCursor != "" {
+		t.Fatalf("筛选结果不符合预期: %+v, %v", page, err)
+	}
+
+	_, err = ts.GetAnalysisDetials(ctx, 1, &DetailQuery{After: query.After})
+	if err != exception.ErrInvalidCursor {
+		t.Errorf("排序方式与游标不一致时期望 ErrInvalidCursor，实际: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+// File:		ranking.go
+// Created by:	Hoven
+// Created on:	2025-05-20
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysis
+
+import (
+	"context"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/pkg/errors"
+)
+
+// PercentileUnranked 表示记录尚未计算过排名（历史数据的默认值）
+const PercentileUnranked = -1
+
+// RankScope 描述参与排名的分数分布范围
+type RankScope struct {
+	AnalystType int
+	Gender      int
+	ByGender    bool
+	// ExcludeId 计算已有记录的排名时排除记录自身
+	ExcludeId int
+}
+
+// ScoreRank 为某个分数在分布中的位置统计
+type ScoreRank struct {
+	Below int64
+	Equal int64
+	Total int64
+}
+
+// Percentile 使用中位秩计算“超过了百分之多少的用户”
+// 分布中没有任何样本时返回 50
+func (sr *ScoreRank) Percentile() int {
+	if sr.Total == 0 {
+		return 50
+	}
+
+	p := (float64(sr.Below) + float64(sr.Equal)/2) / float64(sr.Total) * 100
+	if p > 99 {
+		p = 99
+	}
+
+	return int(p)
+}
+
+type Ranker interface {
+	Percentile(ctx context.Context, scope *RankScope, score int) (int, error)
+}
+
+var _ Ranker = (*DistributionRanker)(nil)
+
+// DistributionRanker 根据已持久化的真实分数分布计算百分位
+type DistributionRanker struct {
+	repo Repo
+}
+
+func NewDistributionRanker(repo Repo) *DistributionRanker {
+	return &DistributionRanker{repo: repo}
+}
+
+func (dr *DistributionRanker) Percentile(ctx context.Context, scope *RankScope, score int) (int, error) {
+	rank, err := dr.repo.GetScoreRank(ctx, scope, score)
+	if err != nil {
+		return 0, errors.Wrap(err, "getScoreRank")
+	}
+
+	return rank.Percentile(), nil
+}
+
+// BackfillPercentiles 为历史上未计算排名的记录补齐百分位，避免只在下次读取时才补充导致分布长期偏斜，
+// 返回处理的记录数
+func BackfillPercentiles(ctx context.Context, repo Repo, byGender bool, batchSize int) (int, error) {
+	ranker := NewDistributionRanker(repo)
+
+	var afterId, done int
+	for {
+		details, err := repo.GetUnrankedDetails(ctx, afterId, batchSize)
+		if err != nil {
+			return done, errors.Wrap(err, "getUnrankedDetails")
+		}
+		if len(details) == 0 {
+			return done, nil
+		}
+
+		for _, detail := range details {
+			if ctx.Err() != nil {
+				return done, ctx.Err()
+			}
+			afterId = detail.ID
+
+			scope := &RankScope{
+				AnalystType: detail.AnalyisType,
+				Gender:      detail.Gender,
+				ByGender:    byGender,
+				ExcludeId:   detail.ID,
+			}
+			percentile, err := ranker.Percentile(ctx, scope, detail.Score)
+			if err != nil {
+				return done, errors.Wrapf(err, "rank detail: %v", detail.ID)
+			}
+
+			if err := repo.UpdatePercentile(ctx, detail.ID, percentile); err != nil {
+				return done, errors.Wrapf(err, "updatePercentile: %v", detail.ID)
+			}
+			done++
+		}
+
+		plog.Infoc(ctx, "backfilled percentiles for %d records, last detail: %v", done, afterId)
+	}
+}
@@ -0,0 +1,43 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBackfillPercentiles(t *testing.T) {
+	repo := &memRepo{}
+	ctx := context.Background()
+
+	for _, d := range []*AnalysisDetail{
+		{UserID: 1, Score: 60, Percentile: 10},
+		{UserID: 1, Score: 80, Percentile: 50},
+		{UserID: 2, Score: 90, Percentile: PercentileUnranked},
+		{UserID: 2, Score: 70, Percentile: PercentileUnranked},
+		{UserID: 3, Score: 70, Percentile: PercentileUnranked, AnalyisType: 1},
+	} {
+		if err := repo.CreateAnalysisDetail(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	done, err := BackfillPercentiles(ctx, repo, false, 1)
+	if err != nil || done != 3 {
+		t.Fatalf("期望补齐 3 条记录，实际 %d, %v", done, err)
+	}
+
+	// 按 id 顺序排名，已排名的记录参与后续记录的分布；不同分析器的分数互不影响
+	for id, want := range map[int]int{3: 99, 4: 33, 5: 50} {
+		d, err := repo.GetDetail(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Percentile != want {
+			t.Errorf("记录 %d 期望百分位 %d，实际 %d", id, want, d.Percentile)
+		}
+	}
+
+	if left, _ := repo.GetUnrankedDetails(ctx, 0, 10); len(left) != 0 {
+		t.Errorf("期望所有记录都已排名，剩余 %d 条", len(left))
+	}
+}
@@ -8,15 +8,75 @@
 
 package analysis
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repo interface {
 	CreateAnalysisDetail(ctx context.Context, detail *AnalysisDetail) error
-	GetUserDetails(ctx context.Context, userId int) ([]*AnalysisDetail, error)
+	// GetUserDetails 按 query 筛选并排序用户的报告，最多返回 query.Limit 条
+	GetUserDetails(ctx context.Context, userId int, query *DetailQuery) ([]*AnalysisDetail, error)
 	GetUserDetail(ctx context.Context, userId, detailId int) (*AnalysisDetail, error)
 	GetDetail(ctx context.Context, detailId int) (*AnalysisDetail, error)
-	GetUserFavoriteDetails(ctx context.Context, userId int) ([]*AnalysisDetail, error)
+	// GetDetails 一次查询多份报告，已删除的报告不在结果中，返回顺序不固定
+	GetDetails(ctx context.Context, detailIds []int) ([]*AnalysisDetail, error)
+	// GetDetailByJob 返回异步任务产出的报告，任务还没有产出报告时返回 nil
+	GetDetailByJob(ctx context.Context, jobId int) (*AnalysisDetail, error)
 	CheckDetailExists(ctx context.Context, userId, detailId int) bool
 	UpdateAnalysisDetail(ctx context.Context, detail *AnalysisDetail) error
 	DeleteAnalysisDetail(ctx context.Context, userId, detailId int) error
+	GetScoreRank(ctx context.Context, scope *RankScope, score int) (*ScoreRank, error)
+	UpdatePercentile(ctx context.Context, detailId, percentile int) error
+	// GetUnrankedDetails 按 id 升序返回 afterId 之后尚未计算排名的记录，只包含排名需要的字段
+	GetUnrankedDetails(ctx context.Context, afterId, limit int) ([]*AnalysisDetail, error)
+	// GetLatestDetailByHash 查找 since 之后同一图片在同一实验分组中最新的分析结果，userId 为 0 时不限用户，
+	// 不在实验中的记录 experiment 和 variant 为空，未找到时返回 nil
+	GetLatestDetailByHash(ctx context.Context, imageHash string, userId int, experiment, variant string, since time.Time) (*AnalysisDetail, error)
+	// GetDetailsWithoutVariants 按 id 升序返回 afterId 之后没有图片尺寸变体的记录，只包含 ID 和 ImageUrl
+	GetDetailsWithoutVariants(ctx context.Context, afterId, limit int) ([]*AnalysisDetail, error)
+	MarkVariantsReady(ctx context.Context, detailId int) error
+	MarkShared(ctx context.Context, detailId int) error
+	// GetVariantStats 按分组汇总实验中记录的数量、平均分、收藏数和分享数，降级到后备分析器的记录
+	// 不是分组自身分析器的结果，只计入 Fallbacks
+	GetVariantStats(ctx context.Context, experiment string) ([]*VariantStats, error)
+	// CreatePrimaryVersion 为报告分配下一个版本号保存 version，并将报告更新为 detail，detail 的版本号随之设置；
+	// 报告还没有版本记录时先保存 original。版本号在事务内分配，同一报告并发调用不会冲突
+	CreatePrimaryVersion(ctx context.Context, detail *AnalysisDetail, original, version *AnalysisVersion) error
+	// GetVersions 按版本号升序返回报告的所有历史版本，从未重新分析过的报告没有版本记录
+	GetVersions(ctx context.Context, reportId int) ([]*AnalysisVersion, error)
+	CreateComparison(ctx context.Context, comparison *Comparison) error
+	GetComparison(ctx context.Context, compareId int) (*Comparison, error)
+	GetUserComparison(ctx context.Context, userId, compareId int) (*Comparison, error)
+	// GetUserComparisons 按时间倒序返回用户的对比记录，最多返回 query.Limit 条，不加载前后报告
+	GetUserComparisons(ctx context.Context, userId int, query *ComparisonQuery) ([]*Comparison, error)
+	UpdateComparison(ctx context.Context, comparison *Comparison) error
+	// GetUserStats 汇总用户报告的数量、最高分、平均分以及最近一份报告的分数，只填充这几项
+	GetUserStats(ctx context.Context, userId int) (*UserStats, error)
+	// GetScoreTrend 按周期汇总 since 之后的报告，按周期升序返回
+	GetScoreTrend(ctx context.Context, userId int, period StatsPeriod, since time.Time) ([]*TrendPoint, error)
+	// GetLabelAverages 按评分项汇总平均分，按评分项出现的次数降序返回
+	GetLabelAverages(ctx context.Context, userId int) ([]*LabelAverage, error)
+	// GetTopTags 返回出现次数最多的 limit 个标签
+	GetTopTags(ctx context.Context, userId int, limit int) ([]*TagCount, error)
+	CreateCollection(ctx context.Context, collection *Collection) error
+	GetUserCollection(ctx context.Context, userId, collectionId int) (*Collection, error)
+	// GetDefaultCollection 用户还没有默认收藏夹时返回 nil
+	GetDefaultCollection(ctx context.Context, userId int) (*Collection, error)
+	// GetUserCollections 默认收藏夹排在最前，其余按 Position、id 升序返回
+	GetUserCollections(ctx context.Context, userId int) ([]*Collection, error)
+	// UpdateCollection 更新收藏夹的名称和封面
+	UpdateCollection(ctx context.Context, collection *Collection) error
+	// SortCollections 将 positions 中的收藏夹按 id 设置为对应的位置
+	SortCollections(ctx context.Context, userId int, positions map[int]int) error
+	// DeleteCollection 删除收藏夹及其中的收藏关系
+	DeleteCollection(ctx context.Context, collectionId int) error
+	// AddCollectionItem 报告已在收藏夹中时不做处理
+	AddCollectionItem(ctx context.Context, item *CollectionItem) error
+	RemoveCollectionItem(ctx context.Context, collectionId, reportId int) error
+	// GetCollectionSummaries 一次查询多个收藏夹的报告数量和封面报告，封面未指定、已被删除或移出收藏夹时
+	// 使用收藏夹中最新的报告，没有报告的收藏夹不在结果中
+	GetCollectionSummaries(ctx context.Context, collectionIds []int) ([]*CollectionSummary, error)
+	// FilterCollectedReports 返回 reportIds 中在收藏夹里的报告 id
+	FilterCollectedReports(ctx context.Context, collectionId int, reportIds []int) ([]int, error)
 }
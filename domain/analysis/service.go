@@ -9,17 +9,14 @@
 package analysis
 
 import (
-	"bytes"
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io"
-	"math/rand"
 	"mime/multipart"
 	"net/http"
-	"net/url"
 	"time"
 
 	"github.com/go-puzzles/puzzles/plog"
@@ -28,58 +25,104 @@ import (
 	"github.com/yazl-tech/beauty-rating-server/config"
 	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
 	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"github.com/yazl-tech/beauty-rating-server/pkg/experiment"
+	"github.com/yazl-tech/beauty-rating-server/pkg/imageproc"
+	"github.com/yazl-tech/beauty-rating-server/pkg/moderator"
 	"github.com/yazl-tech/beauty-rating-server/pkg/oss"
-	"gorm.io/gorm"
 )
 
 type Service interface {
 	UploadAnalysisImage(ctx context.Context, avatarFile *multipart.FileHeader) (string, []byte, error)
-	GetAnalysisImage(ctx context.Context, imageId string, rw http.ResponseWriter, req *http.Request)
-	DoAnalysis(ctx context.Context, userId int, imageId string, b []byte) (*AnalysisDetail, error)
-	GetFavoriteDetails(ctx context.Context, userId int) ([]*AnalysisDetail, error)
-	GetAnalysisDetials(ctx context.Context, userId int) ([]*AnalysisDetail, error)
+	GetAnalysisImage(ctx context.Context, imageId string, size ImageSize, rw http.ResponseWriter, req *http.Request)
+	DoAnalysis(ctx context.Context, userId, gender int, imageId string, b []byte) (*AnalysisDetail, error)
+	SubmitAnalysisJob(ctx context.Context, userId, gender int, imageId string, b []byte) (*AnalysisJob, error)
+	GetAnalysisJob(ctx context.Context, userId, jobId int) (*AnalysisJob, error)
+	RunJobWorkers(ctx context.Context) error
+	GetFavoriteDetails(ctx context.Context, userId int, query *DetailQuery) (*DetailPage, error)
+	GetAnalysisDetials(ctx context.Context, userId int, query *DetailQuery) (*DetailPage, error)
 	ShareAnalysisDetail(ctx context.Context, userId, reportId int) (*ShareDetailToken, error)
 	GetShareDetail(ctx context.Context, token *ShareDetailToken) (*AnalysisDetail, error)
 	Favorite(ctx context.Context, userId int, detailId int) error
 	UnFavorite(ctx context.Context, userId int, detailId int) error
 	DeleteAnalysis(ctx context.Context, userId int, detailId int) error
+	DiscardAnalysis(ctx context.Context, userId int, detailId int) error
+	GetExperimentReport(ctx context.Context, experiment string) (*ExperimentReport, error)
+	Reanalyze(ctx context.Context, userId, reportId int, typ *analyst.AnalystType) (*AnalysisDetail, error)
+	GetVersions(ctx context.Context, userId, reportId int) ([]*AnalysisVersion, error)
+	SetPrimaryVersion(ctx context.Context, userId, reportId, version int) (*AnalysisDetail, error)
+	Compare(ctx context.Context, userId, beforeId, afterId int) (*Comparison, error)
+	GetComparison(ctx context.Context, userId, compareId int) (*Comparison, error)
+	GetComparisons(ctx context.Context, userId int, query *ComparisonQuery) (*ComparisonPage, error)
+	FavoriteComparison(ctx context.Context, userId, compareId int) error
+	UnFavoriteComparison(ctx context.Context, userId, compareId int) error
+	ShareComparison(ctx context.Context, userId, compareId int) (*ShareCompareToken, error)
+	GetShareComparison(ctx context.Context, token *ShareCompareToken) (*Comparison, error)
+	GetStats(ctx context.Context, userId int, period StatsPeriod) (*UserStats, error)
+	GetCollections(ctx context.Context, userId int) ([]*Collection, error)
+	GetCollection(ctx context.Context, userId, collectionId int) (*Collection, error)
+	CreateCollection(ctx context.Context, userId int, name string) (*Collection, error)
+	UpdateCollection(ctx context.Context, userId, collectionId int, update *CollectionUpdate) (*Collection, error)
+	DeleteCollection(ctx context.Context, userId, collectionId int) error
+	SortCollections(ctx context.Context, userId int, collectionIds []int) ([]*Collection, error)
+	GetCollectionDetails(ctx context.Context, userId, collectionId int, query *DetailQuery) (*DetailPage, error)
+	AddToCollection(ctx context.Context, userId, collectionId, reportId int) error
+	RemoveFromCollection(ctx context.Context, userId, collectionId, reportId int) error
 }
 
 var _ Service = (*DefaultAnalysisService)(nil)
 
 type DefaultAnalysisService struct {
-	beautyConf     *config.BeautyConfig
-	analyst        analyst.Analyst
-	repo           Repo
-	oss            oss.IOSS
-	analysisImgDir string
+	beautyConf *config.BeautyConfig
+	analyst    analyst.Analyst
+	experiment *experiment.Experiment
+	moderator  moderator.Moderator
+	repo       Repo
+	jobRepo    JobRepo
+	jobQueue   chan *jobTask
+	ranker     Ranker
+	images     *ImageStore
 }
 
 func NewAnalysisService(
 	beautyConf *config.BeautyConfig,
 	analyst analyst.Analyst,
+	experiment *experiment.Experiment,
+	moderator moderator.Moderator,
 	repo Repo,
+	jobRepo JobRepo,
 	oss oss.IOSS,
 ) *DefaultAnalysisService {
 	return &DefaultAnalysisService{
-		beautyConf:     beautyConf,
-		analyst:        analyst,
-		repo:           repo,
-		oss:            oss,
-		analysisImgDir: "analysis",
+		beautyConf: beautyConf,
+		analyst:    analyst,
+		experiment: experiment,
+		moderator:  moderator,
+		repo:       repo,
+		jobRepo:    jobRepo,
+		jobQueue:   make(chan *jobTask, beautyConf.JobQueueSize),
+		ranker:     NewDistributionRanker(repo),
+		images: NewImageStore(
+			oss,
+			imageproc.NewProcessor(
+				imageproc.WithMaxDimension(beautyConf.ImageMaxDimension),
+				imageproc.WithMaxPixels(beautyConf.ImageMaxPixels),
+			),
+		),
 	}
 }
 
+func (as *DefaultAnalysisService) signShare(data string) string {
+	h := hmac.New(sha256.New, []byte(as.beautyConf.ShareSecretKey))
+	h.Write([]byte(data))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func (as *DefaultAnalysisService) verifyShareToken(token *ShareDetailToken) (err error) {
 	if time.Now().Unix() > token.Expires {
 		return exception.ErrShareExpires
 	}
 
-	data := fmt.Sprintf("%d/%d", token.DetailId, token.Expires)
-	h := hmac.New(sha256.New, []byte(as.beautyConf.ShareSecretKey))
-	h.Write([]byte(data))
-	expectedSig := hex.EncodeToString(h.Sum(nil))
-
+	expectedSig := as.signShare(fmt.Sprintf("%d/%d", token.DetailId, token.Expires))
 	if !hmac.Equal([]byte(expectedSig), []byte(token.Sig)) {
 		return exception.ErrShareTokenInvalidates
 	}
@@ -89,11 +132,7 @@ func (as *DefaultAnalysisService) verifyShareToken(token *ShareDetailToken) (err
 
 func (as *DefaultAnalysisService) generateShareToken(detailId int, expiresDura time.Duration) *ShareDetailToken {
 	expires := time.Now().Add(expiresDura).Unix()
-	data := fmt.Sprintf("%d/%d", detailId, expires)
-
-	h := hmac.New(sha256.New, []byte(as.beautyConf.ShareSecretKey))
-	h.Write([]byte(data))
-	signature := hex.EncodeToString(h.Sum(nil))
+	signature := as.signShare(fmt.Sprintf("%d/%d", detailId, expires))
 
 	return &ShareDetailToken{
 		DetailId: detailId,
@@ -102,12 +141,47 @@ func (as *DefaultAnalysisService) generateShareToken(detailId int, expiresDura t
 	}
 }
 
+func (as *DefaultAnalysisService) rankScope(analystType, gender int) *RankScope {
+	return &RankScope{
+		AnalystType: analystType,
+		Gender:      gender,
+		ByGender:    as.beautyConf.RankByGender,
+	}
+}
+
+// ensurePercentile 为历史上未计算排名的记录补充百分位并持久化，之后每次读取结果保持不变
+func (as *DefaultAnalysisService) ensurePercentile(ctx context.Context, detail *AnalysisDetail) {
+	if detail == nil || detail.Percentile != PercentileUnranked {
+		return
+	}
+
+	scope := as.rankScope(detail.AnalyisType, detail.Gender)
+	scope.ExcludeId = detail.ID
+
+	percentile, err := as.ranker.Percentile(ctx, scope, detail.Score)
+	if err != nil {
+		plog.Warnc(ctx, "rank detail: %v failed: %v", detail.ID, err)
+		return
+	}
+
+	if err := as.repo.UpdatePercentile(ctx, detail.ID, percentile); err != nil {
+		plog.Warnc(ctx, "update detail: %v percentile failed: %v", detail.ID, err)
+		return
+	}
+
+	detail.Percentile = percentile
+}
+
 func (as *DefaultAnalysisService) ShareAnalysisDetail(ctx context.Context, userId, reportId int) (*ShareDetailToken, error) {
 	exists := as.repo.CheckDetailExists(ctx, userId, reportId)
 	if !exists {
 		return nil, exception.ErrDetailNotFound
 	}
 
+	if err := as.repo.MarkShared(ctx, reportId); err != nil {
+		plog.Warnc(ctx, "mark detail: %v shared failed: %v", reportId, err)
+	}
+
 	expires := time.Hour * 24
 	shareToken := as.generateShareToken(reportId, expires)
 
@@ -128,31 +202,51 @@ func (as *DefaultAnalysisService) GetShareDetail(ctx context.Context, token *Sha
 	return as.convertImage(ctx, detail), nil
 }
 
-func (as *DefaultAnalysisService) GetFavoriteDetails(ctx context.Context, userId int) ([]*AnalysisDetail, error) {
-	resp, err := as.repo.GetUserFavoriteDetails(ctx, userId)
+// imageUrl 生成经由本服务代理的图片地址：/api/v1/analysis/image/:imageId?size=
+func (as *DefaultAnalysisService) imageUrl(ctx context.Context, imageId string, size ImageSize) (string, error) {
+	presignedUrl, err := as.images.Presign(ctx, imageId, size, 5*time.Minute)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return as.convertImages(ctx, resp), nil
+	presignedUrl.Host = as.beautyConf.ApiHost
+	if as.beautyConf.ApiTls {
+		presignedUrl.Scheme = "https"
+	}
+	presignedUrl.Path = fmt.Sprintf("%s%s/analysis/image/%s", as.beautyConf.ApiPrefix, as.beautyConf.ApiVersion, imageId)
+	if size != ImageOriginal {
+		presignedUrl.RawQuery += "&size=" + string(size)
+	}
+
+	return presignedUrl.String(), nil
 }
 
 func (as *DefaultAnalysisService) convertImage(ctx context.Context, detail *AnalysisDetail) *AnalysisDetail {
-	objName := fmt.Sprintf("%s/%s", as.analysisImgDir, detail.ImageUrl)
-	presignedUrl, err := as.presignImageUrl(ctx, objName, 5*time.Minute)
+	as.ensurePercentile(ctx, detail)
+
+	imageId := detail.ImageUrl
+	originalUrl, err := as.imageUrl(ctx, imageId, ImageOriginal)
 	if err != nil {
-		plog.Warnc(ctx, "presignedUrl: %v failed: %v", detail.ImageUrl, err)
+		plog.Warnc(ctx, "presignedUrl: %v failed: %v", imageId, err)
 		return nil
 	}
+	detail.ImageUrl = originalUrl
 
-	presignedUrl.Host = as.beautyConf.ApiHost
-	if as.beautyConf.ApiTls {
-		presignedUrl.Scheme = "https"
+	// 尚未补齐变体的历史记录使用原图
+	detail.ThumbnailUrl, detail.MediumUrl = originalUrl, originalUrl
+	if !detail.HasVariants {
+		return detail
+	}
+
+	if detail.ThumbnailUrl, err = as.imageUrl(ctx, imageId, ImageThumb); err != nil {
+		plog.Warnc(ctx, "presignedUrl: %v thumb failed: %v", imageId, err)
+		detail.ThumbnailUrl = originalUrl
+	}
+	if detail.MediumUrl, err = as.imageUrl(ctx, imageId, ImageMedium); err != nil {
+		plog.Warnc(ctx, "presignedUrl: %v medium failed: %v", imageId, err)
+		detail.MediumUrl = originalUrl
 	}
-	// /api/v1/analysis/image/:imageId
-	presignedUrl.Path = fmt.Sprintf("%s%s/analysis/image/%s", as.beautyConf.ApiPrefix, as.beautyConf.ApiVersion, detail.ImageUrl)
 
-	detail.ImageUrl = presignedUrl.String()
 	return detail
 }
 
@@ -162,24 +256,35 @@ func (as *DefaultAnalysisService) convertImages(ctx context.Context, details []*
 	})
 }
 
-func (as *DefaultAnalysisService) presignImageUrl(ctx context.Context, objName string, expires time.Duration) (*url.URL, error) {
-	u, err := as.oss.PresignedGetObject(ctx, objName, expires)
-	if err != nil {
-		return nil, errors.Wrap(err, "presignedImage")
-	}
-
-	return u, nil
+func (as *DefaultAnalysisService) GetAnalysisDetials(ctx context.Context, userId int, query *DetailQuery) (*DetailPage, error) {
+	return as.queryDetails(ctx, userId, query)
 }
 
-func (as *DefaultAnalysisService) GetAnalysisDetials(ctx context.Context, userId int) ([]*AnalysisDetail, error) {
-	resp, err := as.repo.GetUserDetails(ctx, userId)
+// queryDetails 多查询一条用于判断是否还有下一页，只为当前页的报告签名图片地址
+func (as *DefaultAnalysisService) queryDetails(ctx context.Context, userId int, query *DetailQuery) (*DetailPage, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	q := *query
+	q.Limit++
+	details, err := as.repo.GetUserDetails(ctx, userId, &q)
 	if err != nil {
 		return nil, err
 	}
 
-	return as.convertImages(ctx, resp), nil
+	page := new(DetailPage)
+	if len(details) > query.Limit {
+		details = details[:query.Limit]
+		page.NextCursor = query.cursorOf(details[len(details)-1]).Encode()
+	}
+	as.markFavorites(ctx, userId, details...)
+	page.Details = as.convertImages(ctx, details)
+
+	return page, nil
 }
 
+// UploadAnalysisImage 预处理并存储上传的图片及其尺寸变体，返回的图片内容与存储的原图一致
 func (as *DefaultAnalysisService) UploadAnalysisImage(ctx context.Context, file *multipart.FileHeader) (string, []byte, error) {
 	src, err := file.Open()
 	if err != nil {
@@ -187,72 +292,124 @@ func (as *DefaultAnalysisService) UploadAnalysisImage(ctx context.Context, file
 	}
 	defer src.Close()
 
-	var fileBytes bytes.Buffer
-	teeReader := io.TeeReader(src, &fileBytes)
-
-	imageUrl, err := as.oss.UploadFile(ctx, file.Size, as.analysisImgDir, file.Filename, teeReader)
+	raw, err := io.ReadAll(src)
 	if err != nil {
-		return "", nil, errors.Wrap(err, "uploadAnalysisImage")
+		return "", nil, errors.Wrap(err, "readAnalysisImage")
 	}
 
-	return imageUrl, fileBytes.Bytes(), nil
+	return as.images.Upload(ctx, file.Filename, raw)
 }
 
-func (as *DefaultAnalysisService) GetAnalysisImage(ctx context.Context, imageId string, rw http.ResponseWriter, req *http.Request) {
-	objName := fmt.Sprintf("%s/%s", as.analysisImgDir, imageId)
-	as.oss.ProxyPresignedGetObject(objName, rw, req)
+func (as *DefaultAnalysisService) GetAnalysisImage(ctx context.Context, imageId string, size ImageSize, rw http.ResponseWriter, req *http.Request) {
+	as.images.Proxy(imageId, size, rw, req)
 }
 
-func (as *DefaultAnalysisService) DoAnalysis(ctx context.Context, userId int, imageId string, b []byte) (*AnalysisDetail, error) {
-	d, err := as.analyst.DoAnalysis(ctx, imageId, imageId, b)
+func (as *DefaultAnalysisService) analyzeImage(ctx context.Context, userId, gender int, imageId string, b []byte) (*AnalysisDetail, error) {
+	a, experiment, variant := as.pickAnalyst(userId)
+	d, err := a.DoAnalysis(ctx, imageId, imageId, b)
 	if err != nil {
 		return nil, err
 	}
 
-	detail := &AnalysisDetail{
-		UserID:       userId,
-		ImageUrl:     imageId,
-		Score:        d.Score,
-		Percentile:   rand.Intn(20) + 80,
-		Description:  d.Description,
-		Tags:         d.Tags,
-		Date:         time.Now(),
-		ScoreDetails: parseAnalystDetails(d.Details),
-		AnalyisType:  int(d.AnalystType),
+	detail, err := as.newDetail(ctx, d, gender)
+	if err != nil {
+		return nil, err
 	}
+	detail.Experiment = experiment
+	detail.Variant = variant
+	return detail, nil
+}
 
-	err = as.repo.CreateAnalysisDetail(ctx, detail)
+// newDetail 根据分析结果生成报告并计算排名
+func (as *DefaultAnalysisService) newDetail(ctx context.Context, d *analyst.Result, gender int) (*AnalysisDetail, error) {
+	percentile, err := as.ranker.Percentile(ctx, as.rankScope(int(d.AnalystType), gender), d.Score)
 	if err != nil {
 		return nil, err
 	}
 
-	return as.convertImage(ctx, detail), nil
+	return &AnalysisDetail{
+		Score:         d.Score,
+		Percentile:    percentile,
+		Description:   d.Description,
+		Tags:          d.Tags,
+		ScoreDetails:  parseAnalystDetails(d.Details),
+		AnalyisType:   int(d.AnalystType),
+		AnalystName:   d.AnalystName,
+		IsFallback:    d.Fallback,
+		PromptVersion: d.PromptVersion,
+		Model:         d.Model,
+		Contributions: parseContributions(d.Contributions),
+	}, nil
 }
 
-func (as *DefaultAnalysisService) Favorite(ctx context.Context, userId int, detailId int) error {
-	detail, err := as.repo.GetUserDetail(ctx, userId, detailId)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return exception.ErrDetailNotFound
-	} else if err != nil {
-		return errors.Wrapf(err, "getUserDetail. userId=%v, detailId=%v", userId, detail)
+// createDetail 分析图片并保存结果，返回的报告中图片地址尚未签名
+// createDetail 分析图片并保存报告，jobId 为产出报告的异步任务，同步分析时为 0
+func (as *DefaultAnalysisService) createDetail(ctx context.Context, userId, gender int, imageId string, b []byte, jobId int) (*AnalysisDetail, error) {
+	imageHash := HashImage(b)
+
+	// 命中缓存说明同一张图片已经审核并评分过，不再重复审核
+	detail := as.lookupCachedDetail(ctx, userId, imageHash)
+	if detail == nil {
+		verdict, err := as.moderate(ctx, imageId, b)
+		if err != nil {
+			return nil, err
+		}
+
+		detail, err = as.analyzeImage(ctx, userId, gender, imageId, b)
+		if err != nil {
+			return nil, err
+		}
+		detail.ModerationStatus = string(verdict.Verdict)
+		detail.ModerationReason = verdict.Reason
 	}
 
-	detail.IsFavorite = true
-	return as.repo.UpdateAnalysisDetail(ctx, detail)
+	detail.UserID = userId
+	detail.ImageUrl = imageId
+	detail.HasVariants = true
+	detail.Version = 1
+	detail.ImageHash = imageHash
+	detail.Gender = gender
+	detail.Date = time.Now()
+	detail.JobID = jobId
+
+	err := as.repo.CreateAnalysisDetail(ctx, detail)
+	if err != nil {
+		// 租约失效后同一任务的另一次执行可能已经保存了报告，报告按任务唯一
+		if jobId != 0 {
+			if existing, getErr := as.repo.GetDetailByJob(ctx, jobId); getErr == nil && existing != nil {
+				return existing, nil
+			}
+		}
+		return nil, err
+	}
+
+	return detail, nil
 }
 
-func (as *DefaultAnalysisService) UnFavorite(ctx context.Context, userId int, detailId int) error {
-	detail, err := as.repo.GetUserDetail(ctx, userId, detailId)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return exception.ErrDetailNotFound
-	} else if err != nil {
-		return errors.Wrapf(err, "getUserDetail. userId=%v, detailId=%v", userId, detail)
+func (as *DefaultAnalysisService) DoAnalysis(ctx context.Context, userId, gender int, imageId string, b []byte) (*AnalysisDetail, error) {
+	detail, err := as.createDetail(ctx, userId, gender, imageId, b, 0)
+	if err != nil {
+		return nil, err
 	}
 
-	detail.IsFavorite = false
-	return as.repo.UpdateAnalysisDetail(ctx, detail)
+	return as.convertImage(ctx, detail), nil
 }
 
 func (as *DefaultAnalysisService) DeleteAnalysis(ctx context.Context, userId int, detailId int) error {
 	return as.repo.DeleteAnalysisDetail(ctx, userId, detailId)
 }
+
+// DiscardAnalysis 放弃刚生成的报告，与 DeleteAnalysis 不同，报告的原图和各尺寸变体也一并从 oss 删除
+func (as *DefaultAnalysisService) DiscardAnalysis(ctx context.Context, userId int, detailId int) error {
+	detail, err := as.getUserDetail(ctx, userId, detailId)
+	if err != nil {
+		return err
+	}
+
+	if err := as.repo.DeleteAnalysisDetail(ctx, userId, detailId); err != nil {
+		return errors.Wrapf(err, "deleteAnalysisDetail. detailId=%v", detailId)
+	}
+
+	as.removeImage(ctx, detail.ImageUrl)
+	return nil
+}
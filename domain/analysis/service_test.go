@@ -0,0 +1,433 @@
+package analysis
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/config"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst/ai"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst/mock"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"github.com/yazl-tech/beauty-rating-server/pkg/fakebot"
+	"github.com/yazl-tech/beauty-rating-server/pkg/moderator/rule"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+const aiContent = `{
+	"score": 91,
+	"description": "整体气质清新",
+	"tags": ["清秀", "温柔", "阳光", "自然"],
+	"scoreDetails": [
+		{"label": "五官", "score": 90, "desc": "五官端正"},
+		{"label": "气质", "score": 93, "desc": "气质温和"},
+		{"label": "妆容", "score": 88, "desc": "妆容自然"},
+		{"label": "发型", "score": 86, "desc": "发型清爽"}
+	]
+}`
+
+// memRepo 内存中的 Repo 实现，各功能的存储在对应的测试文件中
+type memRepo struct {
+	mu      sync.Mutex
+	details []*AnalysisDetail
+
+	memVersions
+	memComparisons
+	memCollections
+}
+
+func (r *memRepo) CreateAnalysisDetail(_ context.Context, detail *AnalysisDetail) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 与数据库一样，同一任务只能保存一份报告
+	if detail.JobID != 0 && slices.ContainsFunc(r.details, func(d *AnalysisDetail) bool { return d.JobID == detail.JobID }) {
+		return errors.New("duplicate job id")
+	}
+
+	detail.ID = len(r.details) + 1
+	saved := *detail
+	r.details = append(r.details, &saved)
+	return nil
+}
+
+func (r *memRepo) find(match func(d *AnalysisDetail) bool) []*AnalysisDetail {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ret []*AnalysisDetail
+	for _, d := range r.details {
+		if match(d) {
+			copied := *d
+			ret = append(ret, &copied)
+		}
+	}
+	return ret
+}
+
+func (r *memRepo) first(match func(d *AnalysisDetail) bool) (*AnalysisDetail, error) {
+	found := r.find(match)
+	if len(found) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return found[0], nil
+}
+
+func (r *memRepo) GetUserDetails(_ context.Context, userId int, query *DetailQuery) ([]*AnalysisDetail, error) {
+	// compare 按查询的排序方式比较两份报告，小于 0 表示 a 排在 b 之前
+	compare := func(a, b *AnalysisDetail) int {
+		c := a.Date.Compare(b.Date)
+		if query.SortBy == SortByScore {
+			c = cmp.Compare(a.Score, b.Score)
+		}
+		if c == 0 {
+			c = cmp.Compare(a.ID, b.ID)
+		}
+		if !query.Asc {
+			c = -c
+		}
+		return c
+	}
+
+	collected := r.collectedReports(func(c *Collection) bool { return c.ID == query.CollectionId })
+	details := r.find(func(d *AnalysisDetail) bool {
+		return d.UserID == userId &&
+			(query.CollectionId == 0 || collected[d.ID]) &&
+			(query.MinScore == 0 || d.Score >= query.MinScore) &&
+			(query.MaxScore == 0 || d.Score <= query.MaxScore) &&
+			(query.Since.IsZero() || !d.Date.Before(query.Since)) &&
+			(query.Until.IsZero() || d.Date.Before(query.Until)) &&
+			(query.AnalystType == nil || d.AnalyisType == *query.AnalystType) &&
+			(query.Tag == "" || slices.Contains(d.Tags, query.Tag)) &&
+			(query.After == nil || compare(d, &AnalysisDetail{ID: query.After.ID, Score: query.After.Score, Date: query.After.Date}) > 0)
+	})
+	slices.SortFunc(details, compare)
+
+	if len(details) > query.Limit {
+		details = details[:query.Limit]
+	}
+	return details, nil
+}
+
+func (r *memRepo) GetUserDetail(_ context.Context, userId, detailId int) (*AnalysisDetail, error) {
+	return r.first(func(d *AnalysisDetail) bool { return d.UserID == userId && d.ID == detailId })
+}
+
+func (r *memRepo) GetDetail(_ context.Context, detailId int) (*AnalysisDetail, error) {
+	return r.first(func(d *AnalysisDetail) bool { return d.ID == detailId })
+}
+
+func (r *memRepo) GetDetails(_ context.Context, detailIds []int) ([]*AnalysisDetail, error) {
+	return r.find(func(d *AnalysisDetail) bool { return slices.Contains(detailIds, d.ID) }), nil
+}
+
+func (r *memRepo) GetDetailByJob(_ context.Context, jobId int) (*AnalysisDetail, error) {
+	found := r.find(func(d *AnalysisDetail) bool { return d.JobID == jobId })
+	if len(found) == 0 {
+		return nil, nil
+	}
+	return found[0], nil
+}
+
+func (r *memRepo) CheckDetailExists(ctx context.Context, userId, detailId int) bool {
+	_, err := r.GetUserDetail(ctx, userId, detailId)
+	return err == nil
+}
+
+func (r *memRepo) UpdateAnalysisDetail(_ context.Context, detail *AnalysisDetail) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, d := range r.details {
+		if d.ID == detail.ID {
+			saved := *detail
+			r.details[i] = &saved
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memRepo) DeleteAnalysisDetail(_ context.Context, userId, detailId int) error {
+	r.mu.Lock()
+	r.details = slices.DeleteFunc(r.details, func(d *AnalysisDetail) bool { return d.UserID == userId && d.ID == detailId })
+	r.mu.Unlock()
+
+	r.removeReport(detailId)
+	r.removeVersions(detailId)
+	return nil
+}
+
+func (r *memRepo) GetScoreRank(_ context.Context, scope *RankScope, score int) (*ScoreRank, error) {
+	rank := &ScoreRank{}
+	for _, d := range r.find(func(d *AnalysisDetail) bool {
+		return d.AnalyisType == scope.AnalystType && d.Percentile != PercentileUnranked && d.ID != scope.ExcludeId && (!scope.ByGender || d.Gender == scope.Gender)
+	}) {
+		rank.Total++
+		switch {
+		case d.Score < score:
+			rank.Below++
+		case d.Score == score:
+			rank.Equal++
+		}
+	}
+	return rank, nil
+}
+
+func (r *memRepo) UpdatePercentile(_ context.Context, detailId, percentile int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range r.details {
+		if d.ID == detailId {
+			d.Percentile = percentile
+		}
+	}
+	return nil
+}
+
+func (r *memRepo) GetUnrankedDetails(_ context.Context, afterId, limit int) ([]*AnalysisDetail, error) {
+	found := r.find(func(d *AnalysisDetail) bool { return d.ID > afterId && d.Percentile == PercentileUnranked })
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found, nil
+}
+
+func (r *memRepo) GetLatestDetailByHash(_ context.Context, imageHash string, userId int, experiment, variant string, since time.Time) (*AnalysisDetail, error) {
+	found := r.find(func(d *AnalysisDetail) bool {
+		return d.ImageHash == imageHash && (userId == 0 || d.UserID == userId) &&
+			d.Experiment == experiment && d.Variant == variant && d.Date.After(since)
+	})
+	if len(found) == 0 {
+		return nil, nil
+	}
+	return found[len(found)-1], nil
+}
+
+func (r *memRepo) GetDetailsWithoutVariants(_ context.Context, afterId, limit int) ([]*AnalysisDetail, error) {
+	found := r.find(func(d *AnalysisDetail) bool { return d.ID > afterId && !d.HasVariants })
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found, nil
+}
+
+func (r *memRepo) MarkVariantsReady(_ context.Context, detailId int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range r.details {
+		if d.ID == detailId {
+			d.HasVariants = true
+		}
+	}
+	return nil
+}
+
+// memOSS 内存中的 oss.IOSS 实现
+type memOSS struct {
+	mu      sync.Mutex
+	seq     int
+	objects map[string][]byte
+}
+
+func newMemOSS() *memOSS {
+	return &memOSS{objects: make(map[string][]byte)}
+}
+
+func (o *memOSS) UploadFile(ctx context.Context, size int64, dir, objName string, obj io.Reader) (string, error) {
+	o.mu.Lock()
+	o.seq++
+	uri := fmt.Sprintf("%d-%s", o.seq, objName)
+	o.mu.Unlock()
+
+	return uri, o.PutFile(ctx, size, dir+"/"+uri, obj)
+}
+
+func (o *memOSS) PutFile(_ context.Context, _ int64, objName string, obj io.Reader) error {
+	b, err := io.ReadAll(obj)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.objects[objName] = b
+	return nil
+}
+
+func (o *memOSS) GetFile(_ context.Context, objName string, w io.Writer) error {
+	o.mu.Lock()
+	b, ok := o.objects[objName]
+	o.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("object not found: %v", objName)
+	}
+
+	_, err := w.Write(b)
+	return err
+}
+
+func (o *memOSS) RemoveFile(_ context.Context, objName string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.objects, objName)
+	return nil
+}
+
+func (o *memOSS) PresignedGetObject(_ context.Context, objName string, _ time.Duration) (*url.URL, error) {
+	return url.Parse("http://oss.local/bucket/" + objName + "?X-Amz-Signature=sig")
+}
+
+func (o *memOSS) ProxyPresignedGetObject(objName string, rw http.ResponseWriter, _ *http.Request) {
+	_ = o.GetFile(context.Background(), objName, rw)
+}
+
+func (o *memOSS) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return len(o.objects)
+}
+
+// testImage 生成带有明暗变化的 JPEG 图片，能通过规则审核
+func testImage(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8((x*7 + y*13 + (x*y)%97) % 256)
+			img.Set(x, y, color.RGBA{R: v, G: 255 - v, B: uint8(x + y), A: 255})
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+type testService struct {
+	*DefaultAnalysisService
+	bot  *fakebot.Server
+	repo *memRepo
+	oss  *memOSS
+}
+
+func newTestService(t *testing.T, opts ...fakebot.Option) *testService {
+	bot := fakebot.Start(t, opts...)
+
+	conf := &config.BeautyConfig{}
+	conf.SetDefault()
+
+	selector := analyst.NewAnalystSelector(
+		analyst.WithAnalysts(ai.NewAiAnalyst("fake-model", bot.Client()), 1),
+		analyst.WithAnalysts(mock.NewMockAnalyst(), 0),
+	)
+
+	repo := &memRepo{}
+	oss := newMemOSS()
+	return &testService{
+		DefaultAnalysisService: NewAnalysisService(conf, selector, nil, rule.NewRuleModerator(), repo, nil, oss),
+		bot:                    bot,
+		repo:                   repo,
+		oss:                    oss,
+	}
+}
+
+func TestDoAnalysis(t *testing.T) {
+	ts := newTestService(t, fakebot.WithReplies(fakebot.Reply{Content: aiContent}))
+	ctx := context.Background()
+
+	imageId, b, err := ts.images.Upload(ctx, "a.jpg", testImage(t, 300, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detail, err := ts.DoAnalysis(ctx, 1, 1, imageId, b)
+	if err != nil {
+		t.Fatalf("期望分析成功，实际错误: %v", err)
+	}
+	if detail.AnalystName != "AiAnalyst" || detail.Score != 91 || len(detail.ScoreDetails) != 4 {
+		t.Errorf("分析结果不符合预期: %+v", detail)
+	}
+	if detail.ModerationStatus != "allow" {
+		t.Errorf("期望审核通过，实际 %v", detail.ModerationStatus)
+	}
+	if !strings.Contains(detail.ImageUrl, "/api/v1/analysis/image/"+imageId) || !strings.Contains(detail.ThumbnailUrl, "size=thumb") {
+		t.Errorf("图片地址不符合预期: %v, %v", detail.ImageUrl, detail.ThumbnailUrl)
+	}
+
+	saved, err := ts.repo.GetUserDetail(ctx, 1, detail.ID)
+	if err != nil || saved.ImageUrl != imageId || saved.ImageHash != HashImage(b) {
+		t.Fatalf("保存的结果不符合预期: %+v, %v", saved, err)
+	}
+
+	again, err := ts.DoAnalysis(ctx, 1, 1, imageId, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID == detail.ID || again.Score != detail.Score {
+		t.Errorf("期望复用历史结果并新建记录，实际 %+v", again)
+	}
+	if ts.bot.Calls() != 1 {
+		t.Errorf("期望相同图片只请求一次 ai-bot，实际 %d 次", ts.bot.Calls())
+	}
+}
+
+func TestDoAnalysis_Fallback(t *testing.T) {
+	ts := newTestService(t, fakebot.WithError(status.Error(codes.Unavailable, "ai-bot down")))
+	ctx := context.Background()
+
+	imageId, b, err := ts.images.Upload(ctx, "a.jpg", testImage(t, 300, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detail, err := ts.DoAnalysis(ctx, 1, 1, imageId, b)
+	if err != nil {
+		t.Fatalf("期望降级后分析成功，实际错误: %v", err)
+	}
+	if detail.AnalystName != "MockAnalyst" || !detail.IsFallback {
+		t.Errorf("期望降级到模拟分析器，实际 %+v", detail)
+	}
+}
+
+func TestDoAnalysis_Rejected(t *testing.T) {
+	ts := newTestService(t, fakebot.WithReplies(fakebot.Reply{Content: aiContent}))
+	ctx := context.Background()
+
+	imageId, b, err := ts.images.Upload(ctx, "a.jpg", testImage(t, 64, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ts.DoAnalysis(ctx, 1, 1, imageId, b)
+	if err != exception.ErrImageLowQuality {
+		t.Fatalf("期望因图片过小被拒绝，实际 %v", err)
+	}
+	if ts.bot.Calls() != 0 {
+		t.Errorf("被拒绝的图片不应请求 ai-bot，实际 %d 次", ts.bot.Calls())
+	}
+	if ts.oss.Len() != 0 {
+		t.Errorf("被拒绝的图片应从 oss 删除，剩余 %d 个对象", ts.oss.Len())
+	}
+}
@@ -0,0 +1,129 @@
+// File:		stats.go
+// Created by:	Hoven
+// Created on:	2025-06-13
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysis
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+)
+
+const (
+	// trendPeriods 分数趋势包含的周期数，包括当前周期
+	trendPeriods = 12
+	topTagsLimit = 5
+)
+
+type StatsPeriod string
+
+const (
+	StatsByWeek  StatsPeriod = "week"
+	StatsByMonth StatsPeriod = "month"
+)
+
+func ParseStatsPeriod(s string) (StatsPeriod, error) {
+	switch p := StatsPeriod(s); p {
+	case "":
+		return StatsByWeek, nil
+	case StatsByWeek, StatsByMonth:
+		return p, nil
+	default:
+		return "", exception.ErrInvalidStatsPeriod
+	}
+}
+
+// Since 包含当前周期在内最近 n 个周期的起始时间，周从周一开始
+func (p StatsPeriod) Since(now time.Time, n int) time.Time {
+	y, m, d := now.Date()
+	if p == StatsByMonth {
+		return time.Date(y, m-time.Month(n-1), 1, 0, 0, 0, 0, now.Location())
+	}
+
+	weekday := (int(now.Weekday()) + 6) % 7
+	return time.Date(y, m, d-weekday-7*(n-1), 0, 0, 0, 0, now.Location())
+}
+
+// TrendPoint 一个周期内的报告数量和分数
+type TrendPoint struct {
+	// Period 按周统计时为当周周一的日期 2006-01-02，按月统计时为 2006-01
+	Period    string  `json:"period"`
+	Count     int64   `json:"count"`
+	AvgScore  float64 `json:"avgScore"`
+	BestScore int     `json:"bestScore"`
+}
+
+// LabelAverage 评分项（五官、气质等）在所有报告中的平均分
+type LabelAverage struct {
+	Label    string  `json:"label"`
+	AvgScore float64 `json:"avgScore"`
+	Count    int64   `json:"count"`
+}
+
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// UserStats 用户所有报告的汇总统计
+type UserStats struct {
+	Count         int64           `json:"count"`
+	BestScore     int             `json:"bestScore"`
+	AvgScore      float64         `json:"avgScore"`
+	LatestScore   int             `json:"latestScore"`
+	LatestDate    time.Time       `json:"latestDate,omitempty"`
+	Period        StatsPeriod     `json:"period"`
+	Trend         []*TrendPoint   `json:"trend"`
+	LabelAverages []*LabelAverage `json:"labelAverages"`
+	TopTags       []*TagCount     `json:"topTags"`
+}
+
+// GetStats 统计用户的报告数量和分数，分数趋势只包含最近 trendPeriods 个周期中有报告的周期
+func (as *DefaultAnalysisService) GetStats(ctx context.Context, userId int, period StatsPeriod) (*UserStats, error) {
+	stats, err := as.repo.GetUserStats(ctx, userId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getUserStats. userId=%v", userId)
+	}
+
+	stats.Period = period
+	stats.Trend = make([]*TrendPoint, 0)
+	stats.LabelAverages = make([]*LabelAverage, 0)
+	stats.TopTags = make([]*TagCount, 0)
+	if stats.Count == 0 {
+		return stats, nil
+	}
+	stats.AvgScore = round(stats.AvgScore, 1)
+
+	trend, err := as.repo.GetScoreTrend(ctx, userId, period, period.Since(time.Now(), trendPeriods))
+	if err != nil {
+		return nil, errors.Wrapf(err, "getScoreTrend. userId=%v", userId)
+	}
+	for _, t := range trend {
+		t.AvgScore = round(t.AvgScore, 1)
+	}
+
+	labels, err := as.repo.GetLabelAverages(ctx, userId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getLabelAverages. userId=%v", userId)
+	}
+	for _, l := range labels {
+		l.AvgScore = round(l.AvgScore, 1)
+	}
+
+	tags, err := as.repo.GetTopTags(ctx, userId, topTagsLimit)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getTopTags. userId=%v", userId)
+	}
+
+	stats.Trend = append(stats.Trend, trend...)
+	stats.LabelAverages = append(stats.LabelAverages, labels...)
+	stats.TopTags = append(stats.TopTags, tags...)
+	return stats, nil
+}
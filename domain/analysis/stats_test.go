@@ -0,0 +1,131 @@
+package analysis
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func (r *memRepo) GetUserStats(_ context.Context, userId int) (*UserStats, error) {
+	stats := new(UserStats)
+	for _, d := range r.find(func(d *AnalysisDetail) bool { return d.UserID == userId }) {
+		stats.Count++
+		stats.BestScore = max(stats.BestScore, d.Score)
+		stats.AvgScore += float64(d.Score)
+		if !d.Date.Before(stats.LatestDate) {
+			stats.LatestScore, stats.LatestDate = d.Score, d.Date
+		}
+	}
+	if stats.Count > 0 {
+		stats.AvgScore /= float64(stats.Count)
+	}
+	return stats, nil
+}
+
+func (r *memRepo) GetScoreTrend(_ context.Context, userId int, period StatsPeriod, since time.Time) ([]*TrendPoint, error) {
+	points := make(map[string]*TrendPoint)
+	for _, d := range r.find(func(d *AnalysisDetail) bool { return d.UserID == userId && !d.Date.Before(since) }) {
+		key := period.Since(d.Date, 1).Format("2006-01-02")
+		if period == StatsByMonth {
+			key = d.Date.Format("2006-01")
+		}
+		p, ok := points[key]
+		if !ok {
+			p = &TrendPoint{Period: key}
+			points[key] = p
+		}
+		p.AvgScore = (p.AvgScore*float64(p.Count) + float64(d.Score)) / float64(p.Count+1)
+		p.Count++
+		p.BestScore = max(p.BestScore, d.Score)
+	}
+
+	trend := slices.Collect(maps.Values(points))
+	slices.SortFunc(trend, func(a, b *TrendPoint) int { return strings.Compare(a.Period, b.Period) })
+	return trend, nil
+}
+
+func (r *memRepo) GetLabelAverages(_ context.Context, userId int) ([]*LabelAverage, error) {
+	labels := make(map[string]*LabelAverage)
+	for _, d := range r.find(func(d *AnalysisDetail) bool { return d.UserID == userId }) {
+		for _, sd := range d.ScoreDetails {
+			l, ok := labels[sd.Label]
+			if !ok {
+				l = &LabelAverage{Label: sd.Label}
+				labels[sd.Label] = l
+			}
+			l.AvgScore = (l.AvgScore*float64(l.Count) + float64(sd.Score)) / float64(l.Count+1)
+			l.Count++
+		}
+	}
+
+	ret := slices.Collect(maps.Values(labels))
+	slices.SortFunc(ret, func(a, b *LabelAverage) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Label, b.Label))
+	})
+	return ret, nil
+}
+
+func (r *memRepo) GetTopTags(_ context.Context, userId int, limit int) ([]*TagCount, error) {
+	tags := make(map[string]*TagCount)
+	for _, d := range r.find(func(d *AnalysisDetail) bool { return d.UserID == userId }) {
+		for _, tag := range d.Tags {
+			if _, ok := tags[tag]; !ok {
+				tags[tag] = &TagCount{Tag: tag}
+			}
+			tags[tag].Count++
+		}
+	}
+
+	ret := slices.Collect(maps.Values(tags))
+	slices.SortFunc(ret, func(a, b *TagCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Tag, b.Tag))
+	})
+	return ret[:min(limit, len(ret))], nil
+}
+
+func TestGetStats(t *testing.T) {
+	ts := newTestService(t)
+	ctx := context.Background()
+
+	stats, err := ts.GetStats(ctx, 1, StatsByWeek)
+	if err != nil || stats.Count != 0 || stats.Trend == nil || stats.TopTags == nil {
+		t.Fatalf("没有报告时统计不符合预期: %+v, %v", stats, err)
+	}
+
+	now := time.Now()
+	details := []*AnalysisDetail{
+		{UserID: 1, Score: 80, Date: now.AddDate(0, 0, -14), Tags: []string{"清秀", "阳光"},
+			ScoreDetails: []ScoreDetail{{Label: "五官", Score: 84}, {Label: "气质", Score: 76}}},
+		{UserID: 1, Score: 91, Date: now.AddDate(0, 0, -1), Tags: []string{"清秀"},
+			ScoreDetails: []ScoreDetail{{Label: "五官", Score: 90}, {Label: "气质", Score: 93}}},
+		{UserID: 1, Score: 85, Date: now, Tags: []string{"清秀", "温柔"},
+			ScoreDetails: []ScoreDetail{{Label: "五官", Score: 87}}},
+		{UserID: 2, Score: 99, Date: now, Tags: []string{"阳光"}},
+	}
+	for _, d := range details {
+		if err := ts.repo.CreateAnalysisDetail(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err = ts.GetStats(ctx, 1, StatsByWeek)
+	if err != nil {
+		t.Fatalf("期望统计成功，实际错误: %v", err)
+	}
+	if stats.Count != 3 || stats.BestScore != 91 || stats.AvgScore != 85.3 || stats.LatestScore != 85 {
+		t.Errorf("汇总统计不符合预期: %+v", stats)
+	}
+	if len(stats.Trend) < 2 || stats.Trend[len(stats.Trend)-1].Period != StatsByWeek.Since(now, 1).Format("2006-01-02") {
+		t.Errorf("分数趋势不符合预期: %+v", stats.Trend)
+	}
+	if len(stats.LabelAverages) != 2 || stats.LabelAverages[0].Label != "五官" || stats.LabelAverages[0].AvgScore != 87 {
+		t.Errorf("评分项平均分不符合预期: %+v", stats.LabelAverages)
+	}
+	if len(stats.TopTags) != 3 || stats.TopTags[0].Tag != "清秀" || stats.TopTags[0].Count != 3 {
+		t.Errorf("常见标签不符合预期: %+v", stats.TopTags)
+	}
+}
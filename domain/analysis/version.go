@@ -0,0 +1,205 @@
+// File:		version.go
+// Created by:	Hoven
+// Created on:	2025-06-09
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/pkg/errors"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"gorm.io/gorm"
+)
+
+// AnalysisVersion 同一份报告的一次分析结果，报告本身展示的是主版本的结果
+type AnalysisVersion struct {
+	ID           int           `json:"id,omitempty"`
+	ReportID     int           `json:"reportId"`
+	Version      int           `json:"version"`
+	IsPrimary    bool          `json:"isPrimary"`
+	Score        int           `json:"score"`
+	Percentile   int           `json:"percentile"`
+	Description  string        `json:"description"`
+	Tags         []string      `json:"tags"`
+	ScoreDetails []ScoreDetail `json:"scoreDetails"`
+	AnalyisType  int           `json:"analyisType"`
+	Date         time.Time     `json:"date"`
+
+	AnalystName   string `json:"-"`
+	IsFallback    bool   `json:"-"`
+	PromptVersion string `json:"-"`
+	Model         string `json:"-"`
+	Experiment    string `json:"-"`
+	Variant       string `json:"-"`
+	// Contributions 组合分析器各成员的结果
+	Contributions []Contribution `json:"-"`
+}
+
+// TypedAnalyst 支持指定分析器类型的分析器，用户重新分析时指定类型需要
+type TypedAnalyst interface {
+	DoAnalysisByType(ctx context.Context, typ analyst.AnalystType, imageName, imageUrl string, image []byte) (*analyst.Result, error)
+}
+
+// snapshotVersion 以报告当前的结果生成一个版本
+func snapshotVersion(detail *AnalysisDetail) *AnalysisVersion {
+	return &AnalysisVersion{
+		ReportID:      detail.ID,
+		Version:       detail.Version,
+		Score:         detail.Score,
+		Percentile:    detail.Percentile,
+		Description:   detail.Description,
+		Tags:          detail.Tags,
+		ScoreDetails:  detail.ScoreDetails,
+		AnalyisType:   detail.AnalyisType,
+		Date:          detail.Date,
+		AnalystName:   detail.AnalystName,
+		IsFallback:    detail.IsFallback,
+		PromptVersion: detail.PromptVersion,
+		Model:         detail.Model,
+		Experiment:    detail.Experiment,
+		Variant:       detail.Variant,
+		Contributions: detail.Contributions,
+	}
+}
+
+// applyTo 将版本的结果设置为报告的主结果
+func (v *AnalysisVersion) applyTo(detail *AnalysisDetail) {
+	detail.Version = v.Version
+	detail.Score = v.Score
+	detail.Percentile = v.Percentile
+	detail.Description = v.Description
+	detail.Tags = v.Tags
+	detail.ScoreDetails = v.ScoreDetails
+	detail.AnalyisType = v.AnalyisType
+	detail.AnalystName = v.AnalystName
+	detail.IsFallback = v.IsFallback
+	detail.PromptVersion = v.PromptVersion
+	detail.Model = v.Model
+	detail.Experiment = v.Experiment
+	detail.Variant = v.Variant
+	detail.Contributions = v.Contributions
+}
+
+func (as *DefaultAnalysisService) getUserDetail(ctx context.Context, userId, reportId int) (*AnalysisDetail, error) {
+	detail, err := as.repo.GetUserDetail(ctx, userId, reportId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.ErrDetailNotFound
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "getUserDetail. userId=%v, reportId=%v", userId, reportId)
+	}
+
+	return detail, nil
+}
+
+// reportVersions 返回报告的所有版本，从未重新分析过的报告只有一个由当前结果生成的版本
+func (as *DefaultAnalysisService) reportVersions(ctx context.Context, detail *AnalysisDetail) ([]*AnalysisVersion, error) {
+	versions, err := as.repo.GetVersions(ctx, detail.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getVersions. reportId=%v", detail.ID)
+	}
+	if len(versions) == 0 {
+		versions = []*AnalysisVersion{snapshotVersion(detail)}
+	}
+
+	for _, v := range versions {
+		v.IsPrimary = v.Version == detail.Version
+	}
+	return versions, nil
+}
+
+func (as *DefaultAnalysisService) GetVersions(ctx context.Context, userId, reportId int) ([]*AnalysisVersion, error) {
+	detail, err := as.getUserDetail(ctx, userId, reportId)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.reportVersions(ctx, detail)
+}
+
+// reanalyzeImage 未指定类型时与新上传的图片一样选择分析器，指定类型时只使用该类型的分析器且不参与实验
+func (as *DefaultAnalysisService) reanalyzeImage(ctx context.Context, detail *AnalysisDetail, typ *analyst.AnalystType, b []byte) (*AnalysisDetail, error) {
+	if typ == nil {
+		return as.analyzeImage(ctx, detail.UserID, detail.Gender, detail.ImageUrl, b)
+	}
+
+	ta, ok := as.analyst.(TypedAnalyst)
+	if !ok {
+		return nil, exception.ErrInvalidAnalystType
+	}
+
+	d, err := ta.DoAnalysisByType(ctx, *typ, detail.ImageUrl, detail.ImageUrl, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.newDetail(ctx, d, detail.Gender)
+}
+
+// Reanalyze 使用原图重新分析，结果作为报告的新版本保存并设置为主版本，
+// 第一次重新分析时先将原来的结果保存为第一个版本，版本号由 repo 在保存时分配
+func (as *DefaultAnalysisService) Reanalyze(ctx context.Context, userId, reportId int, typ *analyst.AnalystType) (*AnalysisDetail, error) {
+	detail, err := as.getUserDetail(ctx, userId, reportId)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := as.images.Load(ctx, detail.ImageUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := as.reanalyzeImage(ctx, detail, typ, b)
+	if err != nil {
+		return nil, err
+	}
+	result.ID = detail.ID
+	result.Date = time.Now()
+
+	original := snapshotVersion(detail)
+	version := snapshotVersion(result)
+	version.applyTo(detail)
+	if err := as.repo.CreatePrimaryVersion(ctx, detail, original, version); err != nil {
+		return nil, err
+	}
+	plog.Debugc(ctx, "report: %v reanalyzed as version: %v", reportId, version.Version)
+	as.markFavorites(ctx, userId, detail)
+
+	return as.convertImage(ctx, detail), nil
+}
+
+func (as *DefaultAnalysisService) SetPrimaryVersion(ctx context.Context, userId, reportId, version int) (*AnalysisDetail, error) {
+	detail, err := as.getUserDetail(ctx, userId, reportId)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := as.reportVersions(ctx, detail)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range versions {
+		if v.Version != version {
+			continue
+		}
+
+		if !v.IsPrimary {
+			v.applyTo(detail)
+			if err := as.repo.UpdateAnalysisDetail(ctx, detail); err != nil {
+				return nil, err
+			}
+		}
+		as.markFavorites(ctx, userId, detail)
+		return as.convertImage(ctx, detail), nil
+	}
+
+	return nil, exception.ErrVersionNotFound
+}
@@ -0,0 +1,200 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"github.com/yazl-tech/beauty-rating-server/pkg/fakebot"
+)
+
+// memVersions 内存中的报告版本存储
+type memVersions struct {
+	mu       sync.Mutex
+	versions []*AnalysisVersion
+}
+
+// add 调用方需持有 r.mu，与数据库的唯一索引一样拒绝重复的版本号
+func (r *memVersions) add(version *AnalysisVersion) error {
+	for _, v := range r.versions {
+		if v.ReportID == version.ReportID && v.Version == version.Version {
+			return fmt.Errorf("duplicate version: %v", version.Version)
+		}
+	}
+	cp := *version
+	cp.ID = len(r.versions) + 1
+	version.ID = cp.ID
+	r.versions = append(r.versions, &cp)
+	return nil
+}
+
+// removeVersions 删除被删除报告的所有版本
+func (r *memVersions) removeVersions(reportId int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.versions = slices.DeleteFunc(r.versions, func(v *AnalysisVersion) bool { return v.ReportID == reportId })
+}
+
+// CreatePrimaryVersion 持有版本存储的锁完成分配版本号、保存版本和更新报告，与数据库中锁定报告行的效果相同
+func (r *memRepo) CreatePrimaryVersion(ctx context.Context, detail *AnalysisDetail, original, version *AnalysisVersion) error {
+	r.memVersions.mu.Lock()
+	defer r.memVersions.mu.Unlock()
+
+	if len(r.find(func(d *AnalysisDetail) bool { return d.ID == detail.ID })) == 0 {
+		return exception.ErrDetailNotFound
+	}
+
+	latest := 0
+	for _, v := range r.versions {
+		if v.ReportID == detail.ID {
+			latest = max(latest, v.Version)
+		}
+	}
+	if latest == 0 {
+		if err := r.memVersions.add(original); err != nil {
+			return err
+		}
+		latest = original.Version
+	}
+
+	version.Version = latest + 1
+	if err := r.memVersions.add(version); err != nil {
+		return err
+	}
+
+	detail.Version = version.Version
+	return r.UpdateAnalysisDetail(ctx, detail)
+}
+
+func (r *memVersions) GetVersions(_ context.Context, reportId int) ([]*AnalysisVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var versions []*AnalysisVersion
+	for _, v := range r.versions {
+		if v.ReportID == reportId {
+			cp := *v
+			versions = append(versions, &cp)
+		}
+	}
+	return versions, nil
+}
+
+func TestReanalyze(t *testing.T) {
+	ts := newTestService(t, fakebot.WithReplies(fakebot.Reply{Content: aiContent}))
+	ctx := context.Background()
+
+	imageId, b, err := ts.images.Upload(ctx, "a.jpg", testImage(t, 300, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+	detail, err := ts.DoAnalysis(ctx, 1, 1, imageId, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typ := analyst.TypeMock
+	again, err := ts.Reanalyze(ctx, 1, detail.ID, &typ)
+	if err != nil {
+		t.Fatalf("期望重新分析成功，实际错误: %v", err)
+	}
+	if again.ID != detail.ID || again.Version != 2 || again.AnalystName != "MockAnalyst" {
+		t.Errorf("期望同一报告的第 2 个版本由模拟分析器产出，实际 %+v", again)
+	}
+
+	versions, err := ts.GetVersions(ctx, 1, detail.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].Score != 91 || versions[0].IsPrimary || !versions[1].IsPrimary {
+		t.Fatalf("历史版本不符合预期: %+v", versions)
+	}
+
+	primary, err := ts.SetPrimaryVersion(ctx, 1, detail.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if primary.Version != 1 || primary.Score != 91 || primary.AnalystName != "AiAnalyst" {
+		t.Errorf("期望恢复第 1 个版本的结果，实际 %+v", primary)
+	}
+
+	if _, err := ts.SetPrimaryVersion(ctx, 1, detail.ID, 3); err != exception.ErrVersionNotFound {
+		t.Errorf("期望版本不存在，实际 %v", err)
+	}
+	if _, err := ts.Reanalyze(ctx, 2, detail.ID, nil); err != exception.ErrDetailNotFound {
+		t.Errorf("期望不能重新分析其他用户的报告，实际 %v", err)
+	}
+}
+
+func TestReanalyze_Concurrent(t *testing.T) {
+	ts := newTestService(t, fakebot.WithReplies(fakebot.Reply{Content: aiContent}))
+	ctx := context.Background()
+
+	imageId, b, err := ts.images.Upload(ctx, "a.jpg", testImage(t, 300, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+	detail, err := ts.DoAnalysis(ctx, 1, 1, imageId, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 8
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan error, n)
+		typ  = analyst.TypeMock
+	)
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := ts.Reanalyze(ctx, 1, detail.ID, &typ)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("期望并发重新分析都成功，实际错误: %v", err)
+		}
+	}
+
+	versions, err := ts.GetVersions(ctx, 1, detail.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != n+1 || versions[0].AnalystName != "AiAnalyst" {
+		t.Fatalf("期望原始版本加 %d 个新版本，实际 %d 个", n, len(versions))
+	}
+	for i, v := range versions {
+		if v.Version != i+1 {
+			t.Errorf("期望版本号连续，第 %d 个版本号为 %d", i+1, v.Version)
+		}
+	}
+
+	// 切回旧版本后再重新分析，新版本号仍然在最大版本号之后
+	if _, err := ts.SetPrimaryVersion(ctx, 1, detail.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+	again, err := ts.Reanalyze(ctx, 1, detail.ID, &typ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Version != n+2 {
+		t.Errorf("期望新版本号为 %d，实际 %d", n+2, again.Version)
+	}
+
+	if err := ts.DeleteAnalysis(ctx, 1, detail.ID); err != nil {
+		t.Fatal(err)
+	}
+	if versions, _ := ts.repo.GetVersions(ctx, detail.ID); len(versions) != 0 {
+		t.Errorf("期望删除报告时同时删除版本，剩余 %d 个", len(versions))
+	}
+}
@@ -0,0 +1,248 @@
+// File:		worker.go
+// Created by:	Hoven
+// Created on:	2025-05-29
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysis
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/pkg/errors"
+)
+
+// jobSweepInterval 扫描未完成任务的间隔，入队失败或被中断的任务由扫描重新入队
+const jobSweepInterval = 30 * time.Second
+
+type jobTask struct {
+	job *AnalysisJob
+	// image 为空时从 oss 重新读取，用于恢复重启前未完成的任务
+	image []byte
+}
+
+// SubmitAnalysisJob 持久化分析任务后立即返回，任务由 RunJobWorkers 启动的 worker 异步执行
+func (as *DefaultAnalysisService) SubmitAnalysisJob(ctx context.Context, userId, gender int, imageId string, b []byte) (*AnalysisJob, error) {
+	job := &AnalysisJob{
+		UserID:   userId,
+		ImageUrl: imageId,
+		Gender:   gender,
+		Status:   JobPending,
+	}
+	if err := as.jobRepo.CreateJob(ctx, job); err != nil {
+		return nil, errors.Wrap(err, "createJob")
+	}
+
+	as.enqueueJob(ctx, &jobTask{job: job, image: b})
+	return job, nil
+}
+
+func (as *DefaultAnalysisService) GetAnalysisJob(ctx context.Context, userId, jobId int) (*AnalysisJob, error) {
+	job, err := as.jobRepo.GetUserJob(ctx, userId, jobId)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status != JobSucceeded {
+		return job, nil
+	}
+
+	detail, err := as.repo.GetUserDetail(ctx, userId, job.DetailID)
+	if err != nil {
+		return nil, err
+	}
+	job.Detail = as.convertImage(ctx, detail)
+
+	return job, nil
+}
+
+// enqueueJob 队列已满时不阻塞请求，任务保持 pending 等待下一次扫描
+func (as *DefaultAnalysisService) enqueueJob(ctx context.Context, task *jobTask) bool {
+	select {
+	case as.jobQueue <- task:
+		return true
+	default:
+		plog.Warnc(ctx, "analysis job queue is full, job: %v will be picked up by next sweep", task.job.ID)
+		return false
+	}
+}
+
+// RunJobWorkers 启动固定数量的 worker 执行分析任务，并定期恢复未完成的任务，直到 ctx 结束
+func (as *DefaultAnalysisService) RunJobWorkers(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for i := 0; i < as.beautyConf.JobWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			as.jobWorker(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(jobSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		as.sweepJobs(ctx)
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (as *DefaultAnalysisService) jobWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case task := <-as.jobQueue:
+			as.runJob(ctx, task)
+		}
+	}
+}
+
+// sweepJobs 重新入队长时间未被执行的 pending 任务；执行中的任务会定期刷新更新时间，
+// running 任务超过 JobStaleTimeout 没有更新时视为被重启中断，重试次数未用完的重新置为 pending，否则标记为失败
+func (as *DefaultAnalysisService) sweepJobs(ctx context.Context) {
+	now := time.Now()
+	staleBefore := now.Add(-time.Duration(as.beautyConf.JobStaleTimeout) * time.Second)
+
+	jobs, err := as.jobRepo.GetUnfinishedJobs(ctx, now.Add(-jobSweepInterval))
+	if err != nil {
+		plog.Errorc(ctx, "get unfinished jobs failed: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if job.Status == JobRunning {
+			if job.UpdatedAt.After(staleBefore) {
+				continue
+			}
+
+			if job.Attempts >= jobMaxAttempts {
+				plog.Warnc(ctx, "job: %v interrupted %d times, mark as failed", job.ID, job.Attempts)
+				job.fail(errors.New("job interrupted too many times"))
+				if _, err := as.jobRepo.FinishJob(ctx, job); err != nil {
+					plog.Errorc(ctx, "fail job: %v failed: %v", job.ID, err)
+				}
+				continue
+			}
+
+			plog.Infoc(ctx, "job: %v interrupted, requeue it", job.ID)
+			if err := as.jobRepo.RequeueJob(ctx, job); err != nil {
+				plog.Errorc(ctx, "requeue job: %v failed: %v", job.ID, err)
+				continue
+			}
+		}
+
+		if !as.enqueueJob(ctx, &jobTask{job: job}) {
+			return
+		}
+	}
+}
+
+// jobHeartbeatInterval running 任务刷新更新时间的间隔，远小于 JobStaleTimeout 以免被扫描当作中断
+func (as *DefaultAnalysisService) jobHeartbeatInterval() time.Duration {
+	return max(time.Duration(as.beautyConf.JobStaleTimeout)*time.Second/3, time.Second/10)
+}
+
+// heartbeatJob 任务执行期间定期刷新更新时间，发现任务已被其他 worker 重新领取时取消本次执行
+func (as *DefaultAnalysisService) heartbeatJob(ctx context.Context, cancel context.CancelCauseFunc, job *AnalysisJob) {
+	ticker := time.NewTicker(as.jobHeartbeatInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		alive, err := as.jobRepo.HeartbeatJob(ctx, job)
+		if err != nil {
+			plog.Warnc(ctx, "heartbeat job failed: %v", err)
+			continue
+		}
+		if !alive {
+			cancel(errJobLeaseLost)
+			return
+		}
+	}
+}
+
+// jobDetail 执行任务的分析，之前的执行已经保存了报告但没来得及完成任务时直接使用该报告，
+// 重新执行不会产生重复的报告
+func (as *DefaultAnalysisService) jobDetail(ctx context.Context, task *jobTask) (*AnalysisDetail, error) {
+	job := task.job
+
+	detail, err := as.repo.GetDetailByJob(ctx, job.ID)
+	if err != nil || detail != nil {
+		return detail, err
+	}
+
+	image := task.image
+	if image == nil {
+		if image, err = as.images.Load(ctx, job.ImageUrl); err != nil {
+			return nil, err
+		}
+	}
+
+	return as.createDetail(ctx, job.UserID, job.Gender, job.ImageUrl, image, job.ID)
+}
+
+func (as *DefaultAnalysisService) runJob(ctx context.Context, task *jobTask) {
+	job := task.job
+	ctx = plog.With(ctx, "jobId", job.ID)
+
+	claimed, err := as.jobRepo.ClaimJob(ctx, job)
+	if err != nil {
+		plog.Errorc(ctx, "claim job failed: %v", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	jobCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go as.heartbeatJob(jobCtx, cancel, job)
+
+	detail, err := as.jobDetail(jobCtx, task)
+
+	// 任务已由其他 worker 重新执行，放弃本次结果，已经保存的报告由重新执行的一方使用
+	if errors.Is(context.Cause(jobCtx), errJobLeaseLost) {
+		plog.Warnc(ctx, "job lease lost, abandon this attempt: %d", job.Attempts)
+		return
+	}
+
+	// 服务退出导致的中断不计为失败，重新置为 pending 等待重启后继续执行
+	if err != nil && ctx.Err() != nil {
+		plog.Infoc(ctx, "job interrupted by shutdown, requeue it")
+		if err := as.jobRepo.RequeueJob(context.WithoutCancel(ctx), job); err != nil {
+			plog.Errorc(ctx, "requeue job failed: %v", err)
+		}
+		return
+	}
+
+	if err != nil {
+		plog.Errorc(ctx, "run analysis job failed: %v", err)
+		job.fail(err)
+	} else {
+		job.succeed(detail.ID)
+	}
+
+	finished, err := as.jobRepo.FinishJob(ctx, job)
+	if err != nil {
+		plog.Errorc(ctx, "finish job failed: %v", err)
+	} else if !finished {
+		plog.Warnc(ctx, "job lease lost before finish, attempt: %d, detail: %v will be reused by the next attempt", job.Attempts, job.DetailID)
+	}
+}
@@ -0,0 +1,267 @@
+package analysis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/fakebot"
+	"gorm.io/gorm"
+)
+
+// memJobRepo 内存中的 JobRepo 实现，与数据库一样以执行次数作为租约
+type memJobRepo struct {
+	mu   sync.Mutex
+	jobs []*AnalysisJob
+}
+
+// update 在 match 成立时修改任务，返回是否修改
+func (r *memJobRepo) update(jobId int, match func(j *AnalysisJob) bool, apply func(j *AnalysisJob)) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, j := range r.jobs {
+		if j.ID == jobId && match(j) {
+			apply(j)
+			j.UpdatedAt = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memJobRepo) leased(job *AnalysisJob) func(j *AnalysisJob) bool {
+	return func(j *AnalysisJob) bool { return j.Status == JobRunning && j.Attempts == job.Attempts }
+}
+
+func (r *memJobRepo) get(jobId int) *AnalysisJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, j := range r.jobs {
+		if j.ID == jobId {
+			cp := *j
+			return &cp
+		}
+	}
+	return nil
+}
+
+func (r *memJobRepo) CreateJob(_ context.Context, job *AnalysisJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job.ID = len(r.jobs) + 1
+	job.CreatedAt, job.UpdatedAt = time.Now(), time.Now()
+	saved := *job
+	r.jobs = append(r.jobs, &saved)
+	return nil
+}
+
+func (r *memJobRepo) GetJob(_ context.Context, jobId int) (*AnalysisJob, error) {
+	if job := r.get(jobId); job != nil {
+		return job, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memJobRepo) GetUserJob(ctx context.Context, userId, jobId int) (*AnalysisJob, error) {
+	job, err := r.GetJob(ctx, jobId)
+	if err != nil || job.UserID != userId {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return job, nil
+}
+
+func (r *memJobRepo) ClaimJob(_ context.Context, job *AnalysisJob) (bool, error) {
+	claimed := r.update(job.ID, func(j *AnalysisJob) bool {
+		return j.Status == JobPending && j.Attempts == job.Attempts
+	}, func(j *AnalysisJob) {
+		j.Status = JobRunning
+		j.Attempts++
+	})
+	if claimed {
+		job.Status = JobRunning
+		job.Attempts++
+	}
+	return claimed, nil
+}
+
+func (r *memJobRepo) HeartbeatJob(_ context.Context, job *AnalysisJob) (bool, error) {
+	return r.update(job.ID, r.leased(job), func(*AnalysisJob) {}), nil
+}
+
+func (r *memJobRepo) FinishJob(_ context.Context, job *AnalysisJob) (bool, error) {
+	return r.update(job.ID, r.leased(job), func(j *AnalysisJob) {
+		j.Status, j.DetailID, j.Error, j.Message = job.Status, job.DetailID, job.Error, job.Message
+	}), nil
+}
+
+func (r *memJobRepo) RequeueJob(_ context.Context, job *AnalysisJob) error {
+	r.update(job.ID, r.leased(job), func(j *AnalysisJob) { j.Status = JobPending })
+	return nil
+}
+
+func (r *memJobRepo) GetUnfinishedJobs(_ context.Context, before time.Time) ([]*AnalysisJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var jobs []*AnalysisJob
+	for _, j := range r.jobs {
+		if !j.Finished() && j.UpdatedAt.Before(before) {
+			cp := *j
+			jobs = append(jobs, &cp)
+		}
+	}
+	return jobs, nil
+}
+
+func newJobTestService(t *testing.T, latency time.Duration) (*testService, *memJobRepo) {
+	ts := newTestService(t, fakebot.WithLatency(latency), fakebot.WithReplies(fakebot.Reply{Content: aiContent}))
+	ts.beautyConf.JobStaleTimeout = 1
+
+	jobs := &memJobRepo{}
+	ts.jobRepo = jobs
+	return ts, jobs
+}
+
+func TestRunJob_Heartbeat(t *testing.T) {
+	ts, jobs := newJobTestService(t, 1500*time.Millisecond)
+	ctx := context.Background()
+
+	imageId, b, err := ts.images.Upload(ctx, "a.jpg", testImage(t, 300, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := &AnalysisJob{UserID: 1, ImageUrl: imageId, Status: JobPending}
+	if err := jobs.CreateJob(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ts.runJob(ctx, &jobTask{job: job, image: b})
+	}()
+
+	// 执行时间超过 JobStaleTimeout 的任务依靠心跳保持更新，不会被扫描当作中断
+	time.Sleep(1200 * time.Millisecond)
+	if running := jobs.get(job.ID); running.Status != JobRunning || time.Since(running.UpdatedAt) > time.Second {
+		t.Errorf("期望执行中的任务持续刷新更新时间，实际 %+v", running)
+	}
+
+	<-done
+	if finished := jobs.get(job.ID); finished.Status != JobSucceeded || finished.DetailID == 0 || finished.Attempts != 1 {
+		t.Errorf("期望任务执行成功，实际 %+v", finished)
+	}
+}
+
+func TestRunJob_LeaseLost(t *testing.T) {
+	ts, jobs := newJobTestService(t, time.Second)
+	ctx := context.Background()
+
+	imageId, b, err := ts.images.Upload(ctx, "a.jpg", testImage(t, 300, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := &AnalysisJob{UserID: 1, ImageUrl: imageId, Status: JobPending}
+	if err := jobs.CreateJob(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ts.runJob(ctx, &jobTask{job: job, image: b})
+	}()
+
+	// 模拟任务被重新入队并由其他 worker 领取
+	time.Sleep(100 * time.Millisecond)
+	stale := jobs.get(job.ID)
+	if err := jobs.RequeueJob(ctx, stale); err != nil {
+		t.Fatal(err)
+	}
+	if claimed, _ := jobs.ClaimJob(ctx, stale); !claimed {
+		t.Fatal("期望其他 worker 领取任务成功")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(800 * time.Millisecond):
+		t.Fatal("期望租约失效后尽快放弃本次执行")
+	}
+
+	if current := jobs.get(job.ID); current.Status != JobRunning || current.Attempts != 2 {
+		t.Errorf("旧的 worker 不应更新任务，实际 %+v", current)
+	}
+	if len(ts.repo.find(func(*AnalysisDetail) bool { return true })) != 0 {
+		t.Error("放弃的执行不应生成报告")
+	}
+}
+
+// leaseLostOnFinish 模拟报告保存后、完成任务前任务被扫描重新入队
+type leaseLostOnFinish struct {
+	*memJobRepo
+}
+
+func (r *leaseLostOnFinish) FinishJob(ctx context.Context, job *AnalysisJob) (bool, error) {
+	if err := r.RequeueJob(ctx, job); err != nil {
+		return false, err
+	}
+	return r.memJobRepo.FinishJob(ctx, job)
+}
+
+func TestRunJob_LeaseLostAfterCreate(t *testing.T) {
+	ts, jobs := newJobTestService(t, 0)
+	ctx := context.Background()
+
+	imageId, b, err := ts.images.Upload(ctx, "a.jpg", testImage(t, 300, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := &AnalysisJob{UserID: 1, ImageUrl: imageId, Status: JobPending}
+	if err := jobs.CreateJob(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+
+	ts.jobRepo = &leaseLostOnFinish{jobs}
+	ts.runJob(ctx, &jobTask{job: job, image: b})
+	if current := jobs.get(job.ID); current.Status != JobPending {
+		t.Fatalf("期望任务被重新入队，实际 %+v", current)
+	}
+	created := ts.repo.find(func(*AnalysisDetail) bool { return true })
+	if len(created) != 1 || created[0].JobID != job.ID {
+		t.Fatalf("期望第一次执行保存了报告，实际 %+v", created)
+	}
+
+	// 重新执行时使用已经保存的报告，不会产生重复的报告
+	ts.jobRepo = jobs
+	ts.runJob(ctx, &jobTask{job: jobs.get(job.ID)})
+	if finished := jobs.get(job.ID); finished.Status != JobSucceeded || finished.DetailID != created[0].ID || finished.Attempts != 2 {
+		t.Errorf("期望重新执行使用第一次保存的报告，实际 %+v", finished)
+	}
+	if details := ts.repo.find(func(*AnalysisDetail) bool { return true }); len(details) != 1 {
+		t.Errorf("期望同一任务只有一份报告，实际 %d 份", len(details))
+	}
+}
+
+func TestCreateDetail_DuplicateJob(t *testing.T) {
+	ts := newTestService(t, fakebot.WithReplies(fakebot.Reply{Content: aiContent}))
+	ctx := context.Background()
+
+	imageId, b, err := ts.images.Upload(ctx, "a.jpg", testImage(t, 300, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 租约失效的执行与重新领取的执行同时保存报告时，后保存的一方使用已有的报告
+	first, err := ts.createDetail(ctx, 1, 0, imageId, b, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ts.createDetail(ctx, 1, 0, imageId, b, 7)
+	if err != nil || second.ID != first.ID {
+		t.Errorf("期望返回同一任务已保存的报告，实际: %+v, %v", second, err)
+	}
+}
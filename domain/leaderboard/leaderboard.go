@@ -0,0 +1,124 @@
+// File:		leaderboard.go
+// Created by:	Hoven
+// Created on:	2025-06-14
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package leaderboard
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+)
+
+const (
+	// DefaultApp 未携带 X-App-Name 时使用的榜单
+	DefaultApp = "default"
+
+	defaultBoardSize = 20
+	maxBoardSize     = 100
+)
+
+// AppName 返回小程序对应的榜单名称
+func AppName(name string) string {
+	if name = strings.TrimSpace(name); name == "" {
+		return DefaultApp
+	}
+	return name
+}
+
+type Window string
+
+const (
+	WindowDaily  Window = "daily"
+	WindowWeekly Window = "weekly"
+	WindowAll    Window = "all"
+)
+
+func ParseWindow(s string) (Window, error) {
+	switch w := Window(s); w {
+	case WindowDaily, WindowWeekly, WindowAll:
+		return w, nil
+	default:
+		return "", exception.ErrInvalidLeaderboardWindow
+	}
+}
+
+// Since 榜单统计的起始时间，日榜从当天零点开始，周榜从本周一零点开始，总榜返回零值
+func (w Window) Since(now time.Time) time.Time {
+	y, m, d := now.Date()
+	switch w {
+	case WindowDaily:
+		return time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	case WindowWeekly:
+		weekday := (int(now.Weekday()) + 6) % 7
+		return time.Date(y, m, d-weekday, 0, 0, 0, 0, now.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+// Member 加入榜单的用户，昵称和头像在加入及查询自己的排名时从用户资料同步
+type Member struct {
+	AppName  string    `json:"appName"`
+	UserID   int       `json:"userId"`
+	Name     string    `json:"name"`
+	Avatar   string    `json:"avatar"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+// Entry 榜单中的一名用户，BestScore 为用户在统计窗口内所有报告的最高分
+type Entry struct {
+	Rank      int    `json:"rank"`
+	UserID    int    `json:"userId"`
+	Name      string `json:"name"`
+	Avatar    string `json:"avatar"`
+	BestScore int    `json:"bestScore"`
+}
+
+type Board struct {
+	AppName string `json:"appName"`
+	Window  Window `json:"window"`
+	// Total 统计窗口内有报告的榜单用户数
+	Total   int64    `json:"total"`
+	Entries []*Entry `json:"entries"`
+}
+
+type MyRank struct {
+	OptedIn bool `json:"optedIn"`
+	// Entry 统计窗口内没有报告时为空
+	Entry *Entry `json:"entry,omitempty"`
+	Total int64  `json:"total"`
+}
+
+// Scope 参与排名的报告范围
+type Scope struct {
+	AppName string
+	// Since 为零值时不限时间
+	Since time.Time
+	// AiOnly 只统计 AI 分析器给出且没有降级的结果
+	AiOnly bool
+}
+
+// Repo 榜单按用户在范围内的最高分降序排列，分数相同时按 userId 升序；重新分析过的报告按第一次分析的结果统计，
+// 用户不能通过反复重新分析刷高榜单分数
+type Repo interface {
+	// SaveMember 加入榜单，已加入时更新昵称和头像
+	SaveMember(ctx context.Context, member *Member) error
+	// GetMember 未加入榜单时返回 nil
+	GetMember(ctx context.Context, appName string, userId int) (*Member, error)
+	DeleteMember(ctx context.Context, appName string, userId int) error
+	// GetTopEntries 返回排名前 limit 的用户，不填充 Rank
+	GetTopEntries(ctx context.Context, scope *Scope, limit int) ([]*Entry, error)
+	// GetEntry 返回用户在榜单中的最高分，不填充 Rank，窗口内没有报告时返回 nil
+	GetEntry(ctx context.Context, scope *Scope, userId int) (*Entry, error)
+	// CountAhead 统计排在最高分为 score 的 userId 之前的用户数
+	CountAhead(ctx context.Context, scope *Scope, score, userId int) (int64, error)
+	// CountRanked 统计窗口内有报告的榜单用户数
+	CountRanked(ctx context.Context, scope *Scope) (int64, error)
+}
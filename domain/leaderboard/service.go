@@ -0,0 +1,141 @@
+// File:		service.go
+// Created by:	Hoven
+// Created on:	2025-06-14
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package leaderboard
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/pkg/errors"
+	"github.com/yazl-tech/beauty-rating-server/config"
+	"github.com/yazl-tech/beauty-rating-server/domain/user"
+)
+
+type Service interface {
+	OptIn(ctx context.Context, appName string, u *user.User) (*Member, error)
+	OptOut(ctx context.Context, appName string, userId int) error
+	GetBoard(ctx context.Context, appName string, window Window, limit int) (*Board, error)
+	GetMyRank(ctx context.Context, appName string, window Window, u *user.User) (*MyRank, error)
+}
+
+var _ Service = (*DefaultLeaderboardService)(nil)
+
+// DefaultLeaderboardService 用户资料服务只能查询当前登录用户的资料，
+// 因此昵称和头像保存在榜单成员中，由用户本人加入榜单或查询排名时更新
+type DefaultLeaderboardService struct {
+	beautyConf *config.BeautyConfig
+	repo       Repo
+}
+
+func NewLeaderboardService(beautyConf *config.BeautyConfig, repo Repo) *DefaultLeaderboardService {
+	return &DefaultLeaderboardService{beautyConf: beautyConf, repo: repo}
+}
+
+func (ls *DefaultLeaderboardService) scope(appName string, window Window) *Scope {
+	return &Scope{
+		AppName: appName,
+		Since:   window.Since(time.Now()),
+		AiOnly:  ls.beautyConf.LeaderboardAiOnly,
+	}
+}
+
+func (ls *DefaultLeaderboardService) OptIn(ctx context.Context, appName string, u *user.User) (*Member, error) {
+	member := &Member{
+		AppName: AppName(appName),
+		UserID:  u.ID,
+		Name:    u.Name,
+		Avatar:  u.Avatar,
+	}
+	if err := ls.repo.SaveMember(ctx, member); err != nil {
+		return nil, errors.Wrapf(err, "saveMember. app=%v, userId=%v", member.AppName, u.ID)
+	}
+
+	return member, nil
+}
+
+func (ls *DefaultLeaderboardService) OptOut(ctx context.Context, appName string, userId int) error {
+	return ls.repo.DeleteMember(ctx, AppName(appName), userId)
+}
+
+func (ls *DefaultLeaderboardService) GetBoard(ctx context.Context, appName string, window Window, limit int) (*Board, error) {
+	if limit <= 0 {
+		limit = defaultBoardSize
+	}
+	limit = min(limit, maxBoardSize)
+
+	board := &Board{AppName: AppName(appName), Window: window}
+	scope := ls.scope(board.AppName, window)
+
+	entries, err := ls.repo.GetTopEntries(ctx, scope, limit)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getTopEntries. app=%v, window=%v", board.AppName, window)
+	}
+	for i, e := range entries {
+		e.Rank = i + 1
+	}
+	board.Entries = append(make([]*Entry, 0, len(entries)), entries...)
+
+	board.Total, err = ls.repo.CountRanked(ctx, scope)
+	if err != nil {
+		return nil, errors.Wrapf(err, "countRanked. app=%v, window=%v", board.AppName, window)
+	}
+
+	return board, nil
+}
+
+// syncProfile 用户修改过昵称或头像时更新榜单中的展示信息
+func (ls *DefaultLeaderboardService) syncProfile(ctx context.Context, member *Member, u *user.User) {
+	if member.Name == u.Name && member.Avatar == u.Avatar {
+		return
+	}
+
+	member.Name = u.Name
+	member.Avatar = u.Avatar
+	if err := ls.repo.SaveMember(ctx, member); err != nil {
+		plog.Warnc(ctx, "sync leaderboard member: %v profile failed: %v", u.ID, err)
+	}
+}
+
+// GetMyRank 查询用户在榜单中的名次，名次为排在用户之前的人数加一，不受榜单展示数量限制
+func (ls *DefaultLeaderboardService) GetMyRank(ctx context.Context, appName string, window Window, u *user.User) (*MyRank, error) {
+	appName = AppName(appName)
+	member, err := ls.repo.GetMember(ctx, appName, u.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getMember. app=%v, userId=%v", appName, u.ID)
+	}
+	if member == nil {
+		return &MyRank{OptedIn: false}, nil
+	}
+	ls.syncProfile(ctx, member, u)
+
+	scope := ls.scope(appName, window)
+	rank := &MyRank{OptedIn: true}
+	rank.Total, err = ls.repo.CountRanked(ctx, scope)
+	if err != nil {
+		return nil, errors.Wrapf(err, "countRanked. app=%v, window=%v", appName, window)
+	}
+
+	entry, err := ls.repo.GetEntry(ctx, scope, u.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getEntry. app=%v, userId=%v", appName, u.ID)
+	}
+	if entry == nil {
+		return rank, nil
+	}
+
+	ahead, err := ls.repo.CountAhead(ctx, scope, entry.BestScore, u.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "countAhead. app=%v, userId=%v", appName, u.ID)
+	}
+	entry.Rank = int(ahead) + 1
+	rank.Entry = entry
+
+	return rank, nil
+}
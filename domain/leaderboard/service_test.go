@@ -0,0 +1,216 @@
+package leaderboard
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/config"
+	"github.com/yazl-tech/beauty-rating-server/domain/user"
+)
+
+type score struct {
+	userId int
+	score  int
+	date   time.Time
+	// ai AI 分析器给出且没有降级的分数
+	ai bool
+}
+
+// memRepo 内存中的 Repo 实现
+type memRepo struct {
+	members []*Member
+	scores  []score
+}
+
+func (r *memRepo) SaveMember(_ context.Context, member *Member) error {
+	for _, m := range r.members {
+		if m.AppName == member.AppName && m.UserID == member.UserID {
+			m.Name, m.Avatar = member.Name, member.Avatar
+			return nil
+		}
+	}
+	saved := *member
+	r.members = append(r.members, &saved)
+	return nil
+}
+
+func (r *memRepo) GetMember(_ context.Context, appName string, userId int) (*Member, error) {
+	for _, m := range r.members {
+		if m.AppName == appName && m.UserID == userId {
+			copied := *m
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memRepo) DeleteMember(_ context.Context, appName string, userId int) error {
+	r.members = slices.DeleteFunc(r.members, func(m *Member) bool { return m.AppName == appName && m.UserID == userId })
+	return nil
+}
+
+func (r *memRepo) entries(scope *Scope) []*Entry {
+	var entries []*Entry
+	for _, m := range r.members {
+		if m.AppName != scope.AppName {
+			continue
+		}
+
+		var e *Entry
+		for _, s := range r.scores {
+			if s.userId != m.UserID || s.date.Before(scope.Since) || scope.AiOnly && !s.ai {
+				continue
+			}
+			if e == nil || s.score > e.BestScore {
+				e = &Entry{UserID: m.UserID, Name: m.Name, Avatar: m.Avatar, BestScore: s.score}
+			}
+		}
+		if e != nil {
+			entries = append(entries, e)
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b *Entry) int {
+		return cmp.Or(cmp.Compare(b.BestScore, a.BestScore), cmp.Compare(a.UserID, b.UserID))
+	})
+	return entries
+}
+
+func (r *memRepo) GetTopEntries(_ context.Context, scope *Scope, limit int) ([]*Entry, error) {
+	entries := r.entries(scope)
+	return entries[:min(limit, len(entries))], nil
+}
+
+func (r *memRepo) GetEntry(_ context.Context, scope *Scope, userId int) (*Entry, error) {
+	for _, e := range r.entries(scope) {
+		if e.UserID == userId {
+			return e, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memRepo) CountAhead(_ context.Context, scope *Scope, score, userId int) (int64, error) {
+	var count int64
+	for _, e := range r.entries(scope) {
+		if e.BestScore > score || e.BestScore == score && e.UserID < userId {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memRepo) CountRanked(_ context.Context, scope *Scope) (int64, error) {
+	return int64(len(r.entries(scope))), nil
+}
+
+func TestWindowSince(t *testing.T) {
+	now := time.Date(2025, 6, 15, 10, 30, 0, 0, time.Local) // 周日
+
+	if got := WindowDaily.Since(now); !got.Equal(time.Date(2025, 6, 15, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("日榜起始时间不符合预期: %v", got)
+	}
+	if got := WindowWeekly.Since(now); !got.Equal(time.Date(2025, 6, 9, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("周榜起始时间不符合预期: %v", got)
+	}
+	if got := WindowAll.Since(now); !got.IsZero() {
+		t.Errorf("总榜不应限制起始时间: %v", got)
+	}
+	if _, err := ParseWindow("monthly"); err == nil {
+		t.Error("期望未知的榜单类型返回错误")
+	}
+}
+
+func TestLeaderboard(t *testing.T) {
+	ctx := context.Background()
+	repo := new(memRepo)
+	ls := NewLeaderboardService(&config.BeautyConfig{}, repo)
+
+	now := time.Now()
+	old := now.AddDate(0, 0, -30)
+	repo.scores = []score{
+		{1, 95, old, false}, {1, 70, now, false},
+		{2, 88, now, false},
+		{3, 88, now, false}, {3, 60, old, false},
+		{4, 99, now, false},
+	}
+
+	for _, id := range []int{1, 2, 3} {
+		if _, err := ls.OptIn(ctx, "", &user.User{ID: id, Name: "用户"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := ls.OptIn(ctx, "other", &user.User{ID: 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := ls.GetBoard(ctx, "", WindowDaily, 2)
+	if err != nil {
+		t.Fatalf("期望获取榜单成功，实际错误: %v", err)
+	}
+	if board.AppName != DefaultApp || board.Total != 3 || len(board.Entries) != 2 ||
+		board.Entries[0].UserID != 2 || board.Entries[1].UserID != 3 || board.Entries[1].Rank != 2 {
+		t.Errorf("日榜不符合预期: %+v", board)
+	}
+
+	board, err = ls.GetBoard(ctx, "", WindowAll, 0)
+	if err != nil || len(board.Entries) != 3 || board.Entries[0].UserID != 1 || board.Entries[0].BestScore != 95 {
+		t.Errorf("总榜不符合预期: %+v, %v", board, err)
+	}
+
+	// 排名在榜单展示数量之外的用户也能查询到名次，查询时同步最新的昵称
+	rank, err := ls.GetMyRank(ctx, "", WindowDaily, &user.User{ID: 1, Name: "新昵称"})
+	if err != nil || !rank.OptedIn || rank.Entry == nil || rank.Entry.Rank != 3 || rank.Total != 3 {
+		t.Fatalf("我的排名不符合预期: %+v, %v", rank, err)
+	}
+	if m, _ := repo.GetMember(ctx, DefaultApp, 1); m.Name != "新昵称" {
+		t.Errorf("期望同步最新的昵称，实际: %v", m.Name)
+	}
+
+	if err := ls.OptOut(ctx, "", 1); err != nil {
+		t.Fatal(err)
+	}
+	rank, err = ls.GetMyRank(ctx, "", WindowDaily, &user.User{ID: 1})
+	if err != nil || rank.OptedIn || rank.Entry != nil {
+		t.Errorf("退出榜单后排名不符合预期: %+v, %v", rank, err)
+	}
+}
+
+func TestLeaderboard_AiOnly(t *testing.T) {
+	ctx := context.Background()
+	repo := new(memRepo)
+	conf := new(config.BeautyConfig)
+	ls := NewLeaderboardService(conf, repo)
+
+	now := time.Now()
+	repo.scores = []score{
+		{1, 95, now, false}, {1, 80, now, true},
+		{2, 85, now, true},
+		{3, 90, now, false},
+	}
+	for _, id := range []int{1, 2, 3} {
+		if _, err := ls.OptIn(ctx, "", &user.User{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 默认统计所有报告
+	board, err := ls.GetBoard(ctx, "", WindowAll, 0)
+	if err != nil || board.Total != 3 || board.Entries[0].UserID != 1 || board.Entries[0].BestScore != 95 {
+		t.Fatalf("默认榜单不符合预期: %+v, %v", board, err)
+	}
+
+	conf.LeaderboardAiOnly = true
+	board, err = ls.GetBoard(ctx, "", WindowAll, 0)
+	if err != nil || board.Total != 2 || board.Entries[0].UserID != 2 || board.Entries[1].BestScore != 80 {
+		t.Fatalf("只统计 AI 分数的榜单不符合预期: %+v, %v", board, err)
+	}
+
+	rank, err := ls.GetMyRank(ctx, "", WindowAll, &user.User{ID: 3})
+	if err != nil || !rank.OptedIn || rank.Entry != nil || rank.Total != 2 {
+		t.Errorf("没有 AI 分数的用户不应有名次，实际: %+v, %v", rank, err)
+	}
+}
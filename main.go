@@ -9,6 +9,8 @@
 package main
 
 import (
+	"context"
+
 	"github.com/go-puzzles/puzzles/cores"
 	"github.com/go-puzzles/puzzles/dialer/grpc"
 	"github.com/go-puzzles/puzzles/pflags"
@@ -18,11 +20,13 @@ import (
 	"github.com/yazl-tech/beauty-rating-server/config"
 	"github.com/yazl-tech/beauty-rating-server/domain/user"
 	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
+	"github.com/yazl-tech/beauty-rating-server/pkg/metrics"
 	"github.com/yazl-tech/beauty-rating-server/pkg/oss/minio"
 	"github.com/yazl-tech/beauty-rating-server/service"
 
 	consulpuzzle "github.com/go-puzzles/puzzles/cores/puzzles/consul-puzzle"
 	httppuzzle "github.com/go-puzzles/puzzles/cores/puzzles/http-puzzle"
+	analysisRepo "github.com/yazl-tech/beauty-rating-server/pkg/dal/analysis"
 )
 
 var (
@@ -46,11 +50,13 @@ func main() {
 
 	plog.Debugf("beautyConf: %v", plog.Jsonify(beautyConf))
 
-	authCoreConn, err := grpc.DialGrpc(beautyConf.AuthCoreSrv)
+	authCoreGrpcConn, err := grpc.DialGrpc(beautyConf.AuthCoreSrv)
 	plog.PanicError(err)
+	authCoreConn := metrics.NewClientConn(beautyConf.AuthCoreSrv, authCoreGrpcConn)
 
-	aiBotConn, err := grpc.DialGrpc(beautyConf.AiBotSrv)
+	aiBotGrpcConn, err := grpc.DialGrpc(beautyConf.AiBotSrv)
 	plog.PanicError(err)
+	aiBotConn := metrics.NewClientConn(beautyConf.AiBotSrv, aiBotGrpcConn)
 
 	minioClient := minio.NewMinioOss(minioConf)
 
@@ -58,6 +64,12 @@ func main() {
 	plog.PanicError(pgorm.AutoMigrate(mysqlConf))
 	db := pgorm.GetDbByConf(mysqlConf)
 
+	migrated, err := analysisRepo.NewAnalysisRepo(db).MigrateFavorites(context.Background())
+	plog.PanicError(err)
+	if migrated > 0 {
+		plog.Infof("migrate legacy favorites finished, %d favorites migrated", migrated)
+	}
+
 	beautyService := service.NewBeautyRatingService(db, minioClient, authCoreConn, aiBotConn, beautyConf, wechatConf)
 	router := api.SetupRouter(beautyConf, wechatConf, authCoreConn, beautyService)
 
@@ -66,6 +78,8 @@ func main() {
 		consulpuzzle.WithConsulRegister(),
 		httppuzzle.WithCoreHttpCORS(),
 		httppuzzle.WithCoreHttpPuzzle(beautyConf.ApiPrefix, router),
+		metrics.WithMetricsServer(beautyConf.MetricsPort),
+		cores.WithDaemonNameWorker("analysisJobWorker", beautyService.RunAnalysisJobs),
 	)
 	plog.PanicError(cores.Start(coreSrv, beautyConf.ApiPort))
 }
@@ -11,12 +11,12 @@ package ai
 import (
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"path/filepath"
-	"regexp"
+	"net/http"
 	"strings"
+	"time"
 
+	"github.com/go-puzzles/puzzles/plog"
 	"github.com/pkg/errors"
 	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
 	"github.com/yazl-tech/beauty-rating-server/pkg/random"
@@ -27,39 +27,79 @@ import (
 
 var _ analyst.Analyst = (*AiAnalyst)(nil)
 
-var extMimeTypeMap = map[string]string{
-	".jpg":  "image/jpeg",
-	".jpeg": "image/jpeg",
-	".apng": "image/png",
-	".png":  "image/png",
-	".gif":  "image/gif",
-	".webp": "image/webp",
-	".bmp":  "image/bmp",
-	".tiff": "image/tiff",
-	".tif":  "image/tiff",
-	".ico":  "image/x-icon",
-	".dib":  "image/bmp",
-	".icns": "image/icns",
-	".sgi":  "image/sgi",
-	".j2c":  "image/jp2",
-	".j2k":  "image/jp2",
-	".jp2":  "image/jp2",
-	".jpc":  "image/jp2",
-	".jpf":  "image/jp2",
-	".jpx":  "image/jp2",
+type AiOption func(*AiAnalyst)
+
+// WithPrompt 指定分析时使用的系统提示词，默认使用内置的 DefaultPromptVersion
+func WithPrompt(prompt *Prompt) AiOption {
+	return func(a *AiAnalyst) {
+		a.prompt = prompt
+	}
+}
+
+// WithName 设置分析器名称，同时配置多个模型时用于区分，默认为 AiAnalyst
+func WithName(name string) AiOption {
+	return func(a *AiAnalyst) {
+		a.name = name
+	}
+}
+
+// WithTemperature 设置模型的采样温度，默认为 1
+func WithTemperature(temperature float32) AiOption {
+	return func(a *AiAnalyst) {
+		a.temperature = temperature
+	}
+}
+
+// WithTimeout 设置单次分析（包括追问）的超时时间，为 0 时不限制
+func WithTimeout(timeout time.Duration) AiOption {
+	return func(a *AiAnalyst) {
+		a.timeout = timeout
+	}
+}
+
+// WithMaxReask 设置返回结果无法修复时最多追问模型重新生成的次数
+func WithMaxReask(n int) AiOption {
+	return func(a *AiAnalyst) {
+		a.maxReask = n
+	}
 }
 
 type AiAnalyst struct {
+	name         string
 	model        string
+	temperature  float32
+	timeout      time.Duration
+	prompt       *Prompt
+	validator    *resultValidator
+	maxReask     int
 	doubaoClient doubaopb.DoubaoHandlerClient
 }
 
-func NewAiAnalyst(model string, doubaoClient doubaopb.DoubaoHandlerClient) *AiAnalyst {
-	return &AiAnalyst{model: model, doubaoClient: doubaoClient}
+func NewAiAnalyst(model string, doubaoClient doubaopb.DoubaoHandlerClient, opts ...AiOption) *AiAnalyst {
+	a := &AiAnalyst{
+		name:         "AiAnalyst",
+		model:        model,
+		temperature:  1,
+		doubaoClient: doubaoClient,
+		maxReask:     1,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	if a.prompt == nil {
+		a.prompt = MustDefaultPrompt()
+	}
+	a.validator = newResultValidator(a.prompt.Vars)
+	return a
 }
 
 func (a *AiAnalyst) Name() string {
-	return "AiAnalyst"
+	return a.name
+}
+
+func (a *AiAnalyst) Model() string {
+	return a.model
 }
 
 func (a *AiAnalyst) Typ() analyst.AnalystType {
@@ -75,24 +115,24 @@ func (a *AiAnalyst) calcBase64(b []byte) string {
 	return string(buf)
 }
 
-func (a *AiAnalyst) generateImageUrl(imageName string, image []byte) string {
+// generateImageUrl 根据图片内容而不是文件名判断 MIME 类型
+func (a *AiAnalyst) generateImageUrl(image []byte) string {
 	base64Encode := a.calcBase64(image)
 
-	ext := strings.ToLower(filepath.Ext(imageName))
-	mimeType := "image/png"
-	if mt, ok := extMimeTypeMap[ext]; ok {
-		mimeType = mt
+	mimeType := http.DetectContentType(image)
+	if !strings.HasPrefix(mimeType, "image/") {
+		mimeType = "image/png"
 	}
 	return fmt.Sprintf("data:%s;base64,%s", mimeType, base64Encode)
 }
 
-func (a *AiAnalyst) packRequest(imageUrl string) *botpb.ChatRequest {
-	return &botpb.ChatRequest{
+func (a *AiAnalyst) packRequest(imageUrl string, followUps ...*botpb.Message) *botpb.ChatRequest {
+	req := &botpb.ChatRequest{
 		Messages: []*botpb.Message{
 			{
 				Role: botpb.Message_system,
 				Content: &botpb.Message_StringContent{
-					StringContent: systemPrompt,
+					StringContent: a.prompt.Content,
 				},
 			},
 			{
@@ -109,7 +149,34 @@ func (a *AiAnalyst) packRequest(imageUrl string) *botpb.ChatRequest {
 		},
 		Options: &botpb.ChatOptions{
 			Model:       a.model,
-			Temperature: 1,
+			Temperature: a.temperature,
+		},
+	}
+	req.Messages = append(req.Messages, followUps...)
+
+	return req
+}
+
+// reaskMessages 将模型上一次的输出及其问题反馈给模型，要求重新生成
+func (a *AiAnalyst) reaskMessages(content string, err error) []*botpb.Message {
+	problem := err.Error()
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		problem = strings.Join(ve.Problems, "\n")
+	}
+
+	return []*botpb.Message{
+		{
+			Role: botpb.Message_assistant,
+			Content: &botpb.Message_StringContent{
+				StringContent: content,
+			},
+		},
+		{
+			Role: botpb.Message_user,
+			Content: &botpb.Message_StringContent{
+				StringContent: "上面的输出不符合要求：\n" + problem + "\n请修正以上问题，严格按照约定的 JSON 格式重新输出完整结果。",
+			},
 		},
 	}
 }
@@ -132,24 +199,23 @@ func (a *AiAnalyst) choiceTags(tags []string) []string {
 	return tags[:random.RandomNumber(4, 6)]
 }
 
-func (a *AiAnalyst) parseAiResp(choices []*botpb.Choice) (*analyst.Result, error) {
+func (a *AiAnalyst) responseContent(choices []*botpb.Choice) (string, error) {
 	if len(choices) == 0 {
-		return nil, fmt.Errorf("empty choices")
+		return "", fmt.Errorf("empty choices")
 	}
 
-	choice := choices[0]
-	strContent := choice.GetMessage().GetStringContent()
+	return choices[0].GetMessage().GetStringContent(), nil
+}
 
-	re := regexp.MustCompile(`(?s)\{.*\}`)
-	jsonMatch := re.FindString(strContent)
-	if jsonMatch == "" {
-		return nil, fmt.Errorf("no valid JSON found in response")
+func (a *AiAnalyst) parseAiResp(content string) (*analyst.Result, error) {
+	ret, err := extractJSON(content)
+	if err != nil {
+		return nil, err
 	}
 
-	ret := &analyst.Result{}
-	err := json.Unmarshal([]byte(jsonMatch), ret)
+	ret, err = a.validator.Validate(ret)
 	if err != nil {
-		return nil, errors.Wrap(err, "unmarshalChoice")
+		return nil, err
 	}
 	ret.Tags = a.choiceTags(ret.Tags)
 
@@ -157,18 +223,40 @@ func (a *AiAnalyst) parseAiResp(choices []*botpb.Choice) (*analyst.Result, error
 }
 
 func (a *AiAnalyst) DoAnalysis(ctx context.Context, imageName, imageUrl string, image []byte) (*analyst.Result, error) {
-	imageUrl = a.generateImageUrl(imageName, image)
-
-	resp, err := a.doubaoClient.ChatCompletions(ctx, a.packRequest(imageUrl))
-	if err != nil {
-		return nil, err
+	if a.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.timeout)
+		defer cancel()
 	}
 
-	ret, err := a.parseAiResp(resp.GetChoices())
-	if err != nil {
-		return nil, err
-	}
+	imageUrl = a.generateImageUrl(image)
 
-	ret.AnalystType = analyst.TypeAi
-	return ret, nil
+	var followUps []*botpb.Message
+	for attempt := 0; ; attempt++ {
+		resp, err := a.doubaoClient.ChatCompletions(ctx, a.packRequest(imageUrl, followUps...))
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := a.responseContent(resp.GetChoices())
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := a.parseAiResp(content)
+		if err != nil {
+			if attempt >= a.maxReask {
+				return nil, errors.Wrapf(err, "parseAiResp after %d attempts", attempt+1)
+			}
+
+			plog.Warnc(ctx, "ai response invalid, reask model. attempt: %d, error: %v", attempt+1, err)
+			followUps = append(followUps, a.reaskMessages(content, err)...)
+			continue
+		}
+
+		ret.AnalystType = analyst.TypeAi
+		ret.PromptVersion = a.prompt.Version
+		ret.Model = a.model
+		return ret, nil
+	}
 }
@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+	"github.com/yazl-tech/beauty-rating-server/pkg/fakebot"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const validContent = "结果如下：\n```json\n" + `{
+	"score": 91,
+	"description": "整体气质清新",
+	"tags": ["清秀", "温柔", "阳光", "自然", "干净", "甜美"],
+	"scoreDetails": [
+		{"label": "五官", "score": 90, "desc": "五官端正"},
+		{"label": "气质", "score": 93, "desc": "气质温和"},
+		{"label": "妆容", "score": 88, "desc": "妆容自然"},
+		{"label": "发型", "score": 86, "desc": "发型清爽"}
+	]
+}` + "\n```"
+
+var pngImage = []byte("\x89PNG\r\n\x1a\nfake")
+
+func TestAiAnalyst_DoAnalysis(t *testing.T) {
+	bot := fakebot.Start(t, fakebot.WithReplies(fakebot.Reply{Content: validContent}))
+	a := NewAiAnalyst("fake-model", bot.Client())
+
+	ret, err := a.DoAnalysis(context.Background(), "a.png", "", pngImage)
+	if err != nil {
+		t.Fatalf("期望分析成功，实际错误: %v", err)
+	}
+	if ret.AnalystType != analyst.TypeAi || ret.Score != 91 || len(ret.Details) != 4 || ret.PromptVersion != DefaultPromptVersion {
+		t.Errorf("分析结果不符合预期: %+v", ret)
+	}
+
+	req := bot.Requests()[0]
+	if req.GetOptions().GetModel() != "fake-model" {
+		t.Errorf("请求模型不符合预期: %v", req.GetOptions().GetModel())
+	}
+	if url := req.GetMessages()[1].GetTypeContent().GetImageUrl().GetUrl(); !strings.HasPrefix(url, "data:image/png;base64,") {
+		t.Errorf("图片地址不符合预期: %v", url)
+	}
+}
+
+func TestAiAnalyst_ModelOptions(t *testing.T) {
+	bot := fakebot.Start(t, fakebot.WithReplies(fakebot.Reply{Content: validContent}))
+	a := NewAiAnalyst("premium-model", bot.Client(), WithName("premium"), WithTemperature(0.2))
+
+	if a.Name() != "premium" {
+		t.Errorf("分析器名称不符合预期: %v", a.Name())
+	}
+
+	ret, err := a.DoAnalysis(context.Background(), "a.png", "", pngImage)
+	if err != nil {
+		t.Fatalf("期望分析成功，实际错误: %v", err)
+	}
+	if ret.Model != "premium-model" {
+		t.Errorf("结果中的模型不符合预期: %v", ret.Model)
+	}
+	if temperature := bot.Requests()[0].GetOptions().GetTemperature(); temperature != 0.2 {
+		t.Errorf("请求温度不符合预期: %v", temperature)
+	}
+}
+
+func TestAiAnalyst_Reask(t *testing.T) {
+	bot := fakebot.Start(t, fakebot.WithReplies(
+		fakebot.Reply{Content: `{"score": 90, "tags": ["清秀"]}`},
+		fakebot.Reply{Content: validContent},
+	))
+	a := NewAiAnalyst("fake-model", bot.Client())
+
+	ret, err := a.DoAnalysis(context.Background(), "a.png", "", pngImage)
+	if err != nil {
+		t.Fatalf("期望追问后成功，实际错误: %v", err)
+	}
+	if ret.Score != 91 {
+		t.Errorf("期望使用追问后的结果，实际 %+v", ret)
+	}
+
+	requests := bot.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("期望请求 2 次，实际 %d 次", len(requests))
+	}
+	followUp := requests[1].GetMessages()
+	if len(followUp) != 4 || !strings.Contains(followUp[3].GetStringContent(), "缺少“五官”评分项") {
+		t.Errorf("追问消息不符合预期: %v", followUp)
+	}
+}
+
+func TestAiAnalyst_ReaskExhausted(t *testing.T) {
+	bot := fakebot.Start(t, fakebot.WithReplies(
+		fakebot.Reply{Content: "无法识别"},
+		fakebot.Reply{Content: "还是无法识别"},
+	))
+	a := NewAiAnalyst("fake-model", bot.Client())
+
+	if _, err := a.DoAnalysis(context.Background(), "a.png", "", pngImage); err == nil {
+		t.Fatal("期望多次无效回复后失败")
+	}
+	if bot.Calls() != 2 {
+		t.Errorf("期望请求 2 次，实际 %d 次", bot.Calls())
+	}
+}
+
+func TestAiAnalyst_BotError(t *testing.T) {
+	bot := fakebot.Start(t, fakebot.WithError(status.Error(codes.Unavailable, "ai-bot down")))
+	a := NewAiAnalyst("fake-model", bot.Client())
+
+	_, err := a.DoAnalysis(context.Background(), "a.png", "", pngImage)
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("期望透传 ai-bot 错误，实际 %v", err)
+	}
+}
@@ -8,65 +8,224 @@
 
 package ai
 
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultPromptVersion 未配置时使用的提示词版本
+const DefaultPromptVersion = "v1"
+
 const (
-	prefixMsg = `你要以颜值评分专家的视角，对提供的人物图像进行颜值分析。
-
-分析结果需要有区分度，并且表述要浅显易懂、接地气。
-
-对于五官、气质、妆容、发型这几个考量方面，要分别给出评分（评分范围为 70 - 100 分），同时对每个方面给出简要描述，描述字数需控制在 25 个字。具体考量方向如下：
-
-- 五官：观察眼睛、鼻子、嘴巴等的形态是否协调、美观。
-- 气质：感受人物整体给人的感觉，如优雅、活泼等。
-- 妆容：查看化妆的效果和搭配是否合适。
-- 发型：关注发型的样式以及与整体的适配度。
-
-整体颜值得分需综合各方面的情况给出，同样在 70 - 100 分的范围内。
-
-要用一段描述性的文字概括人物的颜值特点，并且分别提炼出:
-- 4 个能体现人物颜值风格的 2 个字的标签。
-- 4 个能体现人物颜值风格的 4 个字的标签。
-一共 8 个标签，你需要注意这 8 个标签的意思不要重复。
-
-特殊情况：
-- 对于女性照片，评分范围为 85-100 分。
-- 对于婴幼儿照片，评分范围为 90-100 分。
-- 对于宠物照片，评分范围为 80-100 分。
-- 尽量不要给范围内的最低分。
-
-请将最终的分析结果以如下 JSON 格式输出：`
-
-	jsonTemplate = `{
-    "score": [整体颜值得分],
-    "description": "[对人物颜值特点的描述]",
-    "tags": [
-        "[体现人物颜值风格的标签1]",
-        "[体现人物颜值风格的标签2]",
-        "[体现人物颜值风格的标签3]",
-		...
-    ],
-    "scoreDetails": [
-        {
-            "label": "五官",
-            "score": [五官得分],
-            "desc": "[五官评分依据，15 字左右]"
-        },
-        {
-            "label": "气质",
-            "score": [气质得分],
-            "desc": "[气质评分依据，15 字左右]"
-        },
-        {
-            "label": "妆容",
-            "score": [妆容得分],
-            "desc": "[妆容评分依据，15 字左右]"
-        },
-        {
-            "label": "发型",
-            "score": [发型得分],
-            "desc": "[发型评分依据，15 字左右]"
-        }
-    ]
-}`
-
-	systemPrompt = prefixMsg + "\n```\n" + jsonTemplate + "\n```"
+	promptExt = ".tmpl"
+	varsExt   = ".json"
 )
+
+//go:embed prompts/*.tmpl prompts/*.json
+var builtinPrompts embed.FS
+
+type PromptLabel struct {
+	Name string `json:"name"`
+	Hint string `json:"hint"`
+}
+
+// PromptVars 渲染提示词模板时可用的变量，同时也是校验 AI 返回结果的依据，
+// 与模板放在同一目录下，文件名为版本号加 .json
+type PromptVars struct {
+	Labels        []PromptLabel `json:"labels"`
+	MinScore      int           `json:"minScore"`
+	MaxScore      int           `json:"maxScore"`
+	ShortTagCount int           `json:"shortTagCount"`
+	LongTagCount  int           `json:"longTagCount"`
+	// MinTagCount 和 MaxTagCount 为可接受的有效标签数量，未配置时分别为 TagCount 的一半和 TagCount
+	MinTagCount int `json:"minTagCount"`
+	MaxTagCount int `json:"maxTagCount"`
+}
+
+// DefaultPromptVars 内置默认版本提示词的变量
+func DefaultPromptVars() *PromptVars {
+	return MustDefaultPrompt().Vars
+}
+
+func (pv *PromptVars) validate() error {
+	if len(pv.Labels) == 0 {
+		return errors.New("labels is empty")
+	}
+	if pv.MinScore >= pv.MaxScore {
+		return fmt.Errorf("invalid score range: %d - %d", pv.MinScore, pv.MaxScore)
+	}
+	if pv.TagCount() <= 0 {
+		return errors.New("tag count must be positive")
+	}
+	if minTags, maxTags := pv.TagRange(); minTags <= 0 || minTags > maxTags {
+		return fmt.Errorf("invalid tag count range: %d - %d", minTags, maxTags)
+	}
+	return nil
+}
+
+func (pv *PromptVars) LabelNames() []string {
+	names := make([]string, 0, len(pv.Labels))
+	for _, l := range pv.Labels {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+func (pv *PromptVars) TagCount() int {
+	return pv.ShortTagCount + pv.LongTagCount
+}
+
+// TagRange 返回可接受的有效标签数量范围
+func (pv *PromptVars) TagRange() (int, int) {
+	minTags, maxTags := pv.MinTagCount, pv.MaxTagCount
+	if maxTags == 0 {
+		maxTags = pv.TagCount()
+	}
+	if minTags == 0 {
+		minTags = (pv.TagCount() + 1) / 2
+	}
+	return minTags, maxTags
+}
+
+// Prompt 为渲染后的系统提示词及其版本号
+type Prompt struct {
+	Version string
+	Vars    *PromptVars
+	Content string
+}
+
+var promptFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// PromptRegistry 管理所有版本的提示词模板及其变量，版本号即模板文件名（不含扩展名）
+type PromptRegistry struct {
+	templates map[string]*template.Template
+	vars      map[string]*PromptVars
+}
+
+// NewPromptRegistry 加载内置模板，dir 不为空时再加载该目录下的 *.tmpl 和 *.json，同名版本以目录中的为准
+func NewPromptRegistry(dir string) (*PromptRegistry, error) {
+	pr := &PromptRegistry{
+		templates: make(map[string]*template.Template),
+		vars:      make(map[string]*PromptVars),
+	}
+
+	if err := pr.loadFS(builtinPrompts, "prompts"); err != nil {
+		return nil, errors.Wrap(err, "loadBuiltinPrompts")
+	}
+
+	if dir != "" {
+		if err := pr.loadFS(os.DirFS(dir), "."); err != nil {
+			return nil, errors.Wrapf(err, "loadPrompts from: %v", dir)
+		}
+	}
+
+	return pr, nil
+}
+
+func (pr *PromptRegistry) loadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || (ext != promptExt && ext != varsExt) {
+			continue
+		}
+
+		b, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+
+		version := strings.TrimSuffix(entry.Name(), ext)
+		if ext == varsExt {
+			vars := new(PromptVars)
+			if err := json.Unmarshal(b, vars); err != nil {
+				return errors.Wrapf(err, "parse prompt vars: %v", version)
+			}
+			if err := vars.validate(); err != nil {
+				return errors.Wrapf(err, "prompt vars: %v", version)
+			}
+			pr.vars[version] = vars
+			continue
+		}
+
+		tmpl, err := template.New(version).Funcs(promptFuncs).Parse(string(b))
+		if err != nil {
+			return errors.Wrapf(err, "parse prompt: %v", version)
+		}
+		pr.templates[version] = tmpl
+	}
+
+	return nil
+}
+
+// Vars 返回渲染指定版本时使用的变量，该版本没有单独的变量文件时使用默认版本的变量
+func (pr *PromptRegistry) Vars(version string) (*PromptVars, error) {
+	if vars, ok := pr.vars[version]; ok {
+		return vars, nil
+	}
+	if vars, ok := pr.vars[DefaultPromptVersion]; ok {
+		return vars, nil
+	}
+	return nil, fmt.Errorf("no vars for prompt version: %v", version)
+}
+
+func (pr *PromptRegistry) Versions() []string {
+	versions := make([]string, 0, len(pr.templates))
+	for v := range pr.templates {
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// Render 使用该版本的变量渲染指定版本的提示词
+func (pr *PromptRegistry) Render(version string) (*Prompt, error) {
+	tmpl, ok := pr.templates[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown prompt version: %v", version)
+	}
+
+	vars, err := pr.Vars(version)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, vars); err != nil {
+		return nil, errors.Wrapf(err, "render prompt: %v", version)
+	}
+
+	return &Prompt{
+		Version: version,
+		Vars:    vars,
+		Content: buf.String(),
+	}, nil
+}
+
+// MustDefaultPrompt 渲染内置的默认版本提示词
+func MustDefaultPrompt() *Prompt {
+	pr, err := NewPromptRegistry("")
+	if err != nil {
+		panic(err)
+	}
+
+	p, err := pr.Render(DefaultPromptVersion)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
@@ -0,0 +1,51 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPromptRegistry_Vars(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"v2.tmpl": "评分项：{{join .LabelNames \"、\"}}，分数 {{.MinScore}} - {{.MaxScore}}",
+		"v2.json": `{"labels": [{"name": "笑容"}], "minScore": 60, "maxScore": 90, "shortTagCount": 2, "longTagCount": 1}`,
+		"v3.tmpl": "标签 {{.TagCount}} 个",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pr, err := NewPromptRegistry(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := pr.Render("v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Content != "评分项：笑容，分数 60 - 90" || p.Vars.TagCount() != 3 {
+		t.Errorf("期望使用目录中的变量渲染，实际 %q, %+v", p.Content, p.Vars)
+	}
+
+	// 没有变量文件的版本使用默认版本的变量
+	p, err = pr.Render("v3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Content != "标签 8 个" || len(p.Vars.Labels) != 4 {
+		t.Errorf("期望使用默认版本的变量渲染，实际 %q, %+v", p.Content, p.Vars)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "v4.json"), []byte(`{"labels": [], "minScore": 70, "maxScore": 100}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPromptRegistry(dir); err == nil || !strings.Contains(err.Error(), "v4") {
+		t.Errorf("期望变量文件无效时加载失败，实际: %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+// File:		validate.go
+// Created by:	Hoven
+// Created on:	2025-05-26
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+)
+
+// ValidationError AI 返回的结果无法修复时返回，Problems 会被反馈给模型用于重新生成
+type ValidationError struct {
+	Problems []string
+}
+
+func (ve *ValidationError) Error() string {
+	return fmt.Sprintf("invalid ai response: %v", strings.Join(ve.Problems, "; "))
+}
+
+// extractJSON 从模型输出中取出第一个完整的 JSON 对象，忽略前后的说明文字和代码块标记
+func extractJSON(content string) (*analyst.Result, error) {
+	for start := strings.IndexByte(content, '{'); start != -1; {
+		ret := &analyst.Result{}
+		err := json.NewDecoder(strings.NewReader(content[start:])).Decode(ret)
+		if err == nil {
+			return ret, nil
+		}
+
+		next := strings.IndexByte(content[start+1:], '{')
+		if next == -1 {
+			return nil, fmt.Errorf("no valid JSON found in response: %v", err)
+		}
+		start += next + 1
+	}
+
+	return nil, fmt.Errorf("no valid JSON found in response")
+}
+
+// resultValidator 按照提示词中约定的格式校验并修复 AI 返回的结果
+//
+// 可以修复的问题：分数越界（截断到范围内）、标签首尾空白与重复、多余的评分项、
+// 总分缺失（取各项平均分）、描述缺失（拼接各项描述）、标签过多（截断）。
+// 无法修复的问题：缺少评分项、评分项描述为空、有效标签数量少于提示词约定的下限。
+type resultValidator struct {
+	vars *PromptVars
+}
+
+func newResultValidator(vars *PromptVars) *resultValidator {
+	return &resultValidator{vars: vars}
+}
+
+func (rv *resultValidator) clamp(score int) int {
+	if score < rv.vars.MinScore {
+		return rv.vars.MinScore
+	}
+	if score > rv.vars.MaxScore {
+		return rv.vars.MaxScore
+	}
+	return score
+}
+
+func (rv *resultValidator) validateDetails(details []analyst.Detail) ([]analyst.Detail, []string) {
+	byLabel := make(map[string]analyst.Detail, len(details))
+	for _, d := range details {
+		label := strings.TrimSpace(d.Label)
+		if _, exists := byLabel[label]; exists {
+			continue
+		}
+		d.Label = label
+		d.Desc = strings.TrimSpace(d.Desc)
+		byLabel[label] = d
+	}
+
+	var problems []string
+	ret := make([]analyst.Detail, 0, len(rv.vars.Labels))
+	for _, l := range rv.vars.Labels {
+		d, ok := byLabel[l.Name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("scoreDetails 缺少“%v”评分项", l.Name))
+			continue
+		}
+		if d.Desc == "" {
+			problems = append(problems, fmt.Sprintf("“%v”评分项缺少 desc 描述", l.Name))
+			continue
+		}
+
+		d.Score = rv.clamp(d.Score)
+		ret = append(ret, d)
+	}
+
+	return ret, problems
+}
+
+func (rv *resultValidator) validateTags(tags []string) ([]string, []string) {
+	seen := make(map[string]bool, len(tags))
+	ret := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		ret = append(ret, t)
+	}
+
+	minTags, maxTags := rv.vars.TagRange()
+	if len(ret) > maxTags {
+		ret = ret[:maxTags]
+	}
+	if len(ret) < minTags {
+		return nil, []string{fmt.Sprintf("tags 需要 %d 个互不重复的标签（至少 %d 个，最多 %d 个），实际只有 %d 个",
+			rv.vars.TagCount(), minTags, maxTags, len(ret))}
+	}
+
+	return ret, nil
+}
+
+// Validate 返回修复后的结果，无法修复时返回 *ValidationError
+func (rv *resultValidator) Validate(ret *analyst.Result) (*analyst.Result, error) {
+	details, problems := rv.validateDetails(ret.Details)
+
+	tags, tagProblems := rv.validateTags(ret.Tags)
+	problems = append(problems, tagProblems...)
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	score := ret.Score
+	if score == 0 && len(details) > 0 {
+		for _, d := range details {
+			score += d.Score
+		}
+		score /= len(details)
+	}
+
+	description := strings.TrimSpace(ret.Description)
+	if description == "" {
+		descs := make([]string, 0, len(details))
+		for _, d := range details {
+			descs = append(descs, d.Desc)
+		}
+		description = strings.Join(descs, "，")
+	}
+
+	return &analyst.Result{
+		AnalystType: ret.AnalystType,
+		Score:       rv.clamp(score),
+		Description: description,
+		Tags:        tags,
+		Details:     details,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package ai
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+)
+
+func validDetails() []analyst.Detail {
+	return []analyst.Detail{
+		{Label: "五官", Score: 88, Desc: "五官端正"},
+		{Label: "气质", Score: 90, Desc: "气质温和"},
+		{Label: "妆容", Score: 86, Desc: "妆容自然"},
+		{Label: "发型", Score: 84, Desc: "发型清爽"},
+	}
+}
+
+func TestExtractJSON(t *testing.T) {
+	content := "好的，结果如下：\n```json\n{\"score\": 88, \"description\": \"不错 {真的}\", \"tags\": [\"清秀\"]}\n```\n以上。"
+
+	ret, err := extractJSON(content)
+	if err != nil {
+		t.Fatalf("期望解析成功，实际错误: %v", err)
+	}
+	if ret.Score != 88 || ret.Description != "不错 {真的}" {
+		t.Errorf("解析结果不符合预期: %+v", ret)
+	}
+
+	if _, err := extractJSON("没有 json {"); err == nil {
+		t.Error("期望解析失败")
+	}
+}
+
+func TestResultValidator_Repair(t *testing.T) {
+	rv := newResultValidator(DefaultPromptVars())
+
+	details := validDetails()
+	details[0].Score = 120
+	details = append(details, analyst.Detail{Label: " 气质 ", Score: 10, Desc: "重复"}, analyst.Detail{Label: "身材", Score: 99, Desc: "多余"})
+
+	ret, err := rv.Validate(&analyst.Result{
+		Score:   0,
+		Tags:    []string{"清秀", " 清秀", "温柔", "", "阳光", "可爱", "干净", "甜美", "自然", "大方", "知性"},
+		Details: details,
+	})
+	if err != nil {
+		t.Fatalf("期望可以修复，实际错误: %v", err)
+	}
+
+	if len(ret.Details) != 4 || ret.Details[0].Score != 100 || ret.Details[1].Desc != "气质温和" {
+		t.Errorf("评分项修复结果不符合预期: %+v", ret.Details)
+	}
+	if ret.Score != (100+90+86+84)/4 {
+		t.Errorf("总分缺失时期望取平均分，实际 %d", ret.Score)
+	}
+	if ret.Description == "" {
+		t.Error("描述缺失时期望由评分项描述拼接")
+	}
+	if len(ret.Tags) != 8 || ret.Tags[1] != "温柔" {
+		t.Errorf("标签修复结果不符合预期: %v", ret.Tags)
+	}
+}
+
+func TestResultValidator_Unrepairable(t *testing.T) {
+	rv := newResultValidator(DefaultPromptVars())
+
+	_, err := rv.Validate(&analyst.Result{
+		Score:   90,
+		Tags:    []string{"清秀", "清秀"},
+		Details: validDetails()[1:],
+	})
+
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("期望返回 ValidationError，实际: %v", err)
+	}
+	if len(ve.Problems) != 2 {
+		t.Errorf("期望 2 个问题，实际: %v", ve.Problems)
+	}
+}
+
+func TestResultValidator_TagRange(t *testing.T) {
+	vars := *DefaultPromptVars()
+	vars.MinTagCount, vars.MaxTagCount = 3, 5
+	rv := newResultValidator(&vars)
+
+	ret, err := rv.Validate(&analyst.Result{
+		Score:   90,
+		Tags:    []string{"清秀", "温柔", "阳光", "可爱", "干净", "甜美"},
+		Details: validDetails(),
+	})
+	if err != nil || len(ret.Tags) != 5 {
+		t.Fatalf("期望标签截断到上限 5 个，实际 %v, %v", ret, err)
+	}
+
+	_, err = rv.Validate(&analyst.Result{Score: 90, Tags: []string{"清秀", "温柔"}, Details: validDetails()})
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Problems) != 1 || !strings.Contains(ve.Problems[0], "至少 3 个，最多 5 个") {
+		t.Errorf("期望追问原因包含标签数量范围，实际: %v", err)
+	}
+}
@@ -10,8 +10,13 @@ package analyst
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-puzzles/puzzles/plog"
+	"github.com/pkg/errors"
 	"github.com/yazl-tech/beauty-rating-server/pkg/dice"
 )
 
@@ -21,14 +26,62 @@ const (
 	TypeMock AnalystType = iota
 	TypeAi
 	TypeSelector
+	TypeHeuristic
+	TypeEnsemble
 )
 
+var analystTypeNames = map[AnalystType]string{
+	TypeMock:      "mock",
+	TypeAi:        "ai",
+	TypeSelector:  "selector",
+	TypeHeuristic: "heuristic",
+	TypeEnsemble:  "ensemble",
+}
+
+func (t AnalystType) String() string {
+	if name, ok := analystTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", int(t))
+}
+
+// ParseAnalystType 按名称解析分析器类型，名称与 String 的返回值一致
+func ParseAnalystType(name string) (AnalystType, error) {
+	for t, n := range analystTypeNames {
+		if n == name {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown analyst type: %v", name)
+}
+
+var ErrNoAvailableAnalyst = errors.New("no available analyst")
+
 type Result struct {
 	AnalystType AnalystType
+	// AnalystName 实际产出结果的分析器名称
+	AnalystName string
+	// Fallback 首选分析器失败后由后备分析器产出时为 true
+	Fallback bool
+	// PromptVersion、Model 产出结果所用的提示词版本和模型，非 AI 分析器为空
+	PromptVersion string
+	Model         string
+	Score         int
+	Description   string
+	Tags          []string
+	Details       []Detail `json:"scoreDetails"`
+	// Contributions 组合分析器中各成员的结果，随报告持久化用于审计
+	Contributions []Contribution `json:"-"`
+}
+
+type Contribution struct {
+	AnalystName string
+	AnalystType AnalystType
+	Weight      int
 	Score       int
-	Description string
-	Tags        []string
-	Details     []Detail `json:"scoreDetails"`
+	// Reference 分数与颜值分不可比，只作参考，不参与合并
+	Reference bool
+	Err       string
 }
 
 type Detail struct {
@@ -52,31 +105,142 @@ func WithAnalysts(analyst Analyst, weight int) SelectorOption {
 	}
 }
 
+// WithAnalystTimeout 设置单个分析器的调用超时，超时视为失败并尝试下一个分析器
+func WithAnalystTimeout(timeout time.Duration) SelectorOption {
+	return func(s *AnalystSelector) {
+		s.timeout = timeout
+	}
+}
+
+// WithCircuitBreaker 设置熔断参数：连续失败 threshold 次后熔断，cooldown 后放行一个探测请求
+func WithCircuitBreaker(threshold int, cooldown time.Duration) SelectorOption {
+	return func(s *AnalystSelector) {
+		s.breakerThreshold = threshold
+		s.breakerCooldown = cooldown
+	}
+}
+
+// selection 为一组不可变的权重及其骰子，整体原子替换以支持运行时调整权重
+type selection struct {
+	weights []int
+	dice    *dice.Dice
+}
+
 type AnalystSelector struct {
-	weights  []int
-	analysts []Analyst
-	dice     *dice.Dice
+	weights   []int
+	analysts  []Analyst
+	breakers  []*circuitBreaker
+	selection atomic.Pointer[selection]
+
+	timeout          time.Duration
+	breakerThreshold int
+	breakerCooldown  time.Duration
 }
 
 func NewAnalystSelector(opts ...SelectorOption) *AnalystSelector {
 	s := &AnalystSelector{
-		analysts: []Analyst{},
+		analysts:         []Analyst{},
+		timeout:          time.Minute,
+		breakerThreshold: 5,
+		breakerCooldown:  30 * time.Second,
 	}
 	for _, opt := range opts {
 		opt(s)
 	}
 
-	s.dice = dice.NewDice(s.weights)
+	for range s.analysts {
+		s.breakers = append(s.breakers, newCircuitBreaker(s.breakerThreshold, s.breakerCooldown))
+	}
+
+	s.selection.Store(&selection{
+		weights: s.weights,
+		dice:    dice.NewDice(s.weights),
+	})
 	return s
 }
 
+// Weights 返回当前生效的权重，以分析器名称为键
+func (s *AnalystSelector) Weights() map[string]int {
+	sel := s.selection.Load()
+
+	weights := make(map[string]int, len(s.analysts))
+	for i, a := range s.analysts {
+		weights[a.Name()] = sel.weights[i]
+	}
+	return weights
+}
+
+// SetWeights 原子替换分析器权重，未出现在 weights 中的分析器保持原权重
+func (s *AnalystSelector) SetWeights(weights map[string]int) error {
+	current := s.selection.Load()
+	newWeights := append([]int(nil), current.weights...)
+
+	index := make(map[string]int, len(s.analysts))
+	for i, a := range s.analysts {
+		index[a.Name()] = i
+	}
+
+	for name, w := range weights {
+		i, ok := index[name]
+		if !ok {
+			return fmt.Errorf("unknown analyst: %v", name)
+		}
+		if w < 0 {
+			return fmt.Errorf("negative weight for analyst: %v", name)
+		}
+		newWeights[i] = w
+	}
+
+	total := 0
+	for _, w := range newWeights {
+		total += w
+	}
+	if total == 0 {
+		return errors.New("all analyst weights are zero")
+	}
+
+	s.selection.Store(&selection{
+		weights: newWeights,
+		dice:    dice.NewDice(newWeights),
+	})
+	return nil
+}
+
 func (s *AnalystSelector) GetAnalyst() Analyst {
-	defer s.dice.Reset()
-	idx := s.dice.Next()
+	idx := s.pick()
+	if idx == -1 {
+		return nil
+	}
 
 	return s.analysts[idx]
 }
 
+func (s *AnalystSelector) pick() int {
+	return s.selection.Load().dice.Sample()
+}
+
+// candidates 返回本次请求的尝试顺序：骰子选中的分析器在前，其余按权重从大到小作为后备
+func (s *AnalystSelector) candidates() []int {
+	sel := s.selection.Load()
+	first := sel.dice.Sample()
+
+	rest := make([]int, 0, len(s.analysts))
+	for i := range s.analysts {
+		if i != first {
+			rest = append(rest, i)
+		}
+	}
+	sort.SliceStable(rest, func(i, j int) bool {
+		return sel.weights[rest[i]] > sel.weights[rest[j]]
+	})
+
+	if first == -1 {
+		return rest
+	}
+
+	return append([]int{first}, rest...)
+}
+
 func (s *AnalystSelector) Name() string {
 	return "AnalystSelector"
 }
@@ -85,14 +249,67 @@ func (s *AnalystSelector) Typ() AnalystType {
 	return TypeSelector
 }
 
+func (s *AnalystSelector) doAnalysis(ctx context.Context, analyst Analyst, imageName, imageUrl string, image []byte) (*Result, error) {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	return analyst.DoAnalysis(ctx, imageName, imageUrl, image)
+}
+
 func (s *AnalystSelector) DoAnalysis(ctx context.Context, imageName, imageUrl string, image []byte) (*Result, error) {
-	analyst := s.GetAnalyst()
+	return s.tryCandidates(ctx, s.candidates(), imageName, imageUrl, image)
+}
+
+// DoAnalysisByType 只在指定类型的分析器中按权重选择，失败时不会降级到其他类型
+func (s *AnalystSelector) DoAnalysisByType(ctx context.Context, typ AnalystType, imageName, imageUrl string, image []byte) (*Result, error) {
+	candidates := s.candidates()
+	matched := candidates[:0]
+	for _, idx := range candidates {
+		if s.analysts[idx].Typ() == typ {
+			matched = append(matched, idx)
+		}
+	}
+
+	return s.tryCandidates(ctx, matched, imageName, imageUrl, image)
+}
+
+func (s *AnalystSelector) tryCandidates(ctx context.Context, candidates []int, imageName, imageUrl string, image []byte) (*Result, error) {
+	var lastErr error = ErrNoAvailableAnalyst
+
+	for attempt, idx := range candidates {
+		analyst := s.analysts[idx]
+		breaker := s.breakers[idx]
+
+		if !breaker.Allow() {
+			plog.Debugc(ctx, "analyst: %v circuit %v, skip", analyst.Name(), breaker.State())
+			continue
+		}
+
+		plog.Debugc(ctx, "GetAnalyst: %v", analyst.Name())
+		ReportProgress(ctx, Progress{Stage: StageAnalyst, Analyst: analyst.Name(), Fallback: attempt > 0})
+		resp, err := s.doAnalysis(ctx, analyst, imageName, imageUrl, image)
+		if err != nil {
+			lastErr = errors.Wrapf(err, "analyst: %v", analyst.Name())
+
+			// 调用方取消或超时不是分析器的问题，不计入熔断，但需要释放 half-open 的探测名额
+			if errors.Is(err, context.Canceled) || ctx.Err() != nil {
+				breaker.Release()
+				return nil, lastErr
+			}
+
+			breaker.Failure()
+			plog.Warnc(ctx, "analyst: %v do analysis failed: %v", analyst.Name(), err)
+			continue
+		}
+		breaker.Success()
 
-	plog.Debugc(ctx, "GetAnalyst: %v", analyst.Name())
-	resp, err := analyst.DoAnalysis(ctx, imageName, imageUrl, image)
-	if err != nil {
-		return nil, err
+		resp.AnalystName = analyst.Name()
+		resp.Fallback = attempt > 0
+		return resp, nil
 	}
 
-	return resp, nil
+	return nil, lastErr
 }
@@ -0,0 +1,120 @@
+// File:		breaker.go
+// Created by:	Hoven
+// Created on:	2025-05-21
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analyst
+
+import (
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (bs breakerState) String() string {
+	switch bs {
+	case breakerClosed:
+		return "closed"
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreaker 记录单个分析器的连续失败次数
+//
+// closed: 正常放行，连续失败达到 threshold 次后进入 open
+// open: 拒绝所有请求，经过 cooldown 后进入 half-open
+// half-open: 只放行一个探测请求，成功则回到 closed，失败则重新 open
+type circuitBreaker struct {
+	mu        sync.Mutex
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		state:     breakerClosed,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow 判断当前是否可以调用分析器，half-open 状态下同时只允许一个探测请求
+func (cb *circuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case breakerOpen:
+		if cb.now().Sub(cb.openedAt) < cb.cooldown {
+			return false
+		}
+		cb.state = breakerHalfOpen
+		cb.probing = true
+		return true
+	case breakerHalfOpen:
+		if cb.probing {
+			return false
+		}
+		cb.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (cb *circuitBreaker) Success() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.state = breakerClosed
+	cb.failures = 0
+	cb.probing = false
+}
+
+func (cb *circuitBreaker) Failure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures++
+	cb.probing = false
+	if cb.state == breakerHalfOpen || cb.failures >= cb.threshold {
+		cb.state = breakerOpen
+		cb.openedAt = cb.now()
+	}
+}
+
+// Release 放弃本次调用的结果，不改变失败计数，half-open 状态下允许下一个探测请求
+func (cb *circuitBreaker) Release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+}
+
+func (cb *circuitBreaker) State() breakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	return cb.state
+}
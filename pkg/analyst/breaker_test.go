@@ -0,0 +1,116 @@
+package analyst
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestBreaker 使用可控时钟的熔断器，返回推进时钟的函数
+func newTestBreaker(threshold int, cooldown time.Duration) (*circuitBreaker, func(time.Duration)) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	cb := newCircuitBreaker(threshold, cooldown)
+	cb.now = func() time.Time { return now }
+	return cb, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestCircuitBreaker_OpenAfterThreshold(t *testing.T) {
+	cb, _ := newTestBreaker(3, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if !cb.Allow() {
+			t.Fatalf("第 %d 次调用前期望放行", i+1)
+		}
+		cb.Failure()
+	}
+	if cb.State() != breakerClosed {
+		t.Fatalf("未达到阈值时期望 closed，实际 %v", cb.State())
+	}
+
+	// 成功会清空连续失败次数
+	cb.Success()
+	for i := 0; i < 2; i++ {
+		cb.Failure()
+	}
+	if cb.State() != breakerClosed {
+		t.Fatalf("成功后连续失败次数应重新计算，实际 %v", cb.State())
+	}
+
+	cb.Failure()
+	if cb.State() != breakerOpen {
+		t.Fatalf("连续失败达到阈值时期望 open，实际 %v", cb.State())
+	}
+	if cb.Allow() {
+		t.Error("open 状态下期望拒绝请求")
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	cb, advance := newTestBreaker(1, time.Minute)
+	cb.Failure()
+
+	advance(59 * time.Second)
+	if cb.Allow() {
+		t.Fatal("冷却时间内期望拒绝请求")
+	}
+
+	advance(time.Second)
+	if !cb.Allow() {
+		t.Fatal("冷却结束后期望放行一个探测请求")
+	}
+	if cb.State() != breakerHalfOpen {
+		t.Fatalf("期望 half-open，实际 %v", cb.State())
+	}
+	if cb.Allow() {
+		t.Fatal("探测请求未结束时期望拒绝其他请求")
+	}
+
+	cb.Success()
+	if cb.State() != breakerClosed || !cb.Allow() {
+		t.Errorf("探测成功后期望 closed，实际 %v", cb.State())
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbeFailure(t *testing.T) {
+	cb, advance := newTestBreaker(3, time.Minute)
+	for i := 0; i < 3; i++ {
+		cb.Failure()
+	}
+
+	advance(time.Minute)
+	if !cb.Allow() {
+		t.Fatal("冷却结束后期望放行一个探测请求")
+	}
+
+	// half-open 下一次失败就重新 open，并重新计算冷却时间
+	cb.Failure()
+	if cb.State() != breakerOpen {
+		t.Fatalf("探测失败后期望 open，实际 %v", cb.State())
+	}
+	advance(30 * time.Second)
+	if cb.Allow() {
+		t.Error("重新 open 后冷却时间内期望拒绝请求")
+	}
+	advance(30 * time.Second)
+	if !cb.Allow() {
+		t.Error("重新冷却结束后期望再次探测")
+	}
+}
+
+func TestCircuitBreaker_Release(t *testing.T) {
+	cb, advance := newTestBreaker(1, time.Minute)
+	cb.Failure()
+	advance(time.Minute)
+
+	if !cb.Allow() {
+		t.Fatal("冷却结束后期望放行一个探测请求")
+	}
+
+	// 探测请求被调用方取消时不改变状态，但允许下一个探测
+	cb.Release()
+	if cb.State() != breakerHalfOpen {
+		t.Fatalf("释放探测后期望保持 half-open，实际 %v", cb.State())
+	}
+	if !cb.Allow() {
+		t.Error("释放探测后期望放行下一个探测请求")
+	}
+}
@@ -0,0 +1,254 @@
+// File:		ensemble.go
+// Created by:	Hoven
+// Created on:	2025-05-28
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package ensemble
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/pkg/errors"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+)
+
+var _ analyst.Analyst = (*EnsembleAnalyst)(nil)
+
+type EnsembleOption func(*EnsembleAnalyst)
+
+// WithMember 添加给出颜值分的成员，按 weight 参与合并
+func WithMember(a analyst.Analyst, weight int) EnsembleOption {
+	return func(e *EnsembleAnalyst) {
+		if weight <= 0 {
+			return
+		}
+		e.members = append(e.members, member{analyst: a, weight: weight})
+	}
+}
+
+// WithReferenceMember 添加分数与颜值分不可比的成员（如图片质量分析），
+// 只记录在成员贡献中用于审计，不参与合并
+func WithReferenceMember(a analyst.Analyst) EnsembleOption {
+	return func(e *EnsembleAnalyst) {
+		e.members = append(e.members, member{analyst: a, reference: true})
+	}
+}
+
+// WithDeadline 设置等待成员返回的最长时间，超时未返回的成员视为失败
+func WithDeadline(deadline time.Duration) EnsembleOption {
+	return func(e *EnsembleAnalyst) {
+		e.deadline = deadline
+	}
+}
+
+// WithMaxTags 设置合并后保留的标签数量上限
+func WithMaxTags(n int) EnsembleOption {
+	return func(e *EnsembleAnalyst) {
+		e.maxTags = n
+	}
+}
+
+type member struct {
+	analyst   analyst.Analyst
+	weight    int
+	reference bool
+}
+
+type memberResult struct {
+	member
+	index  int
+	result *analyst.Result
+	err    error
+}
+
+// EnsembleAnalyst 将同一张图片并发交给多个分析器，在截止时间内合并所有成功的结果
+//
+// 总分按权重加权平均，各评分项取中位数，标签按权重从高到低合并去重，
+// 描述、提示词版本和模型取权重最高的成功成员。参考成员的结果不参与合并，
+// 只要有一个非参考成员成功即可返回结果。
+type EnsembleAnalyst struct {
+	members  []member
+	deadline time.Duration
+	maxTags  int
+}
+
+func NewEnsembleAnalyst(opts ...EnsembleOption) *EnsembleAnalyst {
+	e := &EnsembleAnalyst{
+		deadline: 30 * time.Second,
+		maxTags:  8,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	sort.SliceStable(e.members, func(i, j int) bool {
+		return e.members[i].weight > e.members[j].weight
+	})
+	return e
+}
+
+func (e *EnsembleAnalyst) Name() string {
+	return "EnsembleAnalyst"
+}
+
+func (e *EnsembleAnalyst) Typ() analyst.AnalystType {
+	return analyst.TypeEnsemble
+}
+
+func (e *EnsembleAnalyst) fanOut(ctx context.Context, imageName, imageUrl string, image []byte) []memberResult {
+	ctx, cancel := context.WithTimeout(ctx, e.deadline)
+	defer cancel()
+
+	ch := make(chan memberResult, len(e.members))
+	for i, m := range e.members {
+		go func(i int, m member) {
+			ret, err := m.analyst.DoAnalysis(ctx, imageName, imageUrl, image)
+			if err == nil && ret == nil {
+				err = errors.New("empty result")
+			}
+			ch <- memberResult{member: m, index: i, result: ret, err: err}
+		}(i, m)
+	}
+
+	results := make([]memberResult, len(e.members))
+	for i, m := range e.members {
+		results[i] = memberResult{member: m, index: i, err: errors.New("deadline exceeded")}
+	}
+
+	for range e.members {
+		select {
+		case r := <-ch:
+			results[r.index] = r
+			continue
+		case <-ctx.Done():
+		}
+		break
+	}
+
+	return results
+}
+
+func median(scores []int) int {
+	sorted := append([]int(nil), scores...)
+	sort.Ints(sorted)
+
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return int(math.Round(float64(sorted[n/2-1]+sorted[n/2]) / 2))
+}
+
+// merge 合并成功成员的结果，results 已按权重从高到低排列
+func (e *EnsembleAnalyst) merge(results []memberResult) *analyst.Result {
+	var (
+		weightedSum float64
+		weightTotal int
+		description string
+		labels      []string
+		labelScores = make(map[string][]int)
+		labelDescs  = make(map[string]string)
+		tagSeen     = make(map[string]bool)
+		tags        []string
+	)
+
+	ret := &analyst.Result{AnalystType: analyst.TypeEnsemble}
+	for _, r := range results {
+		if r.err != nil || r.reference {
+			continue
+		}
+
+		weightedSum += float64(r.result.Score * r.weight)
+		weightTotal += r.weight
+		if description == "" {
+			description = r.result.Description
+		}
+		if ret.PromptVersion == "" && r.result.PromptVersion != "" {
+			ret.PromptVersion = r.result.PromptVersion
+			ret.Model = r.result.Model
+		}
+
+		for _, d := range r.result.Details {
+			if _, ok := labelScores[d.Label]; !ok {
+				labels = append(labels, d.Label)
+				labelDescs[d.Label] = d.Desc
+			}
+			labelScores[d.Label] = append(labelScores[d.Label], d.Score)
+		}
+
+		for _, t := range r.result.Tags {
+			if tagSeen[t] || len(tags) >= e.maxTags {
+				continue
+			}
+			tagSeen[t] = true
+			tags = append(tags, t)
+		}
+	}
+
+	details := make([]analyst.Detail, 0, len(labels))
+	for _, label := range labels {
+		details = append(details, analyst.Detail{
+			Label: label,
+			Score: median(labelScores[label]),
+			Desc:  labelDescs[label],
+		})
+	}
+
+	ret.Score = int(math.Round(weightedSum / float64(weightTotal)))
+	ret.Description = description
+	ret.Tags = tags
+	ret.Details = details
+	return ret
+}
+
+func (e *EnsembleAnalyst) DoAnalysis(ctx context.Context, imageName, imageUrl string, image []byte) (*analyst.Result, error) {
+	if len(e.members) == 0 {
+		return nil, analyst.ErrNoAvailableAnalyst
+	}
+
+	results := e.fanOut(ctx, imageName, imageUrl, image)
+
+	var (
+		succeeded     int
+		lastErr       error = analyst.ErrNoAvailableAnalyst
+		contributions       = make([]analyst.Contribution, 0, len(results))
+	)
+	for _, r := range results {
+		c := analyst.Contribution{
+			AnalystName: r.analyst.Name(),
+			AnalystType: r.analyst.Typ(),
+			Weight:      r.weight,
+			Reference:   r.reference,
+		}
+		if r.err != nil {
+			c.Err = r.err.Error()
+			if !r.reference {
+				lastErr = errors.Wrapf(r.err, "analyst: %v", r.analyst.Name())
+			}
+			plog.Warnc(ctx, "ensemble member: %v failed: %v", r.analyst.Name(), r.err)
+		} else {
+			c.Score = r.result.Score
+			if !r.reference {
+				succeeded++
+			}
+		}
+		contributions = append(contributions, c)
+	}
+
+	if succeeded == 0 {
+		return nil, errors.Wrap(lastErr, "all ensemble members failed")
+	}
+
+	ret := e.merge(results)
+	ret.Contributions = contributions
+	plog.Debugc(ctx, "ensemble contributions: %v", plog.Jsonify(contributions))
+
+	return ret, nil
+}
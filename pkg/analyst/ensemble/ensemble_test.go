@@ -0,0 +1,114 @@
+package ensemble
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+)
+
+type fakeAnalyst struct {
+	name  string
+	delay time.Duration
+	ret   *analyst.Result
+	err   error
+}
+
+func (f *fakeAnalyst) Name() string { return f.name }
+
+func (f *fakeAnalyst) Typ() analyst.AnalystType { return analyst.TypeMock }
+
+func (f *fakeAnalyst) DoAnalysis(ctx context.Context, _, _ string, _ []byte) (*analyst.Result, error) {
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return f.ret, f.err
+}
+
+func result(score int, desc string, tags []string, details ...int) *analyst.Result {
+	ret := &analyst.Result{Score: score, Description: desc, Tags: tags}
+	for i, s := range details {
+		ret.Details = append(ret.Details, analyst.Detail{Label: []string{"五官", "气质"}[i], Score: s, Desc: desc})
+	}
+	return ret
+}
+
+func TestEnsembleAnalyst_Merge(t *testing.T) {
+	e := NewEnsembleAnalyst(
+		WithMember(&fakeAnalyst{name: "a", ret: result(90, "a", []string{"甜美", "清秀"}, 90, 80)}, 1),
+		WithMember(&fakeAnalyst{name: "b", ret: result(80, "b", []string{"清秀", "阳光"}, 70, 60)}, 3),
+		WithMember(&fakeAnalyst{name: "c", ret: result(70, "c", nil, 100, 90)}, 1),
+		WithMember(&fakeAnalyst{name: "d", err: errors.New("boom")}, 5),
+		WithMember(&fakeAnalyst{name: "e", delay: time.Second, ret: result(10, "e", nil)}, 10),
+		WithDeadline(100*time.Millisecond),
+	)
+
+	ret, err := e.DoAnalysis(context.Background(), "", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ret.Score != 80 {
+		t.Errorf("期望加权平均分 80，实际 %d", ret.Score)
+	}
+	if ret.Description != "b" {
+		t.Errorf("期望描述来自权重最高的成功成员 b，实际 %v", ret.Description)
+	}
+	if len(ret.Details) != 2 || ret.Details[0].Score != 90 || ret.Details[1].Score != 80 {
+		t.Errorf("评分项中位数不符合预期: %+v", ret.Details)
+	}
+	if len(ret.Tags) != 3 || ret.Tags[0] != "清秀" {
+		t.Errorf("标签合并不符合预期: %v", ret.Tags)
+	}
+	if len(ret.Contributions) != 5 || ret.Contributions[0].Err == "" || ret.Contributions[1].Err == "" {
+		t.Errorf("成员贡献记录不符合预期: %+v", ret.Contributions)
+	}
+}
+
+func TestEnsembleAnalyst_AllFailed(t *testing.T) {
+	e := NewEnsembleAnalyst(WithMember(&fakeAnalyst{name: "a", err: errors.New("boom")}, 1))
+
+	if _, err := e.DoAnalysis(context.Background(), "", "", nil); err == nil {
+		t.Error("全部成员失败时期望返回错误")
+	}
+}
+
+func TestEnsembleAnalyst_ReferenceMember(t *testing.T) {
+	aiRet := result(90, "ai", []string{"清秀"}, 90, 80)
+	aiRet.PromptVersion, aiRet.Model = "v1", "fake-model"
+
+	e := NewEnsembleAnalyst(
+		WithMember(&fakeAnalyst{name: "ai", ret: aiRet}, 2),
+		WithMember(&fakeAnalyst{name: "mock", ret: result(80, "mock", nil, 80, 70)}, 1),
+		WithReferenceMember(&fakeAnalyst{name: "quality", ret: &analyst.Result{
+			Score: 10, Tags: []string{"光线暗"}, Details: []analyst.Detail{{Label: "亮度", Score: 10, Desc: "偏暗"}},
+		}}),
+	)
+
+	ret, err := e.DoAnalysis(context.Background(), "", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Score != 87 || len(ret.Details) != 2 || len(ret.Tags) != 1 {
+		t.Errorf("参考成员的结果不应参与合并，实际 %+v", ret)
+	}
+	if ret.PromptVersion != "v1" || ret.Model != "fake-model" {
+		t.Errorf("期望保留 AI 成员的提示词版本和模型，实际 %v, %v", ret.PromptVersion, ret.Model)
+	}
+	if c := ret.Contributions[2]; !c.Reference || c.Score != 10 {
+		t.Errorf("参考成员的结果应记录在成员贡献中，实际 %+v", c)
+	}
+
+	// 只有参考成员成功时不能给出颜值分
+	e = NewEnsembleAnalyst(
+		WithMember(&fakeAnalyst{name: "ai", err: errors.New("boom")}, 1),
+		WithReferenceMember(&fakeAnalyst{name: "quality", ret: result(10, "quality", nil)}),
+	)
+	if _, err := e.DoAnalysis(context.Background(), "", "", nil); err == nil {
+		t.Error("只有参考成员成功时期望返回错误")
+	}
+}
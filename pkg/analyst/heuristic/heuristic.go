@@ -0,0 +1,207 @@
+// File:		heuristic.go
+// Created by:	Hoven
+// Created on:	2025-05-27
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package heuristic
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"math"
+
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/pkg/errors"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+)
+
+var _ analyst.Analyst = (*HeuristicAnalyst)(nil)
+
+const (
+	minScore = 70
+	maxScore = 100
+)
+
+// quality 单个信号的评价，Value 为 0-1 的质量分
+type quality struct {
+	Label  string
+	Value  float64
+	Weight float64
+	Desc   string
+	Tag    string
+}
+
+// HeuristicAnalyst 完全离线运行的图片质量分析器
+//
+// 基于亮度、对比度、清晰度（拉普拉斯方差）、饱和度和宽高比给出确定性的评分，
+// 同一张图片永远得到相同的结果，作为可解释的非 AI 基线。
+type HeuristicAnalyst struct{}
+
+func NewHeuristicAnalyst() *HeuristicAnalyst {
+	return &HeuristicAnalyst{}
+}
+
+func (h *HeuristicAnalyst) Name() string {
+	return "HeuristicAnalyst"
+}
+
+func (h *HeuristicAnalyst) Typ() analyst.AnalystType {
+	return analyst.TypeHeuristic
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// rangeQuality 数值落在 [low, high] 内为满分，两侧按 fade 的距离线性衰减到 0
+func rangeQuality(v, low, high, fade float64) float64 {
+	switch {
+	case v < low:
+		return clamp01(1 - (low-v)/fade)
+	case v > high:
+		return clamp01(1 - (v-high)/fade)
+	default:
+		return 1
+	}
+}
+
+func tier(q float64, good, ok, poor string) string {
+	switch {
+	case q >= 0.8:
+		return good
+	case q >= 0.5:
+		return ok
+	default:
+		return poor
+	}
+}
+
+func (h *HeuristicAnalyst) evaluate(s *Signals) []quality {
+	brightness := rangeQuality(s.Brightness, 110, 160, 90)
+	contrast := rangeQuality(s.Contrast, 45, 75, 45)
+	sharpness := clamp01(math.Log1p(s.Sharpness) / math.Log1p(400))
+	saturation := rangeQuality(s.Saturation, 0.2, 0.5, 0.3)
+	aspect := clamp01(1 - math.Abs(math.Log(s.AspectRatio/0.75))/math.Log(3))
+
+	exposure := "画面过曝"
+	if s.Brightness < 110 {
+		exposure = "画面偏暗"
+	}
+
+	return []quality{
+		{
+			Label:  "光线",
+			Value:  brightness,
+			Weight: 0.2,
+			Desc:   fmt.Sprintf("平均亮度 %.0f，%s", s.Brightness, tier(brightness, "光线充足均匀", "光线基本合适", exposure)),
+			Tag:    tier(brightness, "光线通透", "", ""),
+		},
+		{
+			Label:  "对比度",
+			Value:  contrast,
+			Weight: 0.2,
+			Desc:   fmt.Sprintf("亮度标准差 %.0f，%s", s.Contrast, tier(contrast, "明暗层次丰富", "层次感一般", "画面发灰或反差过大")),
+			Tag:    tier(contrast, "层次分明", "", ""),
+		},
+		{
+			Label:  "清晰度",
+			Value:  sharpness,
+			Weight: 0.3,
+			Desc:   fmt.Sprintf("拉普拉斯方差 %.0f，%s", s.Sharpness, tier(sharpness, "细节锐利清晰", "细节略有模糊", "对焦不准或抖动")),
+			Tag:    tier(sharpness, "画面清晰", "", ""),
+		},
+		{
+			Label:  "色彩",
+			Value:  saturation,
+			Weight: 0.15,
+			Desc:   fmt.Sprintf("平均饱和度 %.2f，%s", s.Saturation, tier(saturation, "色彩自然鲜活", "色彩稍显平淡", "色彩过淡或过艳")),
+			Tag:    tier(saturation, "色彩鲜活", "", ""),
+		},
+		{
+			Label:  "构图",
+			Value:  aspect,
+			Weight: 0.15,
+			Desc:   fmt.Sprintf("宽高比 %.2f，%s", s.AspectRatio, tier(aspect, "比例适合人像", "比例尚可", "画面比例过于狭长")),
+			Tag:    tier(aspect, "构图舒适", "", ""),
+		},
+	}
+}
+
+func toScore(q float64) int {
+	return minScore + int(math.Round(q*(maxScore-minScore)))
+}
+
+func (h *HeuristicAnalyst) describe(qualities []quality, total float64) string {
+	best, worst := qualities[0], qualities[0]
+	for _, q := range qualities[1:] {
+		if q.Value > best.Value {
+			best = q
+		}
+		if q.Value < worst.Value {
+			worst = q
+		}
+	}
+
+	overall := tier(total, "照片整体质量很高", "照片整体质量不错", "照片整体质量一般")
+	if best.Label == worst.Label || worst.Value >= 0.8 {
+		return fmt.Sprintf("%s，各项指标都很均衡，%s表现尤其出色。", overall, best.Label)
+	}
+
+	return fmt.Sprintf("%s，%s表现最好，%s还有提升空间，换个环境再拍一张可能会有惊喜。", overall, best.Label, worst.Label)
+}
+
+func (h *HeuristicAnalyst) Analyze(img image.Image) *analyst.Result {
+	qualities := h.evaluate(ComputeSignals(img))
+
+	var total float64
+	details := make([]analyst.Detail, 0, len(qualities))
+	tags := make([]string, 0, len(qualities))
+	for _, q := range qualities {
+		total += q.Value * q.Weight
+		details = append(details, analyst.Detail{
+			Label: q.Label,
+			Score: toScore(q.Value),
+			Desc:  q.Desc,
+		})
+		if q.Tag != "" {
+			tags = append(tags, q.Tag)
+		}
+	}
+	if len(tags) < 3 {
+		tags = append(tags, "自然真实", "原图直出")
+	}
+
+	return &analyst.Result{
+		AnalystType: analyst.TypeHeuristic,
+		Score:       toScore(total),
+		Description: h.describe(qualities, total),
+		Tags:        tags,
+		Details:     details,
+	}
+}
+
+func (h *HeuristicAnalyst) DoAnalysis(_ context.Context, _, _ string, b []byte) (*analyst.Result, error) {
+	img, _, err := decodeImage(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.Analyze(img), nil
+}
+
+func decodeImage(b []byte) (image.Image, string, error) {
+	img, format, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, "", errors.Wrap(err, "decodeImage")
+	}
+
+	return img, format, nil
+}
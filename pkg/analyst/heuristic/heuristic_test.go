@@ -0,0 +1,74 @@
+package heuristic
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"reflect"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// checkerboard 生成 3:4 的彩色棋盘格，cell 越小边缘越多，清晰度越高
+func checkerboard(cell int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 300, 400))
+	for y := 0; y < 400; y++ {
+		for x := 0; x < 300; x++ {
+			c := color.RGBA{R: 200, G: 150, B: 120, A: 255}
+			if (x/cell+y/cell)%2 == 0 {
+				c = color.RGBA{R: 90, G: 70, B: 60, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestHeuristicAnalyst_Deterministic(t *testing.T) {
+	h := NewHeuristicAnalyst()
+	b := encodePNG(t, checkerboard(4))
+
+	r1, err := h.DoAnalysis(context.Background(), "", "", b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, _ := h.DoAnalysis(context.Background(), "", "", b)
+
+	if !reflect.DeepEqual(r1, r2) {
+		t.Errorf("同一张图片两次分析结果不一致:\n%+v\n%+v", r1, r2)
+	}
+	if r1.Score < minScore || r1.Score > maxScore || len(r1.Details) != 5 {
+		t.Errorf("分析结果不符合预期: %+v", r1)
+	}
+}
+
+func TestHeuristicAnalyst_Signals(t *testing.T) {
+	h := NewHeuristicAnalyst()
+
+	dark := h.Analyze(image.NewRGBA(image.Rect(0, 0, 300, 400)))
+	sharp := h.Analyze(checkerboard(4))
+
+	if sharp.Score <= dark.Score {
+		t.Errorf("清晰明亮的图片得分 %d 应高于全黑图片 %d", sharp.Score, dark.Score)
+	}
+
+	s := ComputeSignals(checkerboard(4))
+	if s.AspectRatio != 0.75 || s.Sharpness <= 0 || s.Saturation <= 0 {
+		t.Errorf("信号计算不符合预期: %+v", s)
+	}
+}
+
+func TestHeuristicAnalyst_InvalidImage(t *testing.T) {
+	if _, err := NewHeuristicAnalyst().DoAnalysis(context.Background(), "", "", []byte("not an image")); err == nil {
+		t.Error("期望非图片数据返回错误")
+	}
+}
@@ -0,0 +1,139 @@
+// File:		signal.go
+// Created by:	Hoven
+// Created on:	2025-05-27
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package heuristic
+
+import (
+	"image"
+	"math"
+)
+
+// maxSampleSide 计算信号前将长边缩放到的像素数，避免大图逐像素计算过慢
+const maxSampleSide = 512
+
+// Signals 图片的客观质量信号
+type Signals struct {
+	// Brightness 平均亮度，0-255
+	Brightness float64
+	// Contrast 亮度标准差，0-128
+	Contrast float64
+	// Sharpness 拉普拉斯算子响应的方差，越大越清晰
+	Sharpness float64
+	// Saturation 平均饱和度，0-1
+	Saturation float64
+	// AspectRatio 宽高比
+	AspectRatio float64
+}
+
+type sampledImage struct {
+	width  int
+	height int
+	luma   []float64
+	sat    []float64
+}
+
+// sample 以最近邻方式将图片缩放到长边不超过 maxSampleSide，并计算每个像素的亮度和饱和度
+func sample(img image.Image) *sampledImage {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	step := 1.0
+	if long := math.Max(float64(w), float64(h)); long > maxSampleSide {
+		step = long / maxSampleSide
+	}
+
+	sw, sh := int(float64(w)/step), int(float64(h)/step)
+	if sw < 1 {
+		sw = 1
+	}
+	if sh < 1 {
+		sh = 1
+	}
+
+	si := &sampledImage{
+		width:  sw,
+		height: sh,
+		luma:   make([]float64, sw*sh),
+		sat:    make([]float64, sw*sh),
+	}
+
+	for y := 0; y < sh; y++ {
+		for x := 0; x < sw; x++ {
+			px := bounds.Min.X + int(float64(x)*step)
+			py := bounds.Min.Y + int(float64(y)*step)
+			r16, g16, b16, _ := img.At(px, py).RGBA()
+			r, g, b := float64(r16>>8), float64(g16>>8), float64(b16>>8)
+
+			i := y*sw + x
+			si.luma[i] = 0.299*r + 0.587*g + 0.114*b
+
+			maxC := math.Max(r, math.Max(g, b))
+			minC := math.Min(r, math.Min(g, b))
+			if maxC > 0 {
+				si.sat[i] = (maxC - minC) / maxC
+			}
+		}
+	}
+
+	return si
+}
+
+func meanStd(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(values))
+
+	return mean, math.Sqrt(variance)
+}
+
+// laplacianVariance 使用 4 邻域拉普拉斯算子计算亮度图的二阶响应方差
+func (si *sampledImage) laplacianVariance() float64 {
+	if si.width < 3 || si.height < 3 {
+		return 0
+	}
+
+	responses := make([]float64, 0, (si.width-2)*(si.height-2))
+	for y := 1; y < si.height-1; y++ {
+		for x := 1; x < si.width-1; x++ {
+			i := y*si.width + x
+			lap := si.luma[i-si.width] + si.luma[i+si.width] + si.luma[i-1] + si.luma[i+1] - 4*si.luma[i]
+			responses = append(responses, lap)
+		}
+	}
+
+	_, std := meanStd(responses)
+	return std * std
+}
+
+func ComputeSignals(img image.Image) *Signals {
+	si := sample(img)
+
+	brightness, contrast := meanStd(si.luma)
+	saturation, _ := meanStd(si.sat)
+
+	bounds := img.Bounds()
+	return &Signals{
+		Brightness:  brightness,
+		Contrast:    contrast,
+		Sharpness:   si.laplacianVariance(),
+		Saturation:  saturation,
+		AspectRatio: float64(bounds.Dx()) / float64(bounds.Dy()),
+	}
+}
@@ -1,113 +0,0 @@
-// File:		data.go
-// Created by:	Hoven
-// Created on:	2025-02-09
-//
-// This file is part of the Example Project.
-//
-// (c) 2024 Example Corp. All rights reserved.
-
-package mock
-
-var (
-	descriptions = []string{
-		"五官精致立体，气质优雅大方，展现出独特的个人魅力。",
-		"笑容甜美，眼神温柔，给人温暖亲切的感觉，整体气质清新脱俗。",
-		"轮廓分明，妆容精致，皮肤状态很好，展现出健康活力的一面。",
-		"面部轮廓立体，五官搭配和谐。眼神清澈有神，眉形优美自然，整体妆容精致自然，突出了个人特色。皮肤细腻光滑，气色红润。",
-		"气质优雅大方，举手投足间散发迷人魅力。五官精致立体，尤其是眼睛明亮动人，笑容温暖真诚。发型设计别具匠心，与整体气质相得益彰。",
-		"五官精致立体，面部轮廓优美，皮肤白皙透亮，整体给人清新自然的感觉，举手投足间散发着独特的魅力，让人过目难忘。",
-		"眼睛明亮有神，笑容温暖甜美，气质温柔优雅，举止大方得体，举手投足间流露出知性优雅的气质，给人留下深刻的印象。",
-		"轮廓线条流畅，五官搭配和谐，皮肤状态很好，整体气质清新脱俗，举止投足间散发着独特的个人魅力，让人眼前一亮。",
-		"面部轮廓柔和，眉眼精致漂亮，笑容亲和有魅力，整体气质出众，一颦一笑间流露出迷人的气质，给人留下美好的印象。",
-		"皮肤光滑细腻，五官精致小巧，面部比例协调，展现出清新淡雅的气质，举手投足间透露出优雅知性的魅力，让人印象深刻。",
-		"眉目传神，轮廓分明，笑容甜美动人，整体气质优雅大方，一颦一笑间流露出独特的个人魅力，让人过目难忘。",
-		"面部线条柔美，五官精致立体，肤质细腻通透，散发迷人光彩，举手投足间尽显优雅知性的气质，给人留下深刻的印象。",
-		"眼神清澈明亮，面容姣好精致，气质优雅大方，展现独特魅力，一颦一笑间流露出迷人的气质，让人眼前一亮。",
-		"轮廓立体优美，五官精雕细琢，皮肤状态极佳，整体气质出众，举手投足间散发着独特的个人魅力，给人留下美好的印象。",
-		"面部轮廓柔和，眉眼灵动有神，笑容温暖迷人，整体气质清新脱俗，一颦一笑间透露出优雅知性的魅力，让人印象深刻。",
-		"五官精致小巧，面部比例和谐，肤质光滑细腻，气质清新脱俗，举手投足间流露出迷人的气质，让人过目难忘。",
-		"眉眼精致动人，面容姣好可人，笑容甜美温暖，散发迷人魅力，一颦一笑间尽显优雅知性的气质，给人留下深刻的印象。",
-		"轮廓优美流畅，五官搭配和谐，皮肤白皙透亮，展现自然气质，举手投足间散发着独特的个人魅力，让人眼前一亮。",
-		"面部特征精致，眼神清澈动人，笑容亲切自然，整体气质优雅大方，一颦一笑间流露出迷人的气质，给人留下美好的印象。",
-		"五官立体精致，面部轮廓柔美，气质优雅动人，展现独特魅力，举手投足间透露出优雅知性的魅力，让人印象深刻。",
-	}
-
-	allTags = []string{
-		// 五官相关
-		"气质佳", "五官精致", "笑容甜美", "眼神温柔",
-		"轮廓立体", "皮肤好", "妆容精致", "发型时尚",
-		"活力十足", "优雅知性", "自然清新", "魅力电眼",
-		"唇形完美", "发质柔顺", "比例协调", "肤质细腻",
-
-		// 气质相关
-		"温婉可人", "知性优雅", "甜美可爱", "清新脱俗",
-		"明艳动人", "气场强大", "温柔似水", "青春活泼",
-		"端庄大方", "时尚潮流", "清纯动人", "高贵典雅",
-
-		// 个性特征
-		"阳光活力", "邻家女孩", "御姐范儿", "可爱萝莉",
-		"温柔贤淑", "干练精致", "甜美淑女", "清冷气质",
-		"元气满满", "温柔似水", "优雅大方", "灵动可爱",
-
-		// 整体印象
-		"气质出众", "颜值在线", "形象佳", "魅力四射",
-		"光彩照人", "容颜靓丽", "风姿绰约", "韵味十足",
-		"明眸皓齿", "仪态优雅", "神采飞扬", "靓丽动人",
-	}
-
-	scoreLabels = []struct {
-		Label string
-		Descs []string
-	}{
-		{
-			Label: "五官",
-			Descs: []string{
-				"面部特征符合标准比例",
-				"五官分布均匀协调",
-				"面部对称性表现良好",
-				"眼部特征符合美学标准",
-				"鼻部形态符合解剖学标准",
-				"唇部轮廓清晰自然",
-				"面部立体度处于常规范围",
-			},
-		},
-		{
-			Label: "气质",
-			Descs: []string{
-				"仪态表现符合社交礼仪标准",
-				"肢体语言自然协调",
-				"表情管理符合场景需求",
-				"展现出较强的个人气场",
-				"具有普遍认可的个人特色",
-				"行为举止符合社会规范",
-				"整体表现稳定均衡",
-			},
-		},
-		{
-			Label: "妆容",
-			Descs: []string{
-				"底妆均匀覆盖皮肤表面",
-				"眼影色块分布符合标准",
-				"唇部产品应用完整规范",
-				"修容位置符合面部结构",
-				"高光位置准确",
-				"化妆品使用剂量适中",
-				"色号选择符合肤色基准",
-				"上妆手法符合操作规范",
-			},
-		},
-		{
-			Label: "发型",
-			Descs: []string{
-				"发型结构符合设计标准",
-				"发色保持均匀一致",
-				"层次分布符合技术要求",
-				"发质状态达到健康标准",
-				"刘海长度适中",
-				"发丝走向符合物理特性",
-				"造型保持度达到常规要求",
-				"头皮清洁度符合卫生标准",
-			},
-		},
-	}
-)
@@ -10,30 +10,56 @@ package mock
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
 
 	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
-	"github.com/yazl-tech/beauty-rating-server/pkg/random"
 )
 
 var _ analyst.Analyst = (*MockAnalyst)(nil)
 
-type MockAnalyst struct {
+const (
+	minTags = 3
+	maxTags = 6
+)
+
+type MockOption func(*MockAnalyst)
+
+// WithSalt 设置参与随机种子计算的盐，相同图片在不同盐下得到不同的结果
+func WithSalt(salt string) MockOption {
+	return func(m *MockAnalyst) {
+		m.salt = salt
+	}
 }
 
-func NewMockAnalyst() *MockAnalyst {
-	return &MockAnalyst{}
+func WithPhrasePack(pack *PhrasePack) MockOption {
+	return func(m *MockAnalyst) {
+		if pack != nil {
+			m.phrases = pack
+		}
+	}
 }
 
-func (m *MockAnalyst) generateScoreDetails() []analyst.Detail {
-	var details []analyst.Detail
-	for _, item := range scoreLabels {
-		details = append(details, analyst.Detail{
-			Label: item.Label,
-			Score: random.RandomNumber(85, 98),
-			Desc:  item.Descs[random.RandomInt(len(item.Descs))],
-		})
+// MockAnalyst 从文案包中随机拼装结果的模拟分析器
+//
+// 所有随机数都来自图片内容和盐计算出的种子，同一张图片永远得到相同的结果，
+// 分析过程中不会修改文案包，可以并发调用。
+type MockAnalyst struct {
+	salt    string
+	phrases *PhrasePack
+}
+
+func NewMockAnalyst(opts ...MockOption) *MockAnalyst {
+	m := &MockAnalyst{}
+	for _, opt := range opts {
+		opt(m)
 	}
-	return details
+
+	if m.phrases == nil {
+		m.phrases = DefaultPhrasePack()
+	}
+	return m
 }
 
 func (m *MockAnalyst) Name() string {
@@ -44,16 +70,48 @@ func (m *MockAnalyst) Typ() analyst.AnalystType {
 	return analyst.TypeMock
 }
 
-func (m *MockAnalyst) DoAnalysis(_ context.Context, _, _ string, _ []byte) (*analyst.Result, error) {
-	random.RandomShuffle(len(allTags), func(i, j int) {
-		allTags[i], allTags[j] = allTags[j], allTags[i]
-	})
+// seed 由图片内容和盐计算随机种子
+func (m *MockAnalyst) seed(image []byte) int64 {
+	h := sha256.New()
+	h.Write(image)
+	h.Write([]byte(m.salt))
+	return int64(binary.BigEndian.Uint64(h.Sum(nil)))
+}
+
+func randomNumber(r *rand.Rand, min, max int) int {
+	return r.Intn(max-min+1) + min
+}
+
+func (m *MockAnalyst) generateScoreDetails(r *rand.Rand) []analyst.Detail {
+	details := make([]analyst.Detail, 0, len(m.phrases.ScoreLabels))
+	for _, item := range m.phrases.ScoreLabels {
+		details = append(details, analyst.Detail{
+			Label: item.Label,
+			Score: randomNumber(r, 85, 98),
+			Desc:  item.Descs[r.Intn(len(item.Descs))],
+		})
+	}
+	return details
+}
+
+// pickTags 不修改文案包，随机挑选 minTags 到 maxTags 个不重复的标签
+func (m *MockAnalyst) pickTags(r *rand.Rand) []string {
+	perm := r.Perm(len(m.phrases.Tags))
+	tags := make([]string, randomNumber(r, minTags, maxTags))
+	for i := range tags {
+		tags[i] = m.phrases.Tags[perm[i]]
+	}
+	return tags
+}
+
+func (m *MockAnalyst) DoAnalysis(_ context.Context, _, _ string, image []byte) (*analyst.Result, error) {
+	r := rand.New(rand.NewSource(m.seed(image)))
 
 	return &analyst.Result{
 		AnalystType: analyst.TypeMock,
-		Score:       random.RandomNumber(85, 99),
-		Description: descriptions[random.RandomInt(len(descriptions))],
-		Tags:        allTags[:random.RandomNumber(3, 6)],
-		Details:     m.generateScoreDetails(),
+		Score:       randomNumber(r, 85, 99),
+		Description: m.phrases.Descriptions[r.Intn(len(m.phrases.Descriptions))],
+		Tags:        m.pickTags(r),
+		Details:     m.generateScoreDetails(r),
 	}, nil
 }
@@ -0,0 +1,109 @@
+// File:		mock_test.go
+// Created by:	Hoven
+// Created on:	2025-06-03
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package mock
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestDoAnalysisDeterministic(t *testing.T) {
+	image := []byte("same image")
+
+	first, err := NewMockAnalyst().DoAnalysis(context.Background(), "", "", image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := NewMockAnalyst().DoAnalysis(context.Background(), "", "", image)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("same image got different results:\n%+v\n%+v", first, second)
+	}
+
+	if len(first.Tags) < minTags || len(first.Tags) > maxTags {
+		t.Fatalf("unexpected tag count: %d", len(first.Tags))
+	}
+	if first.Score < 85 || first.Score > 99 {
+		t.Fatalf("score out of range: %d", first.Score)
+	}
+}
+
+func TestDoAnalysisSalt(t *testing.T) {
+	image := []byte("same image")
+
+	var differs bool
+	base, _ := NewMockAnalyst().DoAnalysis(context.Background(), "", "", image)
+	for _, salt := range []string{"a", "b", "c", "d"} {
+		ret, _ := NewMockAnalyst(WithSalt(salt)).DoAnalysis(context.Background(), "", "", image)
+		if !reflect.DeepEqual(base, ret) {
+			differs = true
+			break
+		}
+	}
+	if !differs {
+		t.Fatal("salt has no effect on result")
+	}
+}
+
+func TestDoAnalysisKeepsPhrasePack(t *testing.T) {
+	m := NewMockAnalyst()
+	tags := append([]string(nil), m.phrases.Tags...)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _ = m.DoAnalysis(context.Background(), "", "", []byte{byte(i)})
+		}(i)
+	}
+	wg.Wait()
+
+	if !reflect.DeepEqual(tags, m.phrases.Tags) {
+		t.Fatal("phrase pack tags mutated")
+	}
+}
+
+func TestLoadPhrasePack(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	content := `{
+		"descriptions": ["很好看"],
+		"tags": ["a", "b", "c", "d", "e", " f ", "a"],
+		"scoreLabels": [{"label": "五官", "descs": ["协调"]}]
+	}`
+	if err := os.WriteFile(valid, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pack, err := LoadPhrasePack(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c", "d", "e", "f"}; !reflect.DeepEqual(pack.Tags, want) {
+		t.Fatalf("tags: %v, want: %v", pack.Tags, want)
+	}
+
+	ret, _ := NewMockAnalyst(WithPhrasePack(pack)).DoAnalysis(context.Background(), "", "", []byte("x"))
+	if ret.Description != "很好看" || len(ret.Details) != 1 || ret.Details[0].Desc != "协调" {
+		t.Fatalf("result not built from phrase pack: %+v", ret)
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"descriptions": ["x"], "tags": ["a"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPhrasePack(invalid); err == nil {
+		t.Fatal("expected error for incomplete phrase pack")
+	}
+}
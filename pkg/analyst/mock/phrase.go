@@ -0,0 +1,114 @@
+// File:		phrase.go
+// Created by:	Hoven
+// Created on:	2025-06-03
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package mock
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+//go:embed phrases/default.json
+var builtinPhrases embed.FS
+
+type ScoreLabel struct {
+	Label string   `json:"label"`
+	Descs []string `json:"descs"`
+}
+
+// PhrasePack 模拟分析器用来拼装结果的文案
+type PhrasePack struct {
+	Descriptions []string     `json:"descriptions"`
+	Tags         []string     `json:"tags"`
+	ScoreLabels  []ScoreLabel `json:"scoreLabels"`
+}
+
+func parsePhrasePack(b []byte) (*PhrasePack, error) {
+	pack := &PhrasePack{}
+	if err := json.Unmarshal(b, pack); err != nil {
+		return nil, errors.Wrap(err, "unmarshal phrase pack")
+	}
+
+	if err := pack.normalize(); err != nil {
+		return nil, err
+	}
+	return pack, nil
+}
+
+// normalize 去掉空白和重复的文案，并检查每一类文案都不为空
+func (pp *PhrasePack) normalize() error {
+	pp.Descriptions = dedup(pp.Descriptions)
+	if len(pp.Descriptions) == 0 {
+		return errors.New("phrase pack has no descriptions")
+	}
+
+	pp.Tags = dedup(pp.Tags)
+	if len(pp.Tags) < maxTags {
+		return fmt.Errorf("phrase pack needs at least %d tags, got %d", maxTags, len(pp.Tags))
+	}
+
+	if len(pp.ScoreLabels) == 0 {
+		return errors.New("phrase pack has no scoreLabels")
+	}
+	for i, sl := range pp.ScoreLabels {
+		pp.ScoreLabels[i].Label = strings.TrimSpace(sl.Label)
+		pp.ScoreLabels[i].Descs = dedup(sl.Descs)
+		if pp.ScoreLabels[i].Label == "" || len(pp.ScoreLabels[i].Descs) == 0 {
+			return fmt.Errorf("scoreLabels[%d] missing label or descs", i)
+		}
+	}
+
+	return nil
+}
+
+func dedup(phrases []string) []string {
+	seen := make(map[string]bool, len(phrases))
+	ret := make([]string, 0, len(phrases))
+	for _, p := range phrases {
+		p = strings.TrimSpace(p)
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		ret = append(ret, p)
+	}
+	return ret
+}
+
+// LoadPhrasePack 从 JSON 文件加载文案包
+func LoadPhrasePack(path string) (*PhrasePack, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read phrase pack: %v", path)
+	}
+
+	pack, err := parsePhrasePack(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load phrase pack: %v", path)
+	}
+	return pack, nil
+}
+
+// DefaultPhrasePack 内置的文案包
+func DefaultPhrasePack() *PhrasePack {
+	b, err := builtinPhrases.ReadFile("phrases/default.json")
+	if err != nil {
+		panic(err)
+	}
+
+	pack, err := parsePhrasePack(b)
+	if err != nil {
+		panic(err)
+	}
+	return pack
+}
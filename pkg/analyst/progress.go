@@ -0,0 +1,44 @@
+// File:		progress.go
+// Created by:	Hoven
+// Created on:	2025-05-30
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analyst
+
+import "context"
+
+type Stage string
+
+const (
+	// StageAnalyst 选中了分析器，首选分析器失败切换到后备分析器时会再次发出
+	StageAnalyst Stage = "analyst"
+)
+
+type Progress struct {
+	Stage    Stage  `json:"stage"`
+	Analyst  string `json:"analyst,omitempty"`
+	Fallback bool   `json:"fallback,omitempty"`
+}
+
+// ProgressFunc 接收分析过程中的进度，组合分析器的成员会并发调用，实现需要保证并发安全
+type ProgressFunc func(Progress)
+
+type progressKey struct{}
+
+// WithProgress 返回携带进度回调的 ctx，分析器通过 ReportProgress 上报进度
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// ReportProgress 在 ctx 未携带进度回调时不做任何事
+func ReportProgress(ctx context.Context, p Progress) {
+	fn, ok := ctx.Value(progressKey{}).(ProgressFunc)
+	if !ok || fn == nil {
+		return
+	}
+
+	fn(p)
+}
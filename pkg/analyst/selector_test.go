@@ -0,0 +1,111 @@
+package analyst_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst/ai"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst/mock"
+	"github.com/yazl-tech/beauty-rating-server/pkg/fakebot"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const aiContent = `{
+	"score": 91,
+	"description": "整体气质清新",
+	"tags": ["清秀", "温柔", "阳光", "自然"],
+	"scoreDetails": [
+		{"label": "五官", "score": 90, "desc": "五官端正"},
+		{"label": "气质", "score": 93, "desc": "气质温和"},
+		{"label": "妆容", "score": 88, "desc": "妆容自然"},
+		{"label": "发型", "score": 86, "desc": "发型清爽"}
+	]
+}`
+
+func newSelector(bot *fakebot.Server, opts ...analyst.SelectorOption) *analyst.AnalystSelector {
+	opts = append([]analyst.SelectorOption{
+		analyst.WithAnalysts(ai.NewAiAnalyst("fake-model", bot.Client()), 1),
+		analyst.WithAnalysts(mock.NewMockAnalyst(), 0),
+	}, opts...)
+	return analyst.NewAnalystSelector(opts...)
+}
+
+func TestSelector_Ai(t *testing.T) {
+	bot := fakebot.Start(t, fakebot.WithReplies(fakebot.Reply{Content: aiContent}))
+	s := newSelector(bot)
+
+	ret, err := s.DoAnalysis(context.Background(), "a", "", []byte("image"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.AnalystName != "AiAnalyst" || ret.Fallback || ret.Score != 91 {
+		t.Errorf("期望使用 AI 分析器的结果，实际 %+v", ret)
+	}
+}
+
+func TestSelector_FallbackOnTimeout(t *testing.T) {
+	bot := fakebot.Start(t,
+		fakebot.WithLatency(time.Second),
+		fakebot.WithReplies(fakebot.Reply{Content: aiContent}),
+	)
+	s := newSelector(bot, analyst.WithAnalystTimeout(20*time.Millisecond))
+
+	var stages []string
+	ctx := analyst.WithProgress(context.Background(), func(p analyst.Progress) {
+		stages = append(stages, p.Analyst)
+	})
+
+	ret, err := s.DoAnalysis(ctx, "a", "", []byte("image"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.AnalystName != "MockAnalyst" || !ret.Fallback {
+		t.Errorf("期望 AI 超时后降级到模拟分析器，实际 %+v", ret)
+	}
+	if len(stages) != 2 || stages[0] != "AiAnalyst" {
+		t.Errorf("进度回调不符合预期: %v", stages)
+	}
+}
+
+func TestSelector_CircuitBreaker(t *testing.T) {
+	bot := fakebot.Start(t, fakebot.WithError(status.Error(codes.Unavailable, "ai-bot down")))
+	s := newSelector(bot, analyst.WithCircuitBreaker(2, time.Minute))
+
+	for i := 0; i < 4; i++ {
+		ret, err := s.DoAnalysis(context.Background(), "a", "", []byte("image"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ret.AnalystName != "MockAnalyst" {
+			t.Errorf("期望降级到模拟分析器，实际 %v", ret.AnalystName)
+		}
+	}
+
+	if bot.Calls() != 2 {
+		t.Errorf("期望熔断后不再请求 ai-bot，实际请求 %d 次", bot.Calls())
+	}
+}
+
+func TestSelector_CanceledNotCounted(t *testing.T) {
+	bot := fakebot.Start(t,
+		fakebot.WithLatency(time.Second),
+		fakebot.WithReplies(fakebot.Reply{Content: aiContent}),
+	)
+	s := newSelector(bot, analyst.WithCircuitBreaker(1, time.Minute))
+
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		_, err := s.DoAnalysis(ctx, "a", "", []byte("image"))
+		cancel()
+		if err == nil {
+			t.Fatal("期望调用方超时时返回错误")
+		}
+	}
+
+	if bot.Calls() != 2 {
+		t.Errorf("调用方取消不应触发熔断，期望请求 ai-bot 2 次，实际 %d 次", bot.Calls())
+	}
+}
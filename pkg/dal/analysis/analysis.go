@@ -11,6 +11,7 @@ package analysisRepo
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/go-puzzles/puzzles/plog"
 	"github.com/go-puzzles/puzzles/putils"
@@ -18,6 +19,9 @@ import (
 	"github.com/yazl-tech/beauty-rating-server/pkg/dal/base"
 	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
 	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"gorm.io/datatypes"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
 	"gorm.io/gorm"
 )
 
@@ -47,10 +51,43 @@ func (ar *AnalysisRepo) CreateAnalysisDetail(ctx context.Context, detail *analys
 	return nil
 }
 
-func (ar *AnalysisRepo) GetUserDetails(ctx context.Context, userId int) ([]*analysis.AnalysisDetail, error) {
+func (ar *AnalysisRepo) GetUserDetails(ctx context.Context, userId int, query *analysis.DetailQuery) ([]*analysis.AnalysisDetail, error) {
 	db := ar.db.Analysis
 
-	details, err := db.WithContext(ctx).Where(db.UserId.Eq(userId)).Find()
+	conds := []gen.Condition{db.UserId.Eq(userId)}
+	if query.CollectionId != 0 {
+		conds = append(conds, ar.inCollection(ctx, query.CollectionId))
+	}
+	if query.MinScore > 0 {
+		conds = append(conds, db.Score.Gte(query.MinScore))
+	}
+	if query.MaxScore > 0 {
+		conds = append(conds, db.Score.Lte(query.MaxScore))
+	}
+	if !query.Since.IsZero() {
+		conds = append(conds, db.CreatedAt.Gte(query.Since))
+	}
+	if !query.Until.IsZero() {
+		conds = append(conds, db.CreatedAt.Lt(query.Until))
+	}
+	if query.AnalystType != nil {
+		conds = append(conds, db.AnalyisType.Eq(*query.AnalystType))
+	}
+	if query.Tag != "" {
+		conds = append(conds, gen.Cond(datatypes.JSONArrayQuery(db.Tags.ColumnName().String()).Contains(query.Tag))...)
+	}
+	if query.After != nil {
+		conds = append(conds, ar.afterCursor(query))
+	}
+
+	do := db.WithContext(ctx).Where(conds...)
+	if query.SortBy == analysis.SortByScore {
+		do = do.Order(ar.orderBy(db.Score, query.Asc), ar.orderBy(db.ID, query.Asc))
+	} else {
+		do = do.Order(ar.orderBy(db.CreatedAt, query.Asc), ar.orderBy(db.ID, query.Asc))
+	}
+
+	details, err := do.Limit(query.Limit).Find()
 	if err != nil {
 		return nil, err
 	}
@@ -68,6 +105,31 @@ func (ar *AnalysisRepo) GetUserDetails(ctx context.Context, userId int) ([]*anal
 	return detailEntyties, nil
 }
 
+func (ar *AnalysisRepo) orderBy(f field.OrderExpr, asc bool) field.Expr {
+	if asc {
+		return f
+	}
+	return f.Desc()
+}
+
+// afterCursor 游标之后的报告：排序字段在游标之后，或排序字段相同且 id 在游标之后
+func (ar *AnalysisRepo) afterCursor(query *analysis.DetailQuery) field.Expr {
+	db := ar.db.Analysis
+	c := query.After
+
+	if query.SortBy == analysis.SortByScore {
+		if query.Asc {
+			return field.Or(db.Score.Gt(c.Score), field.And(db.Score.Eq(c.Score), db.ID.Gt(c.ID)))
+		}
+		return field.Or(db.Score.Lt(c.Score), field.And(db.Score.Eq(c.Score), db.ID.Lt(c.ID)))
+	}
+
+	if query.Asc {
+		return field.Or(db.CreatedAt.Gt(c.Date), field.And(db.CreatedAt.Eq(c.Date), db.ID.Gt(c.ID)))
+	}
+	return field.Or(db.CreatedAt.Lt(c.Date), field.And(db.CreatedAt.Eq(c.Date), db.ID.Lt(c.ID)))
+}
+
 func (ar *AnalysisRepo) GetUserDetail(ctx context.Context, userId int, detailId int) (*analysis.AnalysisDetail, error) {
 	db := ar.db.Analysis
 
@@ -94,6 +156,42 @@ func (ar *AnalysisRepo) GetDetail(ctx context.Context, detailId int) (*analysis.
 	return detail.ToEntity()
 }
 
+func (ar *AnalysisRepo) GetDetails(ctx context.Context, detailIds []int) ([]*analysis.AnalysisDetail, error) {
+	if len(detailIds) == 0 {
+		return nil, nil
+	}
+	db := ar.db.Analysis
+
+	details, err := db.WithContext(ctx).Where(db.ID.In(detailIds...)).Find()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*analysis.AnalysisDetail, 0, len(details))
+	for _, detail := range details {
+		de, err := detail.ToEntity()
+		if err != nil {
+			plog.Errorc(ctx, "convert detail: %v to entity error: %v", detail.ID, err)
+			continue
+		}
+		ret = append(ret, de)
+	}
+	return ret, nil
+}
+
+func (ar *AnalysisRepo) GetDetailByJob(ctx context.Context, jobId int) (*analysis.AnalysisDetail, error) {
+	db := ar.db.Analysis
+
+	detail, err := db.WithContext(ctx).Where(db.JobId.Eq(jobId)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return detail.ToEntity()
+}
+
 func (ar *AnalysisRepo) UpdateAnalysisDetail(ctx context.Context, detail *analysis.AnalysisDetail) error {
 	if detail.ID == 0 {
 		return exception.ErrNotSpecifyDetail
@@ -114,51 +212,186 @@ func (ar *AnalysisRepo) UpdateAnalysisDetail(ctx context.Context, detail *analys
 	return nil
 }
 
-func (ar *AnalysisRepo) GetUserFavoriteDetails(ctx context.Context, userId int) ([]*analysis.AnalysisDetail, error) {
+// DeleteAnalysisDetail 删除报告的同时删除其所有版本并将其移出所有收藏夹
+func (ar *AnalysisRepo) DeleteAnalysisDetail(ctx context.Context, userId int, detailId int) error {
+	return ar.db.Transaction(func(tx *base.Query) error {
+		db := tx.Analysis
+
+		info, err := db.WithContext(ctx).Where(db.ID.Eq(detailId), db.UserId.Eq(userId)).Delete()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exception.ErrDetailNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if info.RowsAffected == 0 {
+			return exception.ErrDetailNotFound
+		}
+
+		version := tx.AnalysisVersion
+		if _, err := version.WithContext(ctx).Where(version.ReportId.Eq(detailId)).Delete(); err != nil {
+			return err
+		}
+
+		item := tx.AnalysisCollectionItem
+		_, err = item.WithContext(ctx).Where(item.ReportId.Eq(detailId)).Delete()
+		return err
+	})
+}
+
+func (ar *AnalysisRepo) CheckDetailExists(ctx context.Context, userId, detailId int) bool {
+	db := ar.db.Analysis
+
+	count, err := db.WithContext(ctx).Where(db.ID.Eq(detailId), db.UserId.Eq(userId)).Count()
+	if err != nil {
+		return false
+	}
+
+	return count > 0
+}
+
+func (ar *AnalysisRepo) GetScoreRank(ctx context.Context, scope *analysis.RankScope, score int) (*analysis.ScoreRank, error) {
 	db := ar.db.Analysis
 
-	details, err := db.WithContext(ctx).Where(db.UserId.Eq(userId), db.IsFavorite.Is(true)).Find()
+	conds := []gen.Condition{
+		db.AnalyisType.Eq(scope.AnalystType),
+		db.Percentile.Neq(analysis.PercentileUnranked),
+	}
+	if scope.ByGender {
+		conds = append(conds, db.Gender.Eq(scope.Gender))
+	}
+	if scope.ExcludeId != 0 {
+		conds = append(conds, db.ID.Neq(scope.ExcludeId))
+	}
+
+	// 一次聚合查询同时统计总数、低于和等于该分数的记录数
+	rank := new(analysis.ScoreRank)
+	err := db.WithContext(ctx).
+		Select(
+			db.ID.Count().As("total"),
+			field.NewUnsafeFieldRaw("COALESCE(SUM(CASE WHEN score < ? THEN 1 ELSE 0 END), 0)", score).As("below"),
+			field.NewUnsafeFieldRaw("COALESCE(SUM(CASE WHEN score = ? THEN 1 ELSE 0 END), 0)", score).As("equal"),
+		).
+		Where(conds...).
+		Scan(rank)
 	if err != nil {
 		return nil, err
 	}
 
-	detailEntyties := putils.Convert(details, func(detail *model.Analysis) *analysis.AnalysisDetail {
-		de, err := detail.ToEntity()
-		if err != nil {
-			plog.Errorc(ctx, "convert detail: %v to entity error: %v", detail.ID, err)
-			return nil
-		}
+	return rank, nil
+}
 
-		return de
-	})
+func (ar *AnalysisRepo) UpdatePercentile(ctx context.Context, detailId, percentile int) error {
+	db := ar.db.Analysis
 
-	return detailEntyties, nil
+	_, err := db.WithContext(ctx).Where(db.ID.Eq(detailId)).Update(db.Percentile, percentile)
+	return err
 }
 
-func (ar *AnalysisRepo) DeleteAnalysisDetail(ctx context.Context, userId int, detailId int) error {
+func (ar *AnalysisRepo) GetLatestDetailByHash(ctx context.Context, imageHash string, userId int, experiment, variant string, since time.Time) (*analysis.AnalysisDetail, error) {
 	db := ar.db.Analysis
 
-	info, err := db.WithContext(ctx).Where(db.ID.Eq(detailId), db.UserId.Eq(userId)).Delete()
+	conds := []gen.Condition{
+		db.ImageHash.Eq(imageHash),
+		db.Experiment.Eq(experiment),
+		db.Variant.Eq(variant),
+		db.CreatedAt.Gte(since),
+	}
+	if userId != 0 {
+		conds = append(conds, db.UserId.Eq(userId))
+	}
+
+	detail, err := db.WithContext(ctx).Where(conds...).Order(db.CreatedAt.Desc()).First()
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return exception.ErrDetailNotFound
+		return nil, nil
 	} else if err != nil {
-		return err
+		return nil, err
+	}
+
+	return detail.ToEntity()
+}
+
+func (ar *AnalysisRepo) GetDetailsWithoutVariants(ctx context.Context, afterId, limit int) ([]*analysis.AnalysisDetail, error) {
+	db := ar.db.Analysis
+
+	details, err := db.WithContext(ctx).
+		Select(db.ID, db.ImageUrl).
+		Where(db.ID.Gt(afterId), db.HasVariants.Is(false)).
+		Order(db.ID).
+		Limit(limit).
+		Find()
+	if err != nil {
+		return nil, err
 	}
 
-	if info.RowsAffected == 0 {
-		return exception.ErrDetailNotFound
+	return putils.Convert(details, func(detail *model.Analysis) *analysis.AnalysisDetail {
+		return &analysis.AnalysisDetail{ID: detail.ID, ImageUrl: detail.ImageUrl}
+	}), nil
+}
+
+func (ar *AnalysisRepo) GetUnrankedDetails(ctx context.Context, afterId, limit int) ([]*analysis.AnalysisDetail, error) {
+	db := ar.db.Analysis
+
+	details, err := db.WithContext(ctx).
+		Select(db.ID, db.AnalyisType, db.Gender, db.Score).
+		Where(db.ID.Gt(afterId), db.Percentile.Eq(analysis.PercentileUnranked)).
+		Order(db.ID).
+		Limit(limit).
+		Find()
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return putils.Convert(details, func(detail *model.Analysis) *analysis.AnalysisDetail {
+		return &analysis.AnalysisDetail{
+			ID:          detail.ID,
+			AnalyisType: detail.AnalyisType,
+			Gender:      detail.Gender,
+			Score:       detail.Score,
+			Percentile:  analysis.PercentileUnranked,
+		}
+	}), nil
 }
 
-func (ar *AnalysisRepo) CheckDetailExists(ctx context.Context, userId, detailId int) bool {
+func (ar *AnalysisRepo) MarkVariantsReady(ctx context.Context, detailId int) error {
 	db := ar.db.Analysis
 
-	count, err := db.WithContext(ctx).Where(db.ID.Eq(detailId), db.UserId.Eq(userId)).Count()
+	_, err := db.WithContext(ctx).Where(db.ID.Eq(detailId)).Update(db.HasVariants, true)
+	return err
+}
+
+func (ar *AnalysisRepo) MarkShared(ctx context.Context, detailId int) error {
+	db := ar.db.Analysis
+
+	_, err := db.WithContext(ctx).Where(db.ID.Eq(detailId)).Update(db.IsShared, true)
+	return err
+}
+
+// favoritesExpr 统计在默认收藏夹中的非降级报告数
+const favoritesExpr = "SUM(CASE WHEN NOT is_fallback AND EXISTS (SELECT 1 FROM analysis_collection_items ci " +
+	"JOIN analysis_collections c ON c.id = ci.collection_id AND c.is_default " +
+	"WHERE ci.report_id = analysises.id) THEN 1 ELSE 0 END)"
+
+func (ar *AnalysisRepo) GetVariantStats(ctx context.Context, experiment string) ([]*analysis.VariantStats, error) {
+	db := ar.db.Analysis
+
+	var stats []*analysis.VariantStats
+	err := db.WithContext(ctx).
+		Select(
+			db.Variant,
+			field.NewUnsafeFieldRaw("SUM(CASE WHEN NOT is_fallback THEN 1 ELSE 0 END)").As("total"),
+			field.NewUnsafeFieldRaw("COALESCE(AVG(CASE WHEN NOT is_fallback THEN score END), 0)").As("avg_score"),
+			field.NewUnsafeFieldRaw(favoritesExpr).As("favorites"),
+			field.NewUnsafeFieldRaw("SUM(CASE WHEN NOT is_fallback AND is_shared THEN 1 ELSE 0 END)").As("shares"),
+			field.NewUnsafeFieldRaw("SUM(CASE WHEN is_fallback THEN 1 ELSE 0 END)").As("fallbacks"),
+		).
+		Where(db.Experiment.Eq(experiment)).
+		Group(db.Variant).
+		Order(db.Variant).
+		Scan(&stats)
 	if err != nil {
-		return false
+		return nil, err
 	}
 
-	return count > 0
+	return stats, nil
 }
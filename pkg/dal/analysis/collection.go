@@ -0,0 +1,248 @@
+// File:		collection.go
+// Created by:	Hoven
+// Created on:	2025-06-15
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysisRepo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-puzzles/puzzles/putils"
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/base"
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (ar *AnalysisRepo) CreateCollection(ctx context.Context, collection *analysis.Collection) error {
+	collectionDal := new(model.AnalysisCollection)
+	collectionDal.FromEntity(collection)
+
+	if err := ar.db.AnalysisCollection.WithContext(ctx).Create(collectionDal); err != nil {
+		return err
+	}
+
+	collection.ID = collectionDal.ID
+	collection.Date = collectionDal.CreatedAt
+	return nil
+}
+
+func (ar *AnalysisRepo) GetUserCollection(ctx context.Context, userId, collectionId int) (*analysis.Collection, error) {
+	db := ar.db.AnalysisCollection
+
+	collection, err := db.WithContext(ctx).Where(db.ID.Eq(collectionId), db.UserId.Eq(userId)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.ErrCollectionNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return collection.ToEntity(), nil
+}
+
+func (ar *AnalysisRepo) GetDefaultCollection(ctx context.Context, userId int) (*analysis.Collection, error) {
+	db := ar.db.AnalysisCollection
+
+	collection, err := db.WithContext(ctx).Where(db.UserId.Eq(userId), db.IsDefault.Is(true)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return collection.ToEntity(), nil
+}
+
+func (ar *AnalysisRepo) GetUserCollections(ctx context.Context, userId int) ([]*analysis.Collection, error) {
+	db := ar.db.AnalysisCollection
+
+	collections, err := db.WithContext(ctx).
+		Where(db.UserId.Eq(userId)).
+		Order(db.IsDefault.Desc(), db.Position, db.ID).
+		Find()
+	if err != nil {
+		return nil, err
+	}
+
+	return putils.Convert(collections, func(c *model.AnalysisCollection) *analysis.Collection {
+		return c.ToEntity()
+	}), nil
+}
+
+func (ar *AnalysisRepo) UpdateCollection(ctx context.Context, collection *analysis.Collection) error {
+	db := ar.db.AnalysisCollection
+
+	_, err := db.WithContext(ctx).
+		Where(db.ID.Eq(collection.ID)).
+		UpdateSimple(
+			db.Name.Value(collection.Name),
+			db.CoverReportId.Value(collection.CoverReportID),
+		)
+	return err
+}
+
+func (ar *AnalysisRepo) SortCollections(ctx context.Context, userId int, positions map[int]int) error {
+	return ar.db.Transaction(func(tx *base.Query) error {
+		db := tx.AnalysisCollection
+		for id, position := range positions {
+			_, err := db.WithContext(ctx).
+				Where(db.ID.Eq(id), db.UserId.Eq(userId)).
+				UpdateSimple(db.Position.Value(position))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (ar *AnalysisRepo) DeleteCollection(ctx context.Context, collectionId int) error {
+	return ar.db.Transaction(func(tx *base.Query) error {
+		item := tx.AnalysisCollectionItem
+		if _, err := item.WithContext(ctx).Where(item.CollectionId.Eq(collectionId)).Delete(); err != nil {
+			return err
+		}
+
+		db := tx.AnalysisCollection
+		_, err := db.WithContext(ctx).Where(db.ID.Eq(collectionId)).Delete()
+		return err
+	})
+}
+
+func (ar *AnalysisRepo) AddCollectionItem(ctx context.Context, item *analysis.CollectionItem) error {
+	itemDal := new(model.AnalysisCollectionItem)
+	itemDal.FromEntity(item)
+
+	return ar.db.AnalysisCollectionItem.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(itemDal)
+}
+
+func (ar *AnalysisRepo) RemoveCollectionItem(ctx context.Context, collectionId, reportId int) error {
+	db := ar.db.AnalysisCollectionItem
+
+	_, err := db.WithContext(ctx).Where(db.CollectionId.Eq(collectionId), db.ReportId.Eq(reportId)).Delete()
+	return err
+}
+
+// collectionSummary 收藏夹汇总查询的结果，CoverReportId 为指定的封面，不在收藏夹中时为最新的报告
+type collectionSummary struct {
+	CollectionId  int
+	ReportCount   int64
+	CoverReportId int
+}
+
+func (ar *AnalysisRepo) GetCollectionSummaries(ctx context.Context, collectionIds []int) ([]*analysis.CollectionSummary, error) {
+	if len(collectionIds) == 0 {
+		return nil, nil
+	}
+
+	item := ar.db.AnalysisCollectionItem
+	c := ar.db.AnalysisCollection
+	a := ar.db.Analysis
+
+	var rows []*collectionSummary
+	err := item.WithContext(ctx).
+		Select(
+			item.CollectionId,
+			item.ReportId.Count().As("report_count"),
+			field.NewUnsafeFieldRaw("COALESCE(MAX(CASE WHEN ? = ? THEN ? END), MAX(?))", a.ID, c.CoverReportId, a.ID, a.ID).As("cover_report_id"),
+		).
+		Join(c, c.ID.EqCol(item.CollectionId)).
+		Join(a, a.ID.EqCol(item.ReportId), a.DeletedAt.IsNull()).
+		Where(item.CollectionId.In(collectionIds...)).
+		Group(item.CollectionId).
+		Scan(&rows)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	coverIds := putils.Convert(rows, func(r *collectionSummary) int { return r.CoverReportId })
+	covers, err := a.WithContext(ctx).Select(a.ID, a.ImageUrl, a.HasVariants).Where(a.ID.In(coverIds...)).Find()
+	if err != nil {
+		return nil, err
+	}
+	coverById := make(map[int]*analysis.AnalysisDetail, len(covers))
+	for _, cover := range covers {
+		coverById[cover.ID] = &analysis.AnalysisDetail{ID: cover.ID, ImageUrl: cover.ImageUrl, HasVariants: cover.HasVariants}
+	}
+
+	return putils.Convert(rows, func(r *collectionSummary) *analysis.CollectionSummary {
+		return &analysis.CollectionSummary{
+			CollectionID: r.CollectionId,
+			ReportCount:  r.ReportCount,
+			Cover:        coverById[r.CoverReportId],
+		}
+	}), nil
+}
+
+func (ar *AnalysisRepo) FilterCollectedReports(ctx context.Context, collectionId int, reportIds []int) ([]int, error) {
+	db := ar.db.AnalysisCollectionItem
+
+	collected := make([]int, 0, len(reportIds))
+	if len(reportIds) == 0 {
+		return collected, nil
+	}
+
+	err := db.WithContext(ctx).
+		Where(db.CollectionId.Eq(collectionId), db.ReportId.In(reportIds...)).
+		Pluck(db.ReportId, &collected)
+	if err != nil {
+		return nil, err
+	}
+
+	return collected, nil
+}
+
+// inCollection 报告在收藏夹中的筛选条件，以子查询筛选以免关联查询覆盖报告的字段
+func (ar *AnalysisRepo) inCollection(ctx context.Context, collectionId int) gen.Condition {
+	item := ar.db.AnalysisCollectionItem
+	reports := item.WithContext(ctx).Select(item.ReportId).Where(item.CollectionId.Eq(collectionId))
+	return gen.Columns{ar.db.Analysis.ID}.In(reports)
+}
+
+const (
+	migrateDefaultCollections = "INSERT IGNORE INTO analysis_collections (user_id, name, is_default, cover_report_id, position, created_at, updated_at) " +
+		"SELECT DISTINCT user_id, ?, TRUE, 0, 0, NOW(), NOW() FROM analysises WHERE is_favorite AND deleted_at IS NULL"
+	migrateFavoriteItems = "INSERT IGNORE INTO analysis_collection_items (collection_id, report_id, user_id, created_at) " +
+		"SELECT c.id, a.id, a.user_id, a.updated_at FROM analysises a " +
+		"JOIN analysis_collections c ON c.user_id = a.user_id AND c.is_default " +
+		"WHERE a.is_favorite AND a.deleted_at IS NULL"
+	clearFavoriteFlags = "UPDATE analysises SET is_favorite = FALSE WHERE is_favorite"
+)
+
+// MigrateFavorites 将旧版本 analysises.is_favorite 标记的收藏迁移到用户的默认收藏夹，返回新迁移的收藏数，
+// 旧字段不存在时不做处理
+//
+// 迁移后在同一个事务中清除旧标记，重复执行不会把用户已经取消的收藏重新加回收藏夹
+func (ar *AnalysisRepo) MigrateFavorites(ctx context.Context) (int64, error) {
+	db := ar.db.Analysis.WithContext(ctx).UnderlyingDB()
+	if !db.Migrator().HasColumn(&model.Analysis{}, "is_favorite") {
+		return 0, nil
+	}
+
+	var migrated int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(migrateDefaultCollections, analysis.DefaultCollectionName).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(migrateFavoriteItems)
+		if result.Error != nil {
+			return result.Error
+		}
+		migrated = result.RowsAffected
+
+		return tx.Exec(clearFavoriteFlags).Error
+	})
+	return migrated, err
+}
@@ -0,0 +1,101 @@
+// File:		comparison.go
+// Created by:	Hoven
+// Created on:	2025-06-11
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysisRepo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"gorm.io/gen"
+	"gorm.io/gorm"
+)
+
+func (ar *AnalysisRepo) CreateComparison(ctx context.Context, comparison *analysis.Comparison) error {
+	comparisonDal := new(model.AnalysisComparison)
+	if err := comparisonDal.FromEntity(comparison); err != nil {
+		return err
+	}
+
+	if err := ar.db.AnalysisComparison.WithContext(ctx).Create(comparisonDal); err != nil {
+		return err
+	}
+
+	comparison.ID = comparisonDal.ID
+	comparison.Date = comparisonDal.CreatedAt
+	return nil
+}
+
+func (ar *AnalysisRepo) GetComparison(ctx context.Context, compareId int) (*analysis.Comparison, error) {
+	db := ar.db.AnalysisComparison
+
+	comparison, err := db.WithContext(ctx).Where(db.ID.Eq(compareId)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.ErrComparisonNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return comparison.ToEntity()
+}
+
+func (ar *AnalysisRepo) GetUserComparison(ctx context.Context, userId, compareId int) (*analysis.Comparison, error) {
+	db := ar.db.AnalysisComparison
+
+	comparison, err := db.WithContext(ctx).Where(db.ID.Eq(compareId), db.UserId.Eq(userId)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.ErrComparisonNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return comparison.ToEntity()
+}
+
+func (ar *AnalysisRepo) GetUserComparisons(ctx context.Context, userId int, query *analysis.ComparisonQuery) ([]*analysis.Comparison, error) {
+	db := ar.db.AnalysisComparison
+
+	conds := []gen.Condition{db.UserId.Eq(userId)}
+	if query.FavoriteOnly {
+		conds = append(conds, db.IsFavorite.Is(true))
+	}
+	// id 随创建时间递增，按 id 倒序即按时间倒序
+	if query.After != nil {
+		conds = append(conds, db.ID.Lt(query.After.ID))
+	}
+
+	comparisons, err := db.WithContext(ctx).Where(conds...).Order(db.ID.Desc()).Limit(query.Limit).Find()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*analysis.Comparison, 0, len(comparisons))
+	for _, c := range comparisons {
+		ac, err := c.ToEntity()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, ac)
+	}
+	return ret, nil
+}
+
+func (ar *AnalysisRepo) UpdateComparison(ctx context.Context, comparison *analysis.Comparison) error {
+	db := ar.db.AnalysisComparison
+
+	_, err := db.WithContext(ctx).
+		Where(db.ID.Eq(comparison.ID)).
+		UpdateSimple(
+			db.IsFavorite.Value(comparison.IsFavorite),
+			db.IsShared.Value(comparison.IsShared),
+		)
+	return err
+}
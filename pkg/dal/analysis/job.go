@@ -0,0 +1,162 @@
+// File:		job.go
+// Created by:	Hoven
+// Created on:	2025-05-29
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysisRepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-puzzles/puzzles/putils"
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/base"
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"gorm.io/gen"
+	"gorm.io/gorm"
+)
+
+var _ analysis.JobRepo = (*JobRepo)(nil)
+
+type JobRepo struct {
+	db *base.Query
+}
+
+func NewJobRepo(db *gorm.DB) *JobRepo {
+	return &JobRepo{db: base.Use(db)}
+}
+
+func (jr *JobRepo) CreateJob(ctx context.Context, job *analysis.AnalysisJob) error {
+	jobDal := new(model.AnalysisJob)
+	jobDal.FromEntity(job)
+
+	err := jr.db.AnalysisJob.WithContext(ctx).Create(jobDal)
+	if err != nil {
+		return err
+	}
+
+	job.ID = jobDal.ID
+	job.CreatedAt = jobDal.CreatedAt
+	job.UpdatedAt = jobDal.UpdatedAt
+	return nil
+}
+
+func (jr *JobRepo) GetJob(ctx context.Context, jobId int) (*analysis.AnalysisJob, error) {
+	db := jr.db.AnalysisJob
+
+	job, err := db.WithContext(ctx).Where(db.ID.Eq(jobId)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.ErrJobNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return job.ToEntity(), nil
+}
+
+func (jr *JobRepo) GetUserJob(ctx context.Context, userId, jobId int) (*analysis.AnalysisJob, error) {
+	db := jr.db.AnalysisJob
+
+	job, err := db.WithContext(ctx).Where(db.ID.Eq(jobId), db.UserId.Eq(userId)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.ErrJobNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return job.ToEntity(), nil
+}
+
+func (jr *JobRepo) ClaimJob(ctx context.Context, job *analysis.AnalysisJob) (bool, error) {
+	db := jr.db.AnalysisJob
+
+	info, err := db.WithContext(ctx).
+		Where(db.ID.Eq(job.ID), db.Status.Eq(string(analysis.JobPending)), db.Attempts.Eq(job.Attempts)).
+		UpdateSimple(
+			db.Status.Value(string(analysis.JobRunning)),
+			db.Attempts.Add(1),
+		)
+	if err != nil {
+		return false, err
+	}
+	if info.RowsAffected == 0 {
+		return false, nil
+	}
+
+	job.Status = analysis.JobRunning
+	job.Attempts++
+	return true, nil
+}
+
+// leased 以领取时的执行次数作为租约，任务被重新领取后执行次数会增加
+func (jr *JobRepo) leased(job *analysis.AnalysisJob) []gen.Condition {
+	db := jr.db.AnalysisJob
+	return []gen.Condition{
+		db.ID.Eq(job.ID),
+		db.Status.Eq(string(analysis.JobRunning)),
+		db.Attempts.Eq(job.Attempts),
+	}
+}
+
+func (jr *JobRepo) HeartbeatJob(ctx context.Context, job *analysis.AnalysisJob) (bool, error) {
+	db := jr.db.AnalysisJob
+
+	info, err := db.WithContext(ctx).Where(jr.leased(job)...).UpdateColumn(db.UpdatedAt, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	return info.RowsAffected > 0, nil
+}
+
+func (jr *JobRepo) FinishJob(ctx context.Context, job *analysis.AnalysisJob) (bool, error) {
+	db := jr.db.AnalysisJob
+
+	info, err := db.WithContext(ctx).
+		Where(jr.leased(job)...).
+		UpdateSimple(
+			db.Status.Value(string(job.Status)),
+			db.DetailId.Value(job.DetailID),
+			db.Error.Value(job.Error),
+			db.Message.Value(job.Message),
+		)
+	if err != nil {
+		return false, err
+	}
+
+	return info.RowsAffected > 0, nil
+}
+
+func (jr *JobRepo) RequeueJob(ctx context.Context, job *analysis.AnalysisJob) error {
+	db := jr.db.AnalysisJob
+
+	_, err := db.WithContext(ctx).
+		Where(jr.leased(job)...).
+		UpdateSimple(db.Status.Value(string(analysis.JobPending)))
+	return err
+}
+
+func (jr *JobRepo) GetUnfinishedJobs(ctx context.Context, before time.Time) ([]*analysis.AnalysisJob, error) {
+	db := jr.db.AnalysisJob
+
+	jobs, err := db.WithContext(ctx).
+		Where(
+			db.Status.In(string(analysis.JobPending), string(analysis.JobRunning)),
+			db.UpdatedAt.Lt(before),
+		).
+		Order(db.ID).
+		Find()
+	if err != nil {
+		return nil, err
+	}
+
+	return putils.Convert(jobs, func(job *model.AnalysisJob) *analysis.AnalysisJob {
+		return job.ToEntity()
+	}), nil
+}
@@ -0,0 +1,149 @@
+// File:		stats.go
+// Created by:	Hoven
+// Created on:	2025-06-13
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysisRepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"gorm.io/gen/field"
+)
+
+// periodExprs 报告所属周期的 SQL 表达式，按周统计时以当周周一表示
+var periodExprs = map[analysis.StatsPeriod]string{
+	analysis.StatsByWeek:  "DATE_FORMAT(DATE_SUB(created_at, INTERVAL WEEKDAY(created_at) DAY), '%Y-%m-%d')",
+	analysis.StatsByMonth: "DATE_FORMAT(created_at, '%Y-%m')",
+}
+
+// scoreDetailsTable、tagsTable 将 JSON 数组展开为行，需要 MySQL 8.0 及以上版本
+const (
+	scoreDetailsTable = "CROSS JOIN JSON_TABLE(%s, '$[*]' COLUMNS (label VARCHAR(64) PATH '$.label', label_score INT PATH '$.score')) AS sd"
+	tagsTable         = "CROSS JOIN JSON_TABLE(%s, '$[*]' COLUMNS (tag VARCHAR(64) PATH '$')) AS jt"
+)
+
+func (ar *AnalysisRepo) GetUserStats(ctx context.Context, userId int) (*analysis.UserStats, error) {
+	db := ar.db.Analysis
+
+	count, err := db.WithContext(ctx).Where(db.UserId.Eq(userId)).Count()
+	if err != nil {
+		return nil, err
+	}
+	stats := &analysis.UserStats{Count: count}
+	if count == 0 {
+		return stats, nil
+	}
+
+	var summary struct {
+		BestScore int
+		AvgScore  float64
+	}
+	err = db.WithContext(ctx).
+		Select(
+			db.Score.Max().As("best_score"),
+			db.Score.Avg().As("avg_score"),
+		).
+		Where(db.UserId.Eq(userId)).
+		Scan(&summary)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := db.WithContext(ctx).
+		Select(db.Score, db.CreatedAt).
+		Where(db.UserId.Eq(userId)).
+		Order(db.CreatedAt.Desc(), db.ID.Desc()).
+		First()
+	if err != nil {
+		return nil, err
+	}
+
+	stats.BestScore = summary.BestScore
+	stats.AvgScore = summary.AvgScore
+	stats.LatestScore = latest.Score
+	stats.LatestDate = latest.CreatedAt
+	return stats, nil
+}
+
+func (ar *AnalysisRepo) GetScoreTrend(ctx context.Context, userId int, period analysis.StatsPeriod, since time.Time) ([]*analysis.TrendPoint, error) {
+	db := ar.db.Analysis
+
+	expr, ok := periodExprs[period]
+	if !ok {
+		return nil, fmt.Errorf("unknown stats period: %v", period)
+	}
+	periodField := field.NewField("", "period")
+
+	var trend []*analysis.TrendPoint
+	err := db.WithContext(ctx).
+		Select(
+			field.NewUnsafeFieldRaw(expr).As("period"),
+			db.ID.Count().As("count"),
+			db.Score.Avg().As("avg_score"),
+			db.Score.Max().As("best_score"),
+		).
+		Where(db.UserId.Eq(userId), db.CreatedAt.Gte(since)).
+		Group(periodField).
+		Order(periodField).
+		Scan(&trend)
+	if err != nil {
+		return nil, err
+	}
+
+	return trend, nil
+}
+
+func (ar *AnalysisRepo) GetLabelAverages(ctx context.Context, userId int) ([]*analysis.LabelAverage, error) {
+	db := ar.db.Analysis
+
+	var labels []*analysis.LabelAverage
+	err := db.WithContext(ctx).
+		Select(
+			field.NewUnsafeFieldRaw("sd.label").As("label"),
+			field.NewUnsafeFieldRaw("AVG(sd.label_score)").As("avg_score"),
+			db.ID.Count().As("count"),
+		).
+		Where(db.UserId.Eq(userId)).
+		Group(field.NewField("", "label")).
+		Order(field.NewField("", "count").Desc(), field.NewField("", "label")).
+		UnderlyingDB().
+		Joins(fmt.Sprintf(scoreDetailsTable, db.ScoreDetails.ColumnName())).
+		Where("sd.label IS NOT NULL AND sd.label <> ''").
+		Scan(&labels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+func (ar *AnalysisRepo) GetTopTags(ctx context.Context, userId int, limit int) ([]*analysis.TagCount, error) {
+	db := ar.db.Analysis
+
+	var tags []*analysis.TagCount
+	err := db.WithContext(ctx).
+		Select(
+			field.NewUnsafeFieldRaw("jt.tag").As("tag"),
+			db.ID.Count().As("count"),
+		).
+		Where(db.UserId.Eq(userId)).
+		Group(field.NewField("", "tag")).
+		Order(field.NewField("", "count").Desc(), field.NewField("", "tag")).
+		Limit(limit).
+		UnderlyingDB().
+		Joins(fmt.Sprintf(tagsTable, db.Tags.ColumnName())).
+		Where("jt.tag IS NOT NULL AND jt.tag <> ''").
+		Scan(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,96 @@
+// File:		version.go
+// Created by:	Hoven
+// Created on:	2025-06-09
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package analysisRepo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/base"
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func createVersion(ctx context.Context, tx *base.Query, version *analysis.AnalysisVersion) error {
+	versionDal := new(model.AnalysisVersion)
+	if err := versionDal.FromEntity(version); err != nil {
+		return err
+	}
+
+	if err := tx.AnalysisVersion.WithContext(ctx).Create(versionDal); err != nil {
+		return err
+	}
+
+	version.ID = versionDal.ID
+	version.Date = versionDal.CreatedAt
+	return nil
+}
+
+// CreatePrimaryVersion 锁定报告后按已有的最大版本号分配新版本号，同一报告的并发重新分析在锁上排队，
+// 不会产生重复的版本号；保存版本和更新报告在同一个事务中完成
+func (ar *AnalysisRepo) CreatePrimaryVersion(ctx context.Context, detail *analysis.AnalysisDetail, original, version *analysis.AnalysisVersion) error {
+	return ar.db.Transaction(func(tx *base.Query) error {
+		db := tx.Analysis
+		_, err := db.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select(db.ID).
+			Where(db.ID.Eq(detail.ID)).
+			Take()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exception.ErrDetailNotFound
+		} else if err != nil {
+			return err
+		}
+
+		vdb := tx.AnalysisVersion
+		latest, err := vdb.WithContext(ctx).Where(vdb.ReportId.Eq(detail.ID)).Order(vdb.Version.Desc()).Take()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := createVersion(ctx, tx, original); err != nil {
+				return err
+			}
+			latest = &model.AnalysisVersion{Version: original.Version}
+		} else if err != nil {
+			return err
+		}
+
+		version.Version = latest.Version + 1
+		if err := createVersion(ctx, tx, version); err != nil {
+			return err
+		}
+
+		detail.Version = version.Version
+		detailDal := new(model.Analysis)
+		if err := detailDal.FromEntity(detail); err != nil {
+			return err
+		}
+		return db.WithContext(ctx).Where(db.ID.Eq(detail.ID)).Save(detailDal)
+	})
+}
+
+func (ar *AnalysisRepo) GetVersions(ctx context.Context, reportId int) ([]*analysis.AnalysisVersion, error) {
+	db := ar.db.AnalysisVersion
+
+	versions, err := db.WithContext(ctx).Where(db.ReportId.Eq(reportId)).Order(db.Version).Find()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*analysis.AnalysisVersion, 0, len(versions))
+	for _, v := range versions {
+		av, err := v.ToEntity()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, av)
+	}
+	return ret, nil
+}
@@ -0,0 +1,410 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package base
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newAnalysisCollectionItem(db *gorm.DB, opts ...gen.DOOption) analysisCollectionItem {
+	_analysisCollectionItem := analysisCollectionItem{}
+
+	_analysisCollectionItem.analysisCollectionItemDo.UseDB(db, opts...)
+	_analysisCollectionItem.analysisCollectionItemDo.UseModel(&model.AnalysisCollectionItem{})
+
+	tableName := _analysisCollectionItem.analysisCollectionItemDo.TableName()
+	_analysisCollectionItem.ALL = field.NewAsterisk(tableName)
+	_analysisCollectionItem.ID = field.NewInt(tableName, "id")
+	_analysisCollectionItem.CollectionId = field.NewInt(tableName, "collection_id")
+	_analysisCollectionItem.ReportId = field.NewInt(tableName, "report_id")
+	_analysisCollectionItem.UserId = field.NewInt(tableName, "user_id")
+	_analysisCollectionItem.CreatedAt = field.NewTime(tableName, "created_at")
+
+	_analysisCollectionItem.fillFieldMap()
+
+	return _analysisCollectionItem
+}
+
+type analysisCollectionItem struct {
+	analysisCollectionItemDo analysisCollectionItemDo
+
+	ALL          field.Asterisk
+	ID           field.Int
+	CollectionId field.Int
+	ReportId     field.Int
+	UserId       field.Int
+	CreatedAt    field.Time // 加入收藏夹的时间
+
+	fieldMap map[string]field.Expr
+}
+
+func (a analysisCollectionItem) Table(newTableName string) *analysisCollectionItem {
+	a.analysisCollectionItemDo.UseTable(newTableName)
+	return a.updateTableName(newTableName)
+}
+
+func (a analysisCollectionItem) As(alias string) *analysisCollectionItem {
+	a.analysisCollectionItemDo.DO = *(a.analysisCollectionItemDo.As(alias).(*gen.DO))
+	return a.updateTableName(alias)
+}
+
+func (a *analysisCollectionItem) updateTableName(table string) *analysisCollectionItem {
+	a.ALL = field.NewAsterisk(table)
+	a.ID = field.NewInt(table, "id")
+	a.CollectionId = field.NewInt(table, "collection_id")
+	a.ReportId = field.NewInt(table, "report_id")
+	a.UserId = field.NewInt(table, "user_id")
+	a.CreatedAt = field.NewTime(table, "created_at")
+
+	a.fillFieldMap()
+
+	return a
+}
+
+func (a *analysisCollectionItem) WithContext(ctx context.Context) IAnalysisCollectionItemDo {
+	return a.analysisCollectionItemDo.WithContext(ctx)
+}
+
+func (a analysisCollectionItem) TableName() string { return a.analysisCollectionItemDo.TableName() }
+
+func (a analysisCollectionItem) Alias() string { return a.analysisCollectionItemDo.Alias() }
+
+func (a analysisCollectionItem) Columns(cols ...field.Expr) gen.Columns {
+	return a.analysisCollectionItemDo.Columns(cols...)
+}
+
+func (a *analysisCollectionItem) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := a.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (a *analysisCollectionItem) fillFieldMap() {
+	a.fieldMap = make(map[string]field.Expr, 5)
+	a.fieldMap["id"] = a.ID
+	a.fieldMap["collection_id"] = a.CollectionId
+	a.fieldMap["report_id"] = a.ReportId
+	a.fieldMap["user_id"] = a.UserId
+	a.fieldMap["created_at"] = a.CreatedAt
+}
+
+func (a analysisCollectionItem) clone(db *gorm.DB) analysisCollectionItem {
+	a.analysisCollectionItemDo.ReplaceConnPool(db.Statement.ConnPool)
+	return a
+}
+
+func (a analysisCollectionItem) replaceDB(db *gorm.DB) analysisCollectionItem {
+	a.analysisCollectionItemDo.ReplaceDB(db)
+	return a
+}
+
+type analysisCollectionItemDo struct{ gen.DO }
+
+type IAnalysisCollectionItemDo interface {
+	gen.SubQuery
+	Debug() IAnalysisCollectionItemDo
+	WithContext(ctx context.Context) IAnalysisCollectionItemDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IAnalysisCollectionItemDo
+	WriteDB() IAnalysisCollectionItemDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IAnalysisCollectionItemDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IAnalysisCollectionItemDo
+	Not(conds ...gen.Condition) IAnalysisCollectionItemDo
+	Or(conds ...gen.Condition) IAnalysisCollectionItemDo
+	Select(conds ...field.Expr) IAnalysisCollectionItemDo
+	Where(conds ...gen.Condition) IAnalysisCollectionItemDo
+	Order(conds ...field.Expr) IAnalysisCollectionItemDo
+	Distinct(cols ...field.Expr) IAnalysisCollectionItemDo
+	Omit(cols ...field.Expr) IAnalysisCollectionItemDo
+	Join(table schema.Tabler, on ...field.Expr) IAnalysisCollectionItemDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IAnalysisCollectionItemDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IAnalysisCollectionItemDo
+	Group(cols ...field.Expr) IAnalysisCollectionItemDo
+	Having(conds ...gen.Condition) IAnalysisCollectionItemDo
+	Limit(limit int) IAnalysisCollectionItemDo
+	Offset(offset int) IAnalysisCollectionItemDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IAnalysisCollectionItemDo
+	Unscoped() IAnalysisCollectionItemDo
+	Create(values ...*model.AnalysisCollectionItem) error
+	CreateInBatches(values []*model.AnalysisCollectionItem, batchSize int) error
+	Save(values ...*model.AnalysisCollectionItem) error
+	First() (*model.AnalysisCollectionItem, error)
+	Take() (*model.AnalysisCollectionItem, error)
+	Last() (*model.AnalysisCollectionItem, error)
+	Find() ([]*model.AnalysisCollectionItem, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AnalysisCollectionItem, err error)
+	FindInBatches(result *[]*model.AnalysisCollectionItem, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.AnalysisCollectionItem) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IAnalysisCollectionItemDo
+	Assign(attrs ...field.AssignExpr) IAnalysisCollectionItemDo
+	Joins(fields ...field.RelationField) IAnalysisCollectionItemDo
+	Preload(fields ...field.RelationField) IAnalysisCollectionItemDo
+	FirstOrInit() (*model.AnalysisCollectionItem, error)
+	FirstOrCreate() (*model.AnalysisCollectionItem, error)
+	FindByPage(offset int, limit int) (result []*model.AnalysisCollectionItem, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IAnalysisCollectionItemDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (a analysisCollectionItemDo) Debug() IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Debug())
+}
+
+func (a analysisCollectionItemDo) WithContext(ctx context.Context) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.WithContext(ctx))
+}
+
+func (a analysisCollectionItemDo) ReadDB() IAnalysisCollectionItemDo {
+	return a.Clauses(dbresolver.Read)
+}
+
+func (a analysisCollectionItemDo) WriteDB() IAnalysisCollectionItemDo {
+	return a.Clauses(dbresolver.Write)
+}
+
+func (a analysisCollectionItemDo) Session(config *gorm.Session) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Session(config))
+}
+
+func (a analysisCollectionItemDo) Clauses(conds ...clause.Expression) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Clauses(conds...))
+}
+
+func (a analysisCollectionItemDo) Returning(value interface{}, columns ...string) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Returning(value, columns...))
+}
+
+func (a analysisCollectionItemDo) Not(conds ...gen.Condition) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Not(conds...))
+}
+
+func (a analysisCollectionItemDo) Or(conds ...gen.Condition) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Or(conds...))
+}
+
+func (a analysisCollectionItemDo) Select(conds ...field.Expr) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Select(conds...))
+}
+
+func (a analysisCollectionItemDo) Where(conds ...gen.Condition) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Where(conds...))
+}
+
+func (a analysisCollectionItemDo) Order(conds ...field.Expr) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Order(conds...))
+}
+
+func (a analysisCollectionItemDo) Distinct(cols ...field.Expr) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Distinct(cols...))
+}
+
+func (a analysisCollectionItemDo) Omit(cols ...field.Expr) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Omit(cols...))
+}
+
+func (a analysisCollectionItemDo) Join(table schema.Tabler, on ...field.Expr) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Join(table, on...))
+}
+
+func (a analysisCollectionItemDo) LeftJoin(table schema.Tabler, on ...field.Expr) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.LeftJoin(table, on...))
+}
+
+func (a analysisCollectionItemDo) RightJoin(table schema.Tabler, on ...field.Expr) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.RightJoin(table, on...))
+}
+
+func (a analysisCollectionItemDo) Group(cols ...field.Expr) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Group(cols...))
+}
+
+func (a analysisCollectionItemDo) Having(conds ...gen.Condition) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Having(conds...))
+}
+
+func (a analysisCollectionItemDo) Limit(limit int) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Limit(limit))
+}
+
+func (a analysisCollectionItemDo) Offset(offset int) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Offset(offset))
+}
+
+func (a analysisCollectionItemDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Scopes(funcs...))
+}
+
+func (a analysisCollectionItemDo) Unscoped() IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Unscoped())
+}
+
+func (a analysisCollectionItemDo) Create(values ...*model.AnalysisCollectionItem) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Create(values)
+}
+
+func (a analysisCollectionItemDo) CreateInBatches(values []*model.AnalysisCollectionItem, batchSize int) error {
+	return a.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (a analysisCollectionItemDo) Save(values ...*model.AnalysisCollectionItem) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Save(values)
+}
+
+func (a analysisCollectionItemDo) First() (*model.AnalysisCollectionItem, error) {
+	if result, err := a.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisCollectionItem), nil
+	}
+}
+
+func (a analysisCollectionItemDo) Take() (*model.AnalysisCollectionItem, error) {
+	if result, err := a.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisCollectionItem), nil
+	}
+}
+
+func (a analysisCollectionItemDo) Last() (*model.AnalysisCollectionItem, error) {
+	if result, err := a.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisCollectionItem), nil
+	}
+}
+
+func (a analysisCollectionItemDo) Find() ([]*model.AnalysisCollectionItem, error) {
+	result, err := a.DO.Find()
+	return result.([]*model.AnalysisCollectionItem), err
+}
+
+func (a analysisCollectionItemDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AnalysisCollectionItem, err error) {
+	buf := make([]*model.AnalysisCollectionItem, 0, batchSize)
+	err = a.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (a analysisCollectionItemDo) FindInBatches(result *[]*model.AnalysisCollectionItem, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return a.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (a analysisCollectionItemDo) Attrs(attrs ...field.AssignExpr) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Attrs(attrs...))
+}
+
+func (a analysisCollectionItemDo) Assign(attrs ...field.AssignExpr) IAnalysisCollectionItemDo {
+	return a.withDO(a.DO.Assign(attrs...))
+}
+
+func (a analysisCollectionItemDo) Joins(fields ...field.RelationField) IAnalysisCollectionItemDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Joins(_f))
+	}
+	return &a
+}
+
+func (a analysisCollectionItemDo) Preload(fields ...field.RelationField) IAnalysisCollectionItemDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Preload(_f))
+	}
+	return &a
+}
+
+func (a analysisCollectionItemDo) FirstOrInit() (*model.AnalysisCollectionItem, error) {
+	if result, err := a.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisCollectionItem), nil
+	}
+}
+
+func (a analysisCollectionItemDo) FirstOrCreate() (*model.AnalysisCollectionItem, error) {
+	if result, err := a.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisCollectionItem), nil
+	}
+}
+
+func (a analysisCollectionItemDo) FindByPage(offset int, limit int) (result []*model.AnalysisCollectionItem, count int64, err error) {
+	result, err = a.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = a.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (a analysisCollectionItemDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = a.Count()
+	if err != nil {
+		return
+	}
+
+	err = a.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (a analysisCollectionItemDo) Scan(result interface{}) (err error) {
+	return a.DO.Scan(result)
+}
+
+func (a analysisCollectionItemDo) Delete(models ...*model.AnalysisCollectionItem) (result gen.ResultInfo, err error) {
+	return a.DO.Delete(models)
+}
+
+func (a *analysisCollectionItemDo) withDO(do gen.Dao) *analysisCollectionItemDo {
+	a.DO = *do.(*gen.DO)
+	return a
+}
@@ -0,0 +1,422 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package base
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newAnalysisCollection(db *gorm.DB, opts ...gen.DOOption) analysisCollection {
+	_analysisCollection := analysisCollection{}
+
+	_analysisCollection.analysisCollectionDo.UseDB(db, opts...)
+	_analysisCollection.analysisCollectionDo.UseModel(&model.AnalysisCollection{})
+
+	tableName := _analysisCollection.analysisCollectionDo.TableName()
+	_analysisCollection.ALL = field.NewAsterisk(tableName)
+	_analysisCollection.ID = field.NewInt(tableName, "id")
+	_analysisCollection.UserId = field.NewInt(tableName, "user_id")
+	_analysisCollection.Name = field.NewString(tableName, "name")
+	_analysisCollection.IsDefault = field.NewBool(tableName, "is_default")
+	_analysisCollection.CoverReportId = field.NewInt(tableName, "cover_report_id")
+	_analysisCollection.Position = field.NewInt(tableName, "position")
+	_analysisCollection.CreatedAt = field.NewTime(tableName, "created_at")
+	_analysisCollection.UpdatedAt = field.NewTime(tableName, "updated_at")
+
+	_analysisCollection.fillFieldMap()
+
+	return _analysisCollection
+}
+
+type analysisCollection struct {
+	analysisCollectionDo analysisCollectionDo
+
+	ALL           field.Asterisk
+	ID            field.Int
+	UserId        field.Int
+	Name          field.String
+	IsDefault     field.Bool
+	CoverReportId field.Int
+	Position      field.Int
+	CreatedAt     field.Time // 创建时间
+	UpdatedAt     field.Time // 更新时间
+
+	fieldMap map[string]field.Expr
+}
+
+func (a analysisCollection) Table(newTableName string) *analysisCollection {
+	a.analysisCollectionDo.UseTable(newTableName)
+	return a.updateTableName(newTableName)
+}
+
+func (a analysisCollection) As(alias string) *analysisCollection {
+	a.analysisCollectionDo.DO = *(a.analysisCollectionDo.As(alias).(*gen.DO))
+	return a.updateTableName(alias)
+}
+
+func (a *analysisCollection) updateTableName(table string) *analysisCollection {
+	a.ALL = field.NewAsterisk(table)
+	a.ID = field.NewInt(table, "id")
+	a.UserId = field.NewInt(table, "user_id")
+	a.Name = field.NewString(table, "name")
+	a.IsDefault = field.NewBool(table, "is_default")
+	a.CoverReportId = field.NewInt(table, "cover_report_id")
+	a.Position = field.NewInt(table, "position")
+	a.CreatedAt = field.NewTime(table, "created_at")
+	a.UpdatedAt = field.NewTime(table, "updated_at")
+
+	a.fillFieldMap()
+
+	return a
+}
+
+func (a *analysisCollection) WithContext(ctx context.Context) IAnalysisCollectionDo {
+	return a.analysisCollectionDo.WithContext(ctx)
+}
+
+func (a analysisCollection) TableName() string { return a.analysisCollectionDo.TableName() }
+
+func (a analysisCollection) Alias() string { return a.analysisCollectionDo.Alias() }
+
+func (a analysisCollection) Columns(cols ...field.Expr) gen.Columns {
+	return a.analysisCollectionDo.Columns(cols...)
+}
+
+func (a *analysisCollection) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := a.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (a *analysisCollection) fillFieldMap() {
+	a.fieldMap = make(map[string]field.Expr, 8)
+	a.fieldMap["id"] = a.ID
+	a.fieldMap["user_id"] = a.UserId
+	a.fieldMap["name"] = a.Name
+	a.fieldMap["is_default"] = a.IsDefault
+	a.fieldMap["cover_report_id"] = a.CoverReportId
+	a.fieldMap["position"] = a.Position
+	a.fieldMap["created_at"] = a.CreatedAt
+	a.fieldMap["updated_at"] = a.UpdatedAt
+}
+
+func (a analysisCollection) clone(db *gorm.DB) analysisCollection {
+	a.analysisCollectionDo.ReplaceConnPool(db.Statement.ConnPool)
+	return a
+}
+
+func (a analysisCollection) replaceDB(db *gorm.DB) analysisCollection {
+	a.analysisCollectionDo.ReplaceDB(db)
+	return a
+}
+
+type analysisCollectionDo struct{ gen.DO }
+
+type IAnalysisCollectionDo interface {
+	gen.SubQuery
+	Debug() IAnalysisCollectionDo
+	WithContext(ctx context.Context) IAnalysisCollectionDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IAnalysisCollectionDo
+	WriteDB() IAnalysisCollectionDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IAnalysisCollectionDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IAnalysisCollectionDo
+	Not(conds ...gen.Condition) IAnalysisCollectionDo
+	Or(conds ...gen.Condition) IAnalysisCollectionDo
+	Select(conds ...field.Expr) IAnalysisCollectionDo
+	Where(conds ...gen.Condition) IAnalysisCollectionDo
+	Order(conds ...field.Expr) IAnalysisCollectionDo
+	Distinct(cols ...field.Expr) IAnalysisCollectionDo
+	Omit(cols ...field.Expr) IAnalysisCollectionDo
+	Join(table schema.Tabler, on ...field.Expr) IAnalysisCollectionDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IAnalysisCollectionDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IAnalysisCollectionDo
+	Group(cols ...field.Expr) IAnalysisCollectionDo
+	Having(conds ...gen.Condition) IAnalysisCollectionDo
+	Limit(limit int) IAnalysisCollectionDo
+	Offset(offset int) IAnalysisCollectionDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IAnalysisCollectionDo
+	Unscoped() IAnalysisCollectionDo
+	Create(values ...*model.AnalysisCollection) error
+	CreateInBatches(values []*model.AnalysisCollection, batchSize int) error
+	Save(values ...*model.AnalysisCollection) error
+	First() (*model.AnalysisCollection, error)
+	Take() (*model.AnalysisCollection, error)
+	Last() (*model.AnalysisCollection, error)
+	Find() ([]*model.AnalysisCollection, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AnalysisCollection, err error)
+	FindInBatches(result *[]*model.AnalysisCollection, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.AnalysisCollection) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IAnalysisCollectionDo
+	Assign(attrs ...field.AssignExpr) IAnalysisCollectionDo
+	Joins(fields ...field.RelationField) IAnalysisCollectionDo
+	Preload(fields ...field.RelationField) IAnalysisCollectionDo
+	FirstOrInit() (*model.AnalysisCollection, error)
+	FirstOrCreate() (*model.AnalysisCollection, error)
+	FindByPage(offset int, limit int) (result []*model.AnalysisCollection, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IAnalysisCollectionDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (a analysisCollectionDo) Debug() IAnalysisCollectionDo {
+	return a.withDO(a.DO.Debug())
+}
+
+func (a analysisCollectionDo) WithContext(ctx context.Context) IAnalysisCollectionDo {
+	return a.withDO(a.DO.WithContext(ctx))
+}
+
+func (a analysisCollectionDo) ReadDB() IAnalysisCollectionDo {
+	return a.Clauses(dbresolver.Read)
+}
+
+func (a analysisCollectionDo) WriteDB() IAnalysisCollectionDo {
+	return a.Clauses(dbresolver.Write)
+}
+
+func (a analysisCollectionDo) Session(config *gorm.Session) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Session(config))
+}
+
+func (a analysisCollectionDo) Clauses(conds ...clause.Expression) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Clauses(conds...))
+}
+
+func (a analysisCollectionDo) Returning(value interface{}, columns ...string) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Returning(value, columns...))
+}
+
+func (a analysisCollectionDo) Not(conds ...gen.Condition) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Not(conds...))
+}
+
+func (a analysisCollectionDo) Or(conds ...gen.Condition) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Or(conds...))
+}
+
+func (a analysisCollectionDo) Select(conds ...field.Expr) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Select(conds...))
+}
+
+func (a analysisCollectionDo) Where(conds ...gen.Condition) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Where(conds...))
+}
+
+func (a analysisCollectionDo) Order(conds ...field.Expr) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Order(conds...))
+}
+
+func (a analysisCollectionDo) Distinct(cols ...field.Expr) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Distinct(cols...))
+}
+
+func (a analysisCollectionDo) Omit(cols ...field.Expr) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Omit(cols...))
+}
+
+func (a analysisCollectionDo) Join(table schema.Tabler, on ...field.Expr) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Join(table, on...))
+}
+
+func (a analysisCollectionDo) LeftJoin(table schema.Tabler, on ...field.Expr) IAnalysisCollectionDo {
+	return a.withDO(a.DO.LeftJoin(table, on...))
+}
+
+func (a analysisCollectionDo) RightJoin(table schema.Tabler, on ...field.Expr) IAnalysisCollectionDo {
+	return a.withDO(a.DO.RightJoin(table, on...))
+}
+
+func (a analysisCollectionDo) Group(cols ...field.Expr) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Group(cols...))
+}
+
+func (a analysisCollectionDo) Having(conds ...gen.Condition) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Having(conds...))
+}
+
+func (a analysisCollectionDo) Limit(limit int) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Limit(limit))
+}
+
+func (a analysisCollectionDo) Offset(offset int) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Offset(offset))
+}
+
+func (a analysisCollectionDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Scopes(funcs...))
+}
+
+func (a analysisCollectionDo) Unscoped() IAnalysisCollectionDo {
+	return a.withDO(a.DO.Unscoped())
+}
+
+func (a analysisCollectionDo) Create(values ...*model.AnalysisCollection) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Create(values)
+}
+
+func (a analysisCollectionDo) CreateInBatches(values []*model.AnalysisCollection, batchSize int) error {
+	return a.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (a analysisCollectionDo) Save(values ...*model.AnalysisCollection) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Save(values)
+}
+
+func (a analysisCollectionDo) First() (*model.AnalysisCollection, error) {
+	if result, err := a.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisCollection), nil
+	}
+}
+
+func (a analysisCollectionDo) Take() (*model.AnalysisCollection, error) {
+	if result, err := a.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisCollection), nil
+	}
+}
+
+func (a analysisCollectionDo) Last() (*model.AnalysisCollection, error) {
+	if result, err := a.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisCollection), nil
+	}
+}
+
+func (a analysisCollectionDo) Find() ([]*model.AnalysisCollection, error) {
+	result, err := a.DO.Find()
+	return result.([]*model.AnalysisCollection), err
+}
+
+func (a analysisCollectionDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AnalysisCollection, err error) {
+	buf := make([]*model.AnalysisCollection, 0, batchSize)
+	err = a.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (a analysisCollectionDo) FindInBatches(result *[]*model.AnalysisCollection, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return a.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (a analysisCollectionDo) Attrs(attrs ...field.AssignExpr) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Attrs(attrs...))
+}
+
+func (a analysisCollectionDo) Assign(attrs ...field.AssignExpr) IAnalysisCollectionDo {
+	return a.withDO(a.DO.Assign(attrs...))
+}
+
+func (a analysisCollectionDo) Joins(fields ...field.RelationField) IAnalysisCollectionDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Joins(_f))
+	}
+	return &a
+}
+
+func (a analysisCollectionDo) Preload(fields ...field.RelationField) IAnalysisCollectionDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Preload(_f))
+	}
+	return &a
+}
+
+func (a analysisCollectionDo) FirstOrInit() (*model.AnalysisCollection, error) {
+	if result, err := a.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisCollection), nil
+	}
+}
+
+func (a analysisCollectionDo) FirstOrCreate() (*model.AnalysisCollection, error) {
+	if result, err := a.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisCollection), nil
+	}
+}
+
+func (a analysisCollectionDo) FindByPage(offset int, limit int) (result []*model.AnalysisCollection, count int64, err error) {
+	result, err = a.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = a.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (a analysisCollectionDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = a.Count()
+	if err != nil {
+		return
+	}
+
+	err = a.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (a analysisCollectionDo) Scan(result interface{}) (err error) {
+	return a.DO.Scan(result)
+}
+
+func (a analysisCollectionDo) Delete(models ...*model.AnalysisCollection) (result gen.ResultInfo, err error) {
+	return a.DO.Delete(models)
+}
+
+func (a *analysisCollectionDo) withDO(do gen.Dao) *analysisCollectionDo {
+	a.DO = *do.(*gen.DO)
+	return a
+}
@@ -0,0 +1,446 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package base
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newAnalysisComparison(db *gorm.DB, opts ...gen.DOOption) analysisComparison {
+	_analysisComparison := analysisComparison{}
+
+	_analysisComparison.analysisComparisonDo.UseDB(db, opts...)
+	_analysisComparison.analysisComparisonDo.UseModel(&model.AnalysisComparison{})
+
+	tableName := _analysisComparison.analysisComparisonDo.TableName()
+	_analysisComparison.ALL = field.NewAsterisk(tableName)
+	_analysisComparison.ID = field.NewInt(tableName, "id")
+	_analysisComparison.UserId = field.NewInt(tableName, "user_id")
+	_analysisComparison.BeforeId = field.NewInt(tableName, "before_id")
+	_analysisComparison.AfterId = field.NewInt(tableName, "after_id")
+	_analysisComparison.BeforeScore = field.NewInt(tableName, "before_score")
+	_analysisComparison.AfterScore = field.NewInt(tableName, "after_score")
+	_analysisComparison.ScoreDelta = field.NewInt(tableName, "score_delta")
+	_analysisComparison.LabelDeltas = field.NewField(tableName, "label_deltas")
+	_analysisComparison.Narrative = field.NewString(tableName, "narrative")
+	_analysisComparison.IsFavorite = field.NewBool(tableName, "is_favorite")
+	_analysisComparison.IsShared = field.NewBool(tableName, "is_shared")
+	_analysisComparison.CreatedAt = field.NewTime(tableName, "created_at")
+	_analysisComparison.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_analysisComparison.DeletedAt = field.NewField(tableName, "deleted_at")
+
+	_analysisComparison.fillFieldMap()
+
+	return _analysisComparison
+}
+
+type analysisComparison struct {
+	analysisComparisonDo analysisComparisonDo
+
+	ALL         field.Asterisk
+	ID          field.Int
+	UserId      field.Int
+	BeforeId    field.Int
+	AfterId     field.Int
+	BeforeScore field.Int
+	AfterScore  field.Int
+	ScoreDelta  field.Int
+	LabelDeltas field.Field
+	Narrative   field.String
+	IsFavorite  field.Bool
+	IsShared    field.Bool
+	CreatedAt   field.Time  // 创建时间
+	UpdatedAt   field.Time  // 更新时间
+	DeletedAt   field.Field // 软删除时间
+
+	fieldMap map[string]field.Expr
+}
+
+func (a analysisComparison) Table(newTableName string) *analysisComparison {
+	a.analysisComparisonDo.UseTable(newTableName)
+	return a.updateTableName(newTableName)
+}
+
+func (a analysisComparison) As(alias string) *analysisComparison {
+	a.analysisComparisonDo.DO = *(a.analysisComparisonDo.As(alias).(*gen.DO))
+	return a.updateTableName(alias)
+}
+
+func (a *analysisComparison) updateTableName(table string) *analysisComparison {
+	a.ALL = field.NewAsterisk(table)
+	a.ID = field.NewInt(table, "id")
+	a.UserId = field.NewInt(table, "user_id")
+	a.BeforeId = field.NewInt(table, "before_id")
+	a.AfterId = field.NewInt(table, "after_id")
+	a.BeforeScore = field.NewInt(table, "before_score")
+	a.AfterScore = field.NewInt(table, "after_score")
+	a.ScoreDelta = field.NewInt(table, "score_delta")
+	a.LabelDeltas = field.NewField(table, "label_deltas")
+	a.Narrative = field.NewString(table, "narrative")
+	a.IsFavorite = field.NewBool(table, "is_favorite")
+	a.IsShared = field.NewBool(table, "is_shared")
+	a.CreatedAt = field.NewTime(table, "created_at")
+	a.UpdatedAt = field.NewTime(table, "updated_at")
+	a.DeletedAt = field.NewField(table, "deleted_at")
+
+	a.fillFieldMap()
+
+	return a
+}
+
+func (a *analysisComparison) WithContext(ctx context.Context) IAnalysisComparisonDo {
+	return a.analysisComparisonDo.WithContext(ctx)
+}
+
+func (a analysisComparison) TableName() string { return a.analysisComparisonDo.TableName() }
+
+func (a analysisComparison) Alias() string { return a.analysisComparisonDo.Alias() }
+
+func (a analysisComparison) Columns(cols ...field.Expr) gen.Columns {
+	return a.analysisComparisonDo.Columns(cols...)
+}
+
+func (a *analysisComparison) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := a.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (a *analysisComparison) fillFieldMap() {
+	a.fieldMap = make(map[string]field.Expr, 14)
+	a.fieldMap["id"] = a.ID
+	a.fieldMap["user_id"] = a.UserId
+	a.fieldMap["before_id"] = a.BeforeId
+	a.fieldMap["after_id"] = a.AfterId
+	a.fieldMap["before_score"] = a.BeforeScore
+	a.fieldMap["after_score"] = a.AfterScore
+	a.fieldMap["score_delta"] = a.ScoreDelta
+	a.fieldMap["label_deltas"] = a.LabelDeltas
+	a.fieldMap["narrative"] = a.Narrative
+	a.fieldMap["is_favorite"] = a.IsFavorite
+	a.fieldMap["is_shared"] = a.IsShared
+	a.fieldMap["created_at"] = a.CreatedAt
+	a.fieldMap["updated_at"] = a.UpdatedAt
+	a.fieldMap["deleted_at"] = a.DeletedAt
+}
+
+func (a analysisComparison) clone(db *gorm.DB) analysisComparison {
+	a.analysisComparisonDo.ReplaceConnPool(db.Statement.ConnPool)
+	return a
+}
+
+func (a analysisComparison) replaceDB(db *gorm.DB) analysisComparison {
+	a.analysisComparisonDo.ReplaceDB(db)
+	return a
+}
+
+type analysisComparisonDo struct{ gen.DO }
+
+type IAnalysisComparisonDo interface {
+	gen.SubQuery
+	Debug() IAnalysisComparisonDo
+	WithContext(ctx context.Context) IAnalysisComparisonDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IAnalysisComparisonDo
+	WriteDB() IAnalysisComparisonDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IAnalysisComparisonDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IAnalysisComparisonDo
+	Not(conds ...gen.Condition) IAnalysisComparisonDo
+	Or(conds ...gen.Condition) IAnalysisComparisonDo
+	Select(conds ...field.Expr) IAnalysisComparisonDo
+	Where(conds ...gen.Condition) IAnalysisComparisonDo
+	Order(conds ...field.Expr) IAnalysisComparisonDo
+	Distinct(cols ...field.Expr) IAnalysisComparisonDo
+	Omit(cols ...field.Expr) IAnalysisComparisonDo
+	Join(table schema.Tabler, on ...field.Expr) IAnalysisComparisonDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IAnalysisComparisonDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IAnalysisComparisonDo
+	Group(cols ...field.Expr) IAnalysisComparisonDo
+	Having(conds ...gen.Condition) IAnalysisComparisonDo
+	Limit(limit int) IAnalysisComparisonDo
+	Offset(offset int) IAnalysisComparisonDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IAnalysisComparisonDo
+	Unscoped() IAnalysisComparisonDo
+	Create(values ...*model.AnalysisComparison) error
+	CreateInBatches(values []*model.AnalysisComparison, batchSize int) error
+	Save(values ...*model.AnalysisComparison) error
+	First() (*model.AnalysisComparison, error)
+	Take() (*model.AnalysisComparison, error)
+	Last() (*model.AnalysisComparison, error)
+	Find() ([]*model.AnalysisComparison, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AnalysisComparison, err error)
+	FindInBatches(result *[]*model.AnalysisComparison, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.AnalysisComparison) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IAnalysisComparisonDo
+	Assign(attrs ...field.AssignExpr) IAnalysisComparisonDo
+	Joins(fields ...field.RelationField) IAnalysisComparisonDo
+	Preload(fields ...field.RelationField) IAnalysisComparisonDo
+	FirstOrInit() (*model.AnalysisComparison, error)
+	FirstOrCreate() (*model.AnalysisComparison, error)
+	FindByPage(offset int, limit int) (result []*model.AnalysisComparison, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IAnalysisComparisonDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (a analysisComparisonDo) Debug() IAnalysisComparisonDo {
+	return a.withDO(a.DO.Debug())
+}
+
+func (a analysisComparisonDo) WithContext(ctx context.Context) IAnalysisComparisonDo {
+	return a.withDO(a.DO.WithContext(ctx))
+}
+
+func (a analysisComparisonDo) ReadDB() IAnalysisComparisonDo {
+	return a.Clauses(dbresolver.Read)
+}
+
+func (a analysisComparisonDo) WriteDB() IAnalysisComparisonDo {
+	return a.Clauses(dbresolver.Write)
+}
+
+func (a analysisComparisonDo) Session(config *gorm.Session) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Session(config))
+}
+
+func (a analysisComparisonDo) Clauses(conds ...clause.Expression) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Clauses(conds...))
+}
+
+func (a analysisComparisonDo) Returning(value interface{}, columns ...string) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Returning(value, columns...))
+}
+
+func (a analysisComparisonDo) Not(conds ...gen.Condition) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Not(conds...))
+}
+
+func (a analysisComparisonDo) Or(conds ...gen.Condition) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Or(conds...))
+}
+
+func (a analysisComparisonDo) Select(conds ...field.Expr) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Select(conds...))
+}
+
+func (a analysisComparisonDo) Where(conds ...gen.Condition) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Where(conds...))
+}
+
+func (a analysisComparisonDo) Order(conds ...field.Expr) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Order(conds...))
+}
+
+func (a analysisComparisonDo) Distinct(cols ...field.Expr) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Distinct(cols...))
+}
+
+func (a analysisComparisonDo) Omit(cols ...field.Expr) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Omit(cols...))
+}
+
+func (a analysisComparisonDo) Join(table schema.Tabler, on ...field.Expr) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Join(table, on...))
+}
+
+func (a analysisComparisonDo) LeftJoin(table schema.Tabler, on ...field.Expr) IAnalysisComparisonDo {
+	return a.withDO(a.DO.LeftJoin(table, on...))
+}
+
+func (a analysisComparisonDo) RightJoin(table schema.Tabler, on ...field.Expr) IAnalysisComparisonDo {
+	return a.withDO(a.DO.RightJoin(table, on...))
+}
+
+func (a analysisComparisonDo) Group(cols ...field.Expr) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Group(cols...))
+}
+
+func (a analysisComparisonDo) Having(conds ...gen.Condition) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Having(conds...))
+}
+
+func (a analysisComparisonDo) Limit(limit int) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Limit(limit))
+}
+
+func (a analysisComparisonDo) Offset(offset int) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Offset(offset))
+}
+
+func (a analysisComparisonDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Scopes(funcs...))
+}
+
+func (a analysisComparisonDo) Unscoped() IAnalysisComparisonDo {
+	return a.withDO(a.DO.Unscoped())
+}
+
+func (a analysisComparisonDo) Create(values ...*model.AnalysisComparison) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Create(values)
+}
+
+func (a analysisComparisonDo) CreateInBatches(values []*model.AnalysisComparison, batchSize int) error {
+	return a.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (a analysisComparisonDo) Save(values ...*model.AnalysisComparison) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Save(values)
+}
+
+func (a analysisComparisonDo) First() (*model.AnalysisComparison, error) {
+	if result, err := a.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisComparison), nil
+	}
+}
+
+func (a analysisComparisonDo) Take() (*model.AnalysisComparison, error) {
+	if result, err := a.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisComparison), nil
+	}
+}
+
+func (a analysisComparisonDo) Last() (*model.AnalysisComparison, error) {
+	if result, err := a.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisComparison), nil
+	}
+}
+
+func (a analysisComparisonDo) Find() ([]*model.AnalysisComparison, error) {
+	result, err := a.DO.Find()
+	return result.([]*model.AnalysisComparison), err
+}
+
+func (a analysisComparisonDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AnalysisComparison, err error) {
+	buf := make([]*model.AnalysisComparison, 0, batchSize)
+	err = a.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (a analysisComparisonDo) FindInBatches(result *[]*model.AnalysisComparison, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return a.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (a analysisComparisonDo) Attrs(attrs ...field.AssignExpr) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Attrs(attrs...))
+}
+
+func (a analysisComparisonDo) Assign(attrs ...field.AssignExpr) IAnalysisComparisonDo {
+	return a.withDO(a.DO.Assign(attrs...))
+}
+
+func (a analysisComparisonDo) Joins(fields ...field.RelationField) IAnalysisComparisonDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Joins(_f))
+	}
+	return &a
+}
+
+func (a analysisComparisonDo) Preload(fields ...field.RelationField) IAnalysisComparisonDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Preload(_f))
+	}
+	return &a
+}
+
+func (a analysisComparisonDo) FirstOrInit() (*model.AnalysisComparison, error) {
+	if result, err := a.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisComparison), nil
+	}
+}
+
+func (a analysisComparisonDo) FirstOrCreate() (*model.AnalysisComparison, error) {
+	if result, err := a.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisComparison), nil
+	}
+}
+
+func (a analysisComparisonDo) FindByPage(offset int, limit int) (result []*model.AnalysisComparison, count int64, err error) {
+	result, err = a.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = a.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (a analysisComparisonDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = a.Count()
+	if err != nil {
+		return
+	}
+
+	err = a.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (a analysisComparisonDo) Scan(result interface{}) (err error) {
+	return a.DO.Scan(result)
+}
+
+func (a analysisComparisonDo) Delete(models ...*model.AnalysisComparison) (result gen.ResultInfo, err error) {
+	return a.DO.Delete(models)
+}
+
+func (a *analysisComparisonDo) withDO(do gen.Dao) *analysisComparisonDo {
+	a.DO = *do.(*gen.DO)
+	return a
+}
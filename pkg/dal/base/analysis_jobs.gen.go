@@ -0,0 +1,436 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package base
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newAnalysisJob(db *gorm.DB, opts ...gen.DOOption) analysisJob {
+	_analysisJob := analysisJob{}
+
+	_analysisJob.analysisJobDo.UseDB(db, opts...)
+	_analysisJob.analysisJobDo.UseModel(&model.AnalysisJob{})
+
+	tableName := _analysisJob.analysisJobDo.TableName()
+	_analysisJob.ALL = field.NewAsterisk(tableName)
+	_analysisJob.ID = field.NewInt(tableName, "id")
+	_analysisJob.UserId = field.NewInt(tableName, "user_id")
+	_analysisJob.ImageUrl = field.NewString(tableName, "image_url")
+	_analysisJob.Gender = field.NewInt(tableName, "gender")
+	_analysisJob.Status = field.NewString(tableName, "status")
+	_analysisJob.DetailId = field.NewInt(tableName, "detail_id")
+	_analysisJob.Error = field.NewString(tableName, "error")
+	_analysisJob.Message = field.NewString(tableName, "message")
+	_analysisJob.Attempts = field.NewInt(tableName, "attempts")
+	_analysisJob.CreatedAt = field.NewTime(tableName, "created_at")
+	_analysisJob.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_analysisJob.DeletedAt = field.NewField(tableName, "deleted_at")
+
+	_analysisJob.fillFieldMap()
+
+	return _analysisJob
+}
+
+type analysisJob struct {
+	analysisJobDo analysisJobDo
+
+	ALL       field.Asterisk
+	ID        field.Int
+	UserId    field.Int
+	ImageUrl  field.String
+	Gender    field.Int
+	Status    field.String
+	DetailId  field.Int
+	Error     field.String
+	Message   field.String
+	Attempts  field.Int
+	CreatedAt field.Time  // 创建时间
+	UpdatedAt field.Time  // 更新时间
+	DeletedAt field.Field // 软删除时间
+
+	fieldMap map[string]field.Expr
+}
+
+func (a analysisJob) Table(newTableName string) *analysisJob {
+	a.analysisJobDo.UseTable(newTableName)
+	return a.updateTableName(newTableName)
+}
+
+func (a analysisJob) As(alias string) *analysisJob {
+	a.analysisJobDo.DO = *(a.analysisJobDo.As(alias).(*gen.DO))
+	return a.updateTableName(alias)
+}
+
+func (a *analysisJob) updateTableName(table string) *analysisJob {
+	a.ALL = field.NewAsterisk(table)
+	a.ID = field.NewInt(table, "id")
+	a.UserId = field.NewInt(table, "user_id")
+	a.ImageUrl = field.NewString(table, "image_url")
+	a.Gender = field.NewInt(table, "gender")
+	a.Status = field.NewString(table, "status")
+	a.DetailId = field.NewInt(table, "detail_id")
+	a.Error = field.NewString(table, "error")
+	a.Message = field.NewString(table, "message")
+	a.Attempts = field.NewInt(table, "attempts")
+	a.CreatedAt = field.NewTime(table, "created_at")
+	a.UpdatedAt = field.NewTime(table, "updated_at")
+	a.DeletedAt = field.NewField(table, "deleted_at")
+
+	a.fillFieldMap()
+
+	return a
+}
+
+func (a *analysisJob) WithContext(ctx context.Context) IAnalysisJobDo {
+	return a.analysisJobDo.WithContext(ctx)
+}
+
+func (a analysisJob) TableName() string { return a.analysisJobDo.TableName() }
+
+func (a analysisJob) Alias() string { return a.analysisJobDo.Alias() }
+
+func (a analysisJob) Columns(cols ...field.Expr) gen.Columns { return a.analysisJobDo.Columns(cols...) }
+
+func (a *analysisJob) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := a.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (a *analysisJob) fillFieldMap() {
+	a.fieldMap = make(map[string]field.Expr, 12)
+	a.fieldMap["id"] = a.ID
+	a.fieldMap["user_id"] = a.UserId
+	a.fieldMap["image_url"] = a.ImageUrl
+	a.fieldMap["gender"] = a.Gender
+	a.fieldMap["status"] = a.Status
+	a.fieldMap["detail_id"] = a.DetailId
+	a.fieldMap["error"] = a.Error
+	a.fieldMap["message"] = a.Message
+	a.fieldMap["attempts"] = a.Attempts
+	a.fieldMap["created_at"] = a.CreatedAt
+	a.fieldMap["updated_at"] = a.UpdatedAt
+	a.fieldMap["deleted_at"] = a.DeletedAt
+}
+
+func (a analysisJob) clone(db *gorm.DB) analysisJob {
+	a.analysisJobDo.ReplaceConnPool(db.Statement.ConnPool)
+	return a
+}
+
+func (a analysisJob) replaceDB(db *gorm.DB) analysisJob {
+	a.analysisJobDo.ReplaceDB(db)
+	return a
+}
+
+type analysisJobDo struct{ gen.DO }
+
+type IAnalysisJobDo interface {
+	gen.SubQuery
+	Debug() IAnalysisJobDo
+	WithContext(ctx context.Context) IAnalysisJobDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IAnalysisJobDo
+	WriteDB() IAnalysisJobDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IAnalysisJobDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IAnalysisJobDo
+	Not(conds ...gen.Condition) IAnalysisJobDo
+	Or(conds ...gen.Condition) IAnalysisJobDo
+	Select(conds ...field.Expr) IAnalysisJobDo
+	Where(conds ...gen.Condition) IAnalysisJobDo
+	Order(conds ...field.Expr) IAnalysisJobDo
+	Distinct(cols ...field.Expr) IAnalysisJobDo
+	Omit(cols ...field.Expr) IAnalysisJobDo
+	Join(table schema.Tabler, on ...field.Expr) IAnalysisJobDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IAnalysisJobDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IAnalysisJobDo
+	Group(cols ...field.Expr) IAnalysisJobDo
+	Having(conds ...gen.Condition) IAnalysisJobDo
+	Limit(limit int) IAnalysisJobDo
+	Offset(offset int) IAnalysisJobDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IAnalysisJobDo
+	Unscoped() IAnalysisJobDo
+	Create(values ...*model.AnalysisJob) error
+	CreateInBatches(values []*model.AnalysisJob, batchSize int) error
+	Save(values ...*model.AnalysisJob) error
+	First() (*model.AnalysisJob, error)
+	Take() (*model.AnalysisJob, error)
+	Last() (*model.AnalysisJob, error)
+	Find() ([]*model.AnalysisJob, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AnalysisJob, err error)
+	FindInBatches(result *[]*model.AnalysisJob, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.AnalysisJob) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IAnalysisJobDo
+	Assign(attrs ...field.AssignExpr) IAnalysisJobDo
+	Joins(fields ...field.RelationField) IAnalysisJobDo
+	Preload(fields ...field.RelationField) IAnalysisJobDo
+	FirstOrInit() (*model.AnalysisJob, error)
+	FirstOrCreate() (*model.AnalysisJob, error)
+	FindByPage(offset int, limit int) (result []*model.AnalysisJob, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IAnalysisJobDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (a analysisJobDo) Debug() IAnalysisJobDo {
+	return a.withDO(a.DO.Debug())
+}
+
+func (a analysisJobDo) WithContext(ctx context.Context) IAnalysisJobDo {
+	return a.withDO(a.DO.WithContext(ctx))
+}
+
+func (a analysisJobDo) ReadDB() IAnalysisJobDo {
+	return a.Clauses(dbresolver.Read)
+}
+
+func (a analysisJobDo) WriteDB() IAnalysisJobDo {
+	return a.Clauses(dbresolver.Write)
+}
+
+func (a analysisJobDo) Session(config *gorm.Session) IAnalysisJobDo {
+	return a.withDO(a.DO.Session(config))
+}
+
+func (a analysisJobDo) Clauses(conds ...clause.Expression) IAnalysisJobDo {
+	return a.withDO(a.DO.Clauses(conds...))
+}
+
+func (a analysisJobDo) Returning(value interface{}, columns ...string) IAnalysisJobDo {
+	return a.withDO(a.DO.Returning(value, columns...))
+}
+
+func (a analysisJobDo) Not(conds ...gen.Condition) IAnalysisJobDo {
+	return a.withDO(a.DO.Not(conds...))
+}
+
+func (a analysisJobDo) Or(conds ...gen.Condition) IAnalysisJobDo {
+	return a.withDO(a.DO.Or(conds...))
+}
+
+func (a analysisJobDo) Select(conds ...field.Expr) IAnalysisJobDo {
+	return a.withDO(a.DO.Select(conds...))
+}
+
+func (a analysisJobDo) Where(conds ...gen.Condition) IAnalysisJobDo {
+	return a.withDO(a.DO.Where(conds...))
+}
+
+func (a analysisJobDo) Order(conds ...field.Expr) IAnalysisJobDo {
+	return a.withDO(a.DO.Order(conds...))
+}
+
+func (a analysisJobDo) Distinct(cols ...field.Expr) IAnalysisJobDo {
+	return a.withDO(a.DO.Distinct(cols...))
+}
+
+func (a analysisJobDo) Omit(cols ...field.Expr) IAnalysisJobDo {
+	return a.withDO(a.DO.Omit(cols...))
+}
+
+func (a analysisJobDo) Join(table schema.Tabler, on ...field.Expr) IAnalysisJobDo {
+	return a.withDO(a.DO.Join(table, on...))
+}
+
+func (a analysisJobDo) LeftJoin(table schema.Tabler, on ...field.Expr) IAnalysisJobDo {
+	return a.withDO(a.DO.LeftJoin(table, on...))
+}
+
+func (a analysisJobDo) RightJoin(table schema.Tabler, on ...field.Expr) IAnalysisJobDo {
+	return a.withDO(a.DO.RightJoin(table, on...))
+}
+
+func (a analysisJobDo) Group(cols ...field.Expr) IAnalysisJobDo {
+	return a.withDO(a.DO.Group(cols...))
+}
+
+func (a analysisJobDo) Having(conds ...gen.Condition) IAnalysisJobDo {
+	return a.withDO(a.DO.Having(conds...))
+}
+
+func (a analysisJobDo) Limit(limit int) IAnalysisJobDo {
+	return a.withDO(a.DO.Limit(limit))
+}
+
+func (a analysisJobDo) Offset(offset int) IAnalysisJobDo {
+	return a.withDO(a.DO.Offset(offset))
+}
+
+func (a analysisJobDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IAnalysisJobDo {
+	return a.withDO(a.DO.Scopes(funcs...))
+}
+
+func (a analysisJobDo) Unscoped() IAnalysisJobDo {
+	return a.withDO(a.DO.Unscoped())
+}
+
+func (a analysisJobDo) Create(values ...*model.AnalysisJob) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Create(values)
+}
+
+func (a analysisJobDo) CreateInBatches(values []*model.AnalysisJob, batchSize int) error {
+	return a.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (a analysisJobDo) Save(values ...*model.AnalysisJob) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Save(values)
+}
+
+func (a analysisJobDo) First() (*model.AnalysisJob, error) {
+	if result, err := a.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisJob), nil
+	}
+}
+
+func (a analysisJobDo) Take() (*model.AnalysisJob, error) {
+	if result, err := a.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisJob), nil
+	}
+}
+
+func (a analysisJobDo) Last() (*model.AnalysisJob, error) {
+	if result, err := a.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisJob), nil
+	}
+}
+
+func (a analysisJobDo) Find() ([]*model.AnalysisJob, error) {
+	result, err := a.DO.Find()
+	return result.([]*model.AnalysisJob), err
+}
+
+func (a analysisJobDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AnalysisJob, err error) {
+	buf := make([]*model.AnalysisJob, 0, batchSize)
+	err = a.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (a analysisJobDo) FindInBatches(result *[]*model.AnalysisJob, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return a.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (a analysisJobDo) Attrs(attrs ...field.AssignExpr) IAnalysisJobDo {
+	return a.withDO(a.DO.Attrs(attrs...))
+}
+
+func (a analysisJobDo) Assign(attrs ...field.AssignExpr) IAnalysisJobDo {
+	return a.withDO(a.DO.Assign(attrs...))
+}
+
+func (a analysisJobDo) Joins(fields ...field.RelationField) IAnalysisJobDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Joins(_f))
+	}
+	return &a
+}
+
+func (a analysisJobDo) Preload(fields ...field.RelationField) IAnalysisJobDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Preload(_f))
+	}
+	return &a
+}
+
+func (a analysisJobDo) FirstOrInit() (*model.AnalysisJob, error) {
+	if result, err := a.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisJob), nil
+	}
+}
+
+func (a analysisJobDo) FirstOrCreate() (*model.AnalysisJob, error) {
+	if result, err := a.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisJob), nil
+	}
+}
+
+func (a analysisJobDo) FindByPage(offset int, limit int) (result []*model.AnalysisJob, count int64, err error) {
+	result, err = a.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = a.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (a analysisJobDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = a.Count()
+	if err != nil {
+		return
+	}
+
+	err = a.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (a analysisJobDo) Scan(result interface{}) (err error) {
+	return a.DO.Scan(result)
+}
+
+func (a analysisJobDo) Delete(models ...*model.AnalysisJob) (result gen.ResultInfo, err error) {
+	return a.DO.Delete(models)
+}
+
+func (a *analysisJobDo) withDO(do gen.Dao) *analysisJobDo {
+	a.DO = *do.(*gen.DO)
+	return a
+}
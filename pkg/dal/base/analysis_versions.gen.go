@@ -0,0 +1,466 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package base
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newAnalysisVersion(db *gorm.DB, opts ...gen.DOOption) analysisVersion {
+	_analysisVersion := analysisVersion{}
+
+	_analysisVersion.analysisVersionDo.UseDB(db, opts...)
+	_analysisVersion.analysisVersionDo.UseModel(&model.AnalysisVersion{})
+
+	tableName := _analysisVersion.analysisVersionDo.TableName()
+	_analysisVersion.ALL = field.NewAsterisk(tableName)
+	_analysisVersion.ID = field.NewInt(tableName, "id")
+	_analysisVersion.ReportId = field.NewInt(tableName, "report_id")
+	_analysisVersion.Version = field.NewInt(tableName, "version")
+	_analysisVersion.Score = field.NewInt(tableName, "score")
+	_analysisVersion.Percentile = field.NewInt(tableName, "percentile")
+	_analysisVersion.Description = field.NewString(tableName, "description")
+	_analysisVersion.Tags = field.NewField(tableName, "tags")
+	_analysisVersion.ScoreDetails = field.NewField(tableName, "score_details")
+	_analysisVersion.AnalyisType = field.NewInt(tableName, "analyis_type")
+	_analysisVersion.AnalystName = field.NewString(tableName, "analyst_name")
+	_analysisVersion.IsFallback = field.NewBool(tableName, "is_fallback")
+	_analysisVersion.PromptVersion = field.NewString(tableName, "prompt_version")
+	_analysisVersion.Model = field.NewString(tableName, "model")
+	_analysisVersion.Experiment = field.NewString(tableName, "experiment")
+	_analysisVersion.Variant = field.NewString(tableName, "variant")
+	_analysisVersion.Contributions = field.NewField(tableName, "contributions")
+	_analysisVersion.CreatedAt = field.NewTime(tableName, "created_at")
+	_analysisVersion.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_analysisVersion.DeletedAt = field.NewField(tableName, "deleted_at")
+
+	_analysisVersion.fillFieldMap()
+
+	return _analysisVersion
+}
+
+type analysisVersion struct {
+	analysisVersionDo analysisVersionDo
+
+	ALL           field.Asterisk
+	ID            field.Int
+	ReportId      field.Int
+	Version       field.Int
+	Score         field.Int
+	Percentile    field.Int
+	Description   field.String
+	Tags          field.Field
+	ScoreDetails  field.Field
+	AnalyisType   field.Int
+	AnalystName   field.String
+	IsFallback    field.Bool
+	PromptVersion field.String
+	Model         field.String
+	Experiment    field.String
+	Variant       field.String
+	Contributions field.Field
+	CreatedAt     field.Time  // 创建时间
+	UpdatedAt     field.Time  // 更新时间
+	DeletedAt     field.Field // 软删除时间
+
+	fieldMap map[string]field.Expr
+}
+
+func (a analysisVersion) Table(newTableName string) *analysisVersion {
+	a.analysisVersionDo.UseTable(newTableName)
+	return a.updateTableName(newTableName)
+}
+
+func (a analysisVersion) As(alias string) *analysisVersion {
+	a.analysisVersionDo.DO = *(a.analysisVersionDo.As(alias).(*gen.DO))
+	return a.updateTableName(alias)
+}
+
+func (a *analysisVersion) updateTableName(table string) *analysisVersion {
+	a.ALL = field.NewAsterisk(table)
+	a.ID = field.NewInt(table, "id")
+	a.ReportId = field.NewInt(table, "report_id")
+	a.Version = field.NewInt(table, "version")
+	a.Score = field.NewInt(table, "score")
+	a.Percentile = field.NewInt(table, "percentile")
+	a.Description = field.NewString(table, "description")
+	a.Tags = field.NewField(table, "tags")
+	a.ScoreDetails = field.NewField(table, "score_details")
+	a.AnalyisType = field.NewInt(table, "analyis_type")
+	a.AnalystName = field.NewString(table, "analyst_name")
+	a.IsFallback = field.NewBool(table, "is_fallback")
+	a.PromptVersion = field.NewString(table, "prompt_version")
+	a.Model = field.NewString(table, "model")
+	a.Experiment = field.NewString(table, "experiment")
+	a.Variant = field.NewString(table, "variant")
+	a.Contributions = field.NewField(table, "contributions")
+	a.CreatedAt = field.NewTime(table, "created_at")
+	a.UpdatedAt = field.NewTime(table, "updated_at")
+	a.DeletedAt = field.NewField(table, "deleted_at")
+
+	a.fillFieldMap()
+
+	return a
+}
+
+func (a *analysisVersion) WithContext(ctx context.Context) IAnalysisVersionDo {
+	return a.analysisVersionDo.WithContext(ctx)
+}
+
+func (a analysisVersion) TableName() string { return a.analysisVersionDo.TableName() }
+
+func (a analysisVersion) Alias() string { return a.analysisVersionDo.Alias() }
+
+func (a analysisVersion) Columns(cols ...field.Expr) gen.Columns {
+	return a.analysisVersionDo.Columns(cols...)
+}
+
+func (a *analysisVersion) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := a.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (a *analysisVersion) fillFieldMap() {
+	a.fieldMap = make(map[string]field.Expr, 19)
+	a.fieldMap["id"] = a.ID
+	a.fieldMap["report_id"] = a.ReportId
+	a.fieldMap["version"] = a.Version
+	a.fieldMap["score"] = a.Score
+	a.fieldMap["percentile"] = a.Percentile
+	a.fieldMap["description"] = a.Description
+	a.fieldMap["tags"] = a.Tags
+	a.fieldMap["score_details"] = a.ScoreDetails
+	a.fieldMap["analyis_type"] = a.AnalyisType
+	a.fieldMap["analyst_name"] = a.AnalystName
+	a.fieldMap["is_fallback"] = a.IsFallback
+	a.fieldMap["prompt_version"] = a.PromptVersion
+	a.fieldMap["model"] = a.Model
+	a.fieldMap["experiment"] = a.Experiment
+	a.fieldMap["variant"] = a.Variant
+	a.fieldMap["contributions"] = a.Contributions
+	a.fieldMap["created_at"] = a.CreatedAt
+	a.fieldMap["updated_at"] = a.UpdatedAt
+	a.fieldMap["deleted_at"] = a.DeletedAt
+}
+
+func (a analysisVersion) clone(db *gorm.DB) analysisVersion {
+	a.analysisVersionDo.ReplaceConnPool(db.Statement.ConnPool)
+	return a
+}
+
+func (a analysisVersion) replaceDB(db *gorm.DB) analysisVersion {
+	a.analysisVersionDo.ReplaceDB(db)
+	return a
+}
+
+type analysisVersionDo struct{ gen.DO }
+
+type IAnalysisVersionDo interface {
+	gen.SubQuery
+	Debug() IAnalysisVersionDo
+	WithContext(ctx context.Context) IAnalysisVersionDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IAnalysisVersionDo
+	WriteDB() IAnalysisVersionDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IAnalysisVersionDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IAnalysisVersionDo
+	Not(conds ...gen.Condition) IAnalysisVersionDo
+	Or(conds ...gen.Condition) IAnalysisVersionDo
+	Select(conds ...field.Expr) IAnalysisVersionDo
+	Where(conds ...gen.Condition) IAnalysisVersionDo
+	Order(conds ...field.Expr) IAnalysisVersionDo
+	Distinct(cols ...field.Expr) IAnalysisVersionDo
+	Omit(cols ...field.Expr) IAnalysisVersionDo
+	Join(table schema.Tabler, on ...field.Expr) IAnalysisVersionDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IAnalysisVersionDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IAnalysisVersionDo
+	Group(cols ...field.Expr) IAnalysisVersionDo
+	Having(conds ...gen.Condition) IAnalysisVersionDo
+	Limit(limit int) IAnalysisVersionDo
+	Offset(offset int) IAnalysisVersionDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IAnalysisVersionDo
+	Unscoped() IAnalysisVersionDo
+	Create(values ...*model.AnalysisVersion) error
+	CreateInBatches(values []*model.AnalysisVersion, batchSize int) error
+	Save(values ...*model.AnalysisVersion) error
+	First() (*model.AnalysisVersion, error)
+	Take() (*model.AnalysisVersion, error)
+	Last() (*model.AnalysisVersion, error)
+	Find() ([]*model.AnalysisVersion, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AnalysisVersion, err error)
+	FindInBatches(result *[]*model.AnalysisVersion, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.AnalysisVersion) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IAnalysisVersionDo
+	Assign(attrs ...field.AssignExpr) IAnalysisVersionDo
+	Joins(fields ...field.RelationField) IAnalysisVersionDo
+	Preload(fields ...field.RelationField) IAnalysisVersionDo
+	FirstOrInit() (*model.AnalysisVersion, error)
+	FirstOrCreate() (*model.AnalysisVersion, error)
+	FindByPage(offset int, limit int) (result []*model.AnalysisVersion, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IAnalysisVersionDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (a analysisVersionDo) Debug() IAnalysisVersionDo {
+	return a.withDO(a.DO.Debug())
+}
+
+func (a analysisVersionDo) WithContext(ctx context.Context) IAnalysisVersionDo {
+	return a.withDO(a.DO.WithContext(ctx))
+}
+
+func (a analysisVersionDo) ReadDB() IAnalysisVersionDo {
+	return a.Clauses(dbresolver.Read)
+}
+
+func (a analysisVersionDo) WriteDB() IAnalysisVersionDo {
+	return a.Clauses(dbresolver.Write)
+}
+
+func (a analysisVersionDo) Session(config *gorm.Session) IAnalysisVersionDo {
+	return a.withDO(a.DO.Session(config))
+}
+
+func (a analysisVersionDo) Clauses(conds ...clause.Expression) IAnalysisVersionDo {
+	return a.withDO(a.DO.Clauses(conds...))
+}
+
+func (a analysisVersionDo) Returning(value interface{}, columns ...string) IAnalysisVersionDo {
+	return a.withDO(a.DO.Returning(value, columns...))
+}
+
+func (a analysisVersionDo) Not(conds ...gen.Condition) IAnalysisVersionDo {
+	return a.withDO(a.DO.Not(conds...))
+}
+
+func (a analysisVersionDo) Or(conds ...gen.Condition) IAnalysisVersionDo {
+	return a.withDO(a.DO.Or(conds...))
+}
+
+func (a analysisVersionDo) Select(conds ...field.Expr) IAnalysisVersionDo {
+	return a.withDO(a.DO.Select(conds...))
+}
+
+func (a analysisVersionDo) Where(conds ...gen.Condition) IAnalysisVersionDo {
+	return a.withDO(a.DO.Where(conds...))
+}
+
+func (a analysisVersionDo) Order(conds ...field.Expr) IAnalysisVersionDo {
+	return a.withDO(a.DO.Order(conds...))
+}
+
+func (a analysisVersionDo) Distinct(cols ...field.Expr) IAnalysisVersionDo {
+	return a.withDO(a.DO.Distinct(cols...))
+}
+
+func (a analysisVersionDo) Omit(cols ...field.Expr) IAnalysisVersionDo {
+	return a.withDO(a.DO.Omit(cols...))
+}
+
+func (a analysisVersionDo) Join(table schema.Tabler, on ...field.Expr) IAnalysisVersionDo {
+	return a.withDO(a.DO.Join(table, on...))
+}
+
+func (a analysisVersionDo) LeftJoin(table schema.Tabler, on ...field.Expr) IAnalysisVersionDo {
+	return a.withDO(a.DO.LeftJoin(table, on...))
+}
+
+func (a analysisVersionDo) RightJoin(table schema.Tabler, on ...field.Expr) IAnalysisVersionDo {
+	return a.withDO(a.DO.RightJoin(table, on...))
+}
+
+func (a analysisVersionDo) Group(cols ...field.Expr) IAnalysisVersionDo {
+	return a.withDO(a.DO.Group(cols...))
+}
+
+func (a analysisVersionDo) Having(conds ...gen.Condition) IAnalysisVersionDo {
+	return a.withDO(a.DO.Having(conds...))
+}
+
+func (a analysisVersionDo) Limit(limit int) IAnalysisVersionDo {
+	return a.withDO(a.DO.Limit(limit))
+}
+
+func (a analysisVersionDo) Offset(offset int) IAnalysisVersionDo {
+	return a.withDO(a.DO.Offset(offset))
+}
+
+func (a analysisVersionDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IAnalysisVersionDo {
+	return a.withDO(a.DO.Scopes(funcs...))
+}
+
+func (a analysisVersionDo) Unscoped() IAnalysisVersionDo {
+	return a.withDO(a.DO.Unscoped())
+}
+
+func (a analysisVersionDo) Create(values ...*model.AnalysisVersion) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Create(values)
+}
+
+func (a analysisVersionDo) CreateInBatches(values []*model.AnalysisVersion, batchSize int) error {
+	return a.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (a analysisVersionDo) Save(values ...*model.AnalysisVersion) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Save(values)
+}
+
+func (a analysisVersionDo) First() (*model.AnalysisVersion, error) {
+	if result, err := a.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisVersion), nil
+	}
+}
+
+func (a analysisVersionDo) Take() (*model.AnalysisVersion, error) {
+	if result, err := a.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisVersion), nil
+	}
+}
+
+func (a analysisVersionDo) Last() (*model.AnalysisVersion, error) {
+	if result, err := a.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisVersion), nil
+	}
+}
+
+func (a analysisVersionDo) Find() ([]*model.AnalysisVersion, error) {
+	result, err := a.DO.Find()
+	return result.([]*model.AnalysisVersion), err
+}
+
+func (a analysisVersionDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AnalysisVersion, err error) {
+	buf := make([]*model.AnalysisVersion, 0, batchSize)
+	err = a.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (a analysisVersionDo) FindInBatches(result *[]*model.AnalysisVersion, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return a.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (a analysisVersionDo) Attrs(attrs ...field.AssignExpr) IAnalysisVersionDo {
+	return a.withDO(a.DO.Attrs(attrs...))
+}
+
+func (a analysisVersionDo) Assign(attrs ...field.AssignExpr) IAnalysisVersionDo {
+	return a.withDO(a.DO.Assign(attrs...))
+}
+
+func (a analysisVersionDo) Joins(fields ...field.RelationField) IAnalysisVersionDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Joins(_f))
+	}
+	return &a
+}
+
+func (a analysisVersionDo) Preload(fields ...field.RelationField) IAnalysisVersionDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Preload(_f))
+	}
+	return &a
+}
+
+func (a analysisVersionDo) FirstOrInit() (*model.AnalysisVersion, error) {
+	if result, err := a.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisVersion), nil
+	}
+}
+
+func (a analysisVersionDo) FirstOrCreate() (*model.AnalysisVersion, error) {
+	if result, err := a.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AnalysisVersion), nil
+	}
+}
+
+func (a analysisVersionDo) FindByPage(offset int, limit int) (result []*model.AnalysisVersion, count int64, err error) {
+	result, err = a.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = a.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (a analysisVersionDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = a.Count()
+	if err != nil {
+		return
+	}
+
+	err = a.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (a analysisVersionDo) Scan(result interface{}) (err error) {
+	return a.DO.Scan(result)
+}
+
+func (a analysisVersionDo) Delete(models ...*model.AnalysisVersion) (result gen.ResultInfo, err error) {
+	return a.DO.Delete(models)
+}
+
+func (a *analysisVersionDo) withDO(do gen.Dao) *analysisVersionDo {
+	a.DO = *do.(*gen.DO)
+	return a
+}
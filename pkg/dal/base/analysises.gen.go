@@ -6,6 +6,7 @@ package base
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
 	"gorm.io/gorm"
@@ -33,7 +34,23 @@ func newAnalysis(db *gorm.DB, opts ...gen.DOOption) analysis {
 	_analysis.Description = field.NewString(tableName, "description")
 	_analysis.Tags = field.NewField(tableName, "tags")
 	_analysis.ScoreDetails = field.NewField(tableName, "score_details")
-	_analysis.IsFavorite = field.NewBool(tableName, "is_favorite")
+	_analysis.AnalyisType = field.NewInt(tableName, "analyis_type")
+	_analysis.Gender = field.NewInt(tableName, "gender")
+	_analysis.Percentile = field.NewInt(tableName, "percentile")
+	_analysis.AnalystName = field.NewString(tableName, "analyst_name")
+	_analysis.IsFallback = field.NewBool(tableName, "is_fallback")
+	_analysis.ImageHash = field.NewString(tableName, "image_hash")
+	_analysis.PromptVersion = field.NewString(tableName, "prompt_version")
+	_analysis.Model = field.NewString(tableName, "model")
+	_analysis.HasVariants = field.NewBool(tableName, "has_variants")
+	_analysis.Version = field.NewInt(tableName, "version")
+	_analysis.ModerationStatus = field.NewString(tableName, "moderation_status")
+	_analysis.ModerationReason = field.NewString(tableName, "moderation_reason")
+	_analysis.Experiment = field.NewString(tableName, "experiment")
+	_analysis.Variant = field.NewString(tableName, "variant")
+	_analysis.IsShared = field.NewBool(tableName, "is_shared")
+	_analysis.JobId = field.NewInt(tableName, "job_id")
+	_analysis.Contributions = field.NewField(tableName, "contributions")
 	_analysis.CreatedAt = field.NewTime(tableName, "created_at")
 	_analysis.UpdatedAt = field.NewTime(tableName, "updated_at")
 	_analysis.DeletedAt = field.NewField(tableName, "deleted_at")
@@ -46,18 +63,34 @@ func newAnalysis(db *gorm.DB, opts ...gen.DOOption) analysis {
 type analysis struct {
 	analysisDo analysisDo
 
-	ALL          field.Asterisk
-	ID           field.Int
-	UserId       field.Int
-	ImageUrl     field.String
-	Score        field.Int
-	Description  field.String
-	Tags         field.Field
-	ScoreDetails field.Field
-	IsFavorite   field.Bool
-	CreatedAt    field.Time
-	UpdatedAt    field.Time
-	DeletedAt    field.Field
+	ALL              field.Asterisk
+	ID               field.Int
+	UserId           field.Int
+	ImageUrl         field.String
+	Score            field.Int
+	Description      field.String
+	Tags             field.Field
+	ScoreDetails     field.Field
+	AnalyisType      field.Int
+	Gender           field.Int
+	Percentile       field.Int
+	AnalystName      field.String
+	IsFallback       field.Bool
+	ImageHash        field.String
+	PromptVersion    field.String
+	Model            field.String
+	HasVariants      field.Bool
+	Version          field.Int
+	ModerationStatus field.String
+	ModerationReason field.String
+	Experiment       field.String
+	Variant          field.String
+	IsShared         field.Bool
+	JobId            field.Int
+	Contributions    field.Field
+	CreatedAt        field.Time  // 创建时间
+	UpdatedAt        field.Time  // 更新时间
+	DeletedAt        field.Field // 软删除时间
 
 	fieldMap map[string]field.Expr
 }
@@ -81,7 +114,23 @@ func (a *analysis) updateTableName(table string) *analysis {
 	a.Description = field.NewString(table, "description")
 	a.Tags = field.NewField(table, "tags")
 	a.ScoreDetails = field.NewField(table, "score_details")
-	a.IsFavorite = field.NewBool(table, "is_favorite")
+	a.AnalyisType = field.NewInt(table, "analyis_type")
+	a.Gender = field.NewInt(table, "gender")
+	a.Percentile = field.NewInt(table, "percentile")
+	a.AnalystName = field.NewString(table, "analyst_name")
+	a.IsFallback = field.NewBool(table, "is_fallback")
+	a.ImageHash = field.NewString(table, "image_hash")
+	a.PromptVersion = field.NewString(table, "prompt_version")
+	a.Model = field.NewString(table, "model")
+	a.HasVariants = field.NewBool(table, "has_variants")
+	a.Version = field.NewInt(table, "version")
+	a.ModerationStatus = field.NewString(table, "moderation_status")
+	a.ModerationReason = field.NewString(table, "moderation_reason")
+	a.Experiment = field.NewString(table, "experiment")
+	a.Variant = field.NewString(table, "variant")
+	a.IsShared = field.NewBool(table, "is_shared")
+	a.JobId = field.NewInt(table, "job_id")
+	a.Contributions = field.NewField(table, "contributions")
 	a.CreatedAt = field.NewTime(table, "created_at")
 	a.UpdatedAt = field.NewTime(table, "updated_at")
 	a.DeletedAt = field.NewField(table, "deleted_at")
@@ -109,7 +158,7 @@ func (a *analysis) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (a *analysis) fillFieldMap() {
-	a.fieldMap = make(map[string]field.Expr, 11)
+	a.fieldMap = make(map[string]field.Expr, 27)
 	a.fieldMap["id"] = a.ID
 	a.fieldMap["user_id"] = a.UserId
 	a.fieldMap["image_url"] = a.ImageUrl
@@ -117,7 +166,23 @@ func (a *analysis) fillFieldMap() {
 	a.fieldMap["description"] = a.Description
 	a.fieldMap["tags"] = a.Tags
 	a.fieldMap["score_details"] = a.ScoreDetails
-	a.fieldMap["is_favorite"] = a.IsFavorite
+	a.fieldMap["analyis_type"] = a.AnalyisType
+	a.fieldMap["gender"] = a.Gender
+	a.fieldMap["percentile"] = a.Percentile
+	a.fieldMap["analyst_name"] = a.AnalystName
+	a.fieldMap["is_fallback"] = a.IsFallback
+	a.fieldMap["image_hash"] = a.ImageHash
+	a.fieldMap["prompt_version"] = a.PromptVersion
+	a.fieldMap["model"] = a.Model
+	a.fieldMap["has_variants"] = a.HasVariants
+	a.fieldMap["version"] = a.Version
+	a.fieldMap["moderation_status"] = a.ModerationStatus
+	a.fieldMap["moderation_reason"] = a.ModerationReason
+	a.fieldMap["experiment"] = a.Experiment
+	a.fieldMap["variant"] = a.Variant
+	a.fieldMap["is_shared"] = a.IsShared
+	a.fieldMap["job_id"] = a.JobId
+	a.fieldMap["contributions"] = a.Contributions
 	a.fieldMap["created_at"] = a.CreatedAt
 	a.fieldMap["updated_at"] = a.UpdatedAt
 	a.fieldMap["deleted_at"] = a.DeletedAt
@@ -190,6 +255,8 @@ type IAnalysisDo interface {
 	FirstOrCreate() (*model.Analysis, error)
 	FindByPage(offset int, limit int) (result []*model.Analysis, count int64, err error)
 	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
 	Scan(result interface{}) (err error)
 	Returning(value interface{}, columns ...string) IAnalysisDo
 	UnderlyingDB() *gorm.DB
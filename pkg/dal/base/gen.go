@@ -17,23 +17,41 @@ import (
 
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
-		db:       db,
-		Analysis: newAnalysis(db, opts...),
+		db:                     db,
+		Analysis:               newAnalysis(db, opts...),
+		AnalysisCollection:     newAnalysisCollection(db, opts...),
+		AnalysisCollectionItem: newAnalysisCollectionItem(db, opts...),
+		AnalysisComparison:     newAnalysisComparison(db, opts...),
+		AnalysisJob:            newAnalysisJob(db, opts...),
+		AnalysisVersion:        newAnalysisVersion(db, opts...),
+		LeaderboardMember:      newLeaderboardMember(db, opts...),
 	}
 }
 
 type Query struct {
 	db *gorm.DB
 
-	Analysis analysis
+	Analysis               analysis
+	AnalysisCollection     analysisCollection
+	AnalysisCollectionItem analysisCollectionItem
+	AnalysisComparison     analysisComparison
+	AnalysisJob            analysisJob
+	AnalysisVersion        analysisVersion
+	LeaderboardMember      leaderboardMember
 }
 
 func (q *Query) Available() bool { return q.db != nil }
 
 func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
-		db:       db,
-		Analysis: q.Analysis.clone(db),
+		db:                     db,
+		Analysis:               q.Analysis.clone(db),
+		AnalysisCollection:     q.AnalysisCollection.clone(db),
+		AnalysisCollectionItem: q.AnalysisCollectionItem.clone(db),
+		AnalysisComparison:     q.AnalysisComparison.clone(db),
+		AnalysisJob:            q.AnalysisJob.clone(db),
+		AnalysisVersion:        q.AnalysisVersion.clone(db),
+		LeaderboardMember:      q.LeaderboardMember.clone(db),
 	}
 }
 
@@ -47,18 +65,36 @@ func (q *Query) WriteDB() *Query {
 
 func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
-		db:       db,
-		Analysis: q.Analysis.replaceDB(db),
+		db:                     db,
+		Analysis:               q.Analysis.replaceDB(db),
+		AnalysisCollection:     q.AnalysisCollection.replaceDB(db),
+		AnalysisCollectionItem: q.AnalysisCollectionItem.replaceDB(db),
+		AnalysisComparison:     q.AnalysisComparison.replaceDB(db),
+		AnalysisJob:            q.AnalysisJob.replaceDB(db),
+		AnalysisVersion:        q.AnalysisVersion.replaceDB(db),
+		LeaderboardMember:      q.LeaderboardMember.replaceDB(db),
 	}
 }
 
 type queryCtx struct {
-	Analysis IAnalysisDo
+	Analysis               IAnalysisDo
+	AnalysisCollection     IAnalysisCollectionDo
+	AnalysisCollectionItem IAnalysisCollectionItemDo
+	AnalysisComparison     IAnalysisComparisonDo
+	AnalysisJob            IAnalysisJobDo
+	AnalysisVersion        IAnalysisVersionDo
+	LeaderboardMember      ILeaderboardMemberDo
 }
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
 	return &queryCtx{
-		Analysis: q.Analysis.WithContext(ctx),
+		Analysis:               q.Analysis.WithContext(ctx),
+		AnalysisCollection:     q.AnalysisCollection.WithContext(ctx),
+		AnalysisCollectionItem: q.AnalysisCollectionItem.WithContext(ctx),
+		AnalysisComparison:     q.AnalysisComparison.WithContext(ctx),
+		AnalysisJob:            q.AnalysisJob.WithContext(ctx),
+		AnalysisVersion:        q.AnalysisVersion.WithContext(ctx),
+		LeaderboardMember:      q.LeaderboardMember.WithContext(ctx),
 	}
 }
 
@@ -0,0 +1,418 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package base
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+)
+
+func newLeaderboardMember(db *gorm.DB, opts ...gen.DOOption) leaderboardMember {
+	_leaderboardMember := leaderboardMember{}
+
+	_leaderboardMember.leaderboardMemberDo.UseDB(db, opts...)
+	_leaderboardMember.leaderboardMemberDo.UseModel(&model.LeaderboardMember{})
+
+	tableName := _leaderboardMember.leaderboardMemberDo.TableName()
+	_leaderboardMember.ALL = field.NewAsterisk(tableName)
+	_leaderboardMember.ID = field.NewInt(tableName, "id")
+	_leaderboardMember.AppName = field.NewString(tableName, "app_name")
+	_leaderboardMember.UserId = field.NewInt(tableName, "user_id")
+	_leaderboardMember.DisplayName = field.NewString(tableName, "display_name")
+	_leaderboardMember.Avatar = field.NewString(tableName, "avatar")
+	_leaderboardMember.CreatedAt = field.NewTime(tableName, "created_at")
+	_leaderboardMember.UpdatedAt = field.NewTime(tableName, "updated_at")
+
+	_leaderboardMember.fillFieldMap()
+
+	return _leaderboardMember
+}
+
+type leaderboardMember struct {
+	leaderboardMemberDo leaderboardMemberDo
+
+	ALL         field.Asterisk
+	ID          field.Int
+	AppName     field.String
+	UserId      field.Int
+	DisplayName field.String
+	Avatar      field.String
+	CreatedAt   field.Time // 创建时间
+	UpdatedAt   field.Time // 更新时间
+
+	fieldMap map[string]field.Expr
+}
+
+func (l leaderboardMember) Table(newTableName string) *leaderboardMember {
+	l.leaderboardMemberDo.UseTable(newTableName)
+	return l.updateTableName(newTableName)
+}
+
+func (l leaderboardMember) As(alias string) *leaderboardMember {
+	l.leaderboardMemberDo.DO = *(l.leaderboardMemberDo.As(alias).(*gen.DO))
+	return l.updateTableName(alias)
+}
+
+func (l *leaderboardMember) updateTableName(table string) *leaderboardMember {
+	l.ALL = field.NewAsterisk(table)
+	l.ID = field.NewInt(table, "id")
+	l.AppName = field.NewString(table, "app_name")
+	l.UserId = field.NewInt(table, "user_id")
+	l.DisplayName = field.NewString(table, "display_name")
+	l.Avatar = field.NewString(table, "avatar")
+	l.CreatedAt = field.NewTime(table, "created_at")
+	l.UpdatedAt = field.NewTime(table, "updated_at")
+
+	l.fillFieldMap()
+
+	return l
+}
+
+func (l *leaderboardMember) WithContext(ctx context.Context) ILeaderboardMemberDo {
+	return l.leaderboardMemberDo.WithContext(ctx)
+}
+
+func (l leaderboardMember) TableName() string { return l.leaderboardMemberDo.TableName() }
+
+func (l leaderboardMember) Alias() string { return l.leaderboardMemberDo.Alias() }
+
+func (l leaderboardMember) Columns(cols ...field.Expr) gen.Columns {
+	return l.leaderboardMemberDo.Columns(cols...)
+}
+
+func (l *leaderboardMember) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := l.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (l *leaderboardMember) fillFieldMap() {
+	l.fieldMap = make(map[string]field.Expr, 7)
+	l.fieldMap["id"] = l.ID
+	l.fieldMap["app_name"] = l.AppName
+	l.fieldMap["user_id"] = l.UserId
+	l.fieldMap["display_name"] = l.DisplayName
+	l.fieldMap["avatar"] = l.Avatar
+	l.fieldMap["created_at"] = l.CreatedAt
+	l.fieldMap["updated_at"] = l.UpdatedAt
+}
+
+func (l leaderboardMember) clone(db *gorm.DB) leaderboardMember {
+	l.leaderboardMemberDo.ReplaceConnPool(db.Statement.ConnPool)
+	return l
+}
+
+func (l leaderboardMember) replaceDB(db *gorm.DB) leaderboardMember {
+	l.leaderboardMemberDo.ReplaceDB(db)
+	return l
+}
+
+type leaderboardMemberDo struct{ gen.DO }
+
+type ILeaderboardMemberDo interface {
+	gen.SubQuery
+	Debug() ILeaderboardMemberDo
+	WithContext(ctx context.Context) ILeaderboardMemberDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() ILeaderboardMemberDo
+	WriteDB() ILeaderboardMemberDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) ILeaderboardMemberDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) ILeaderboardMemberDo
+	Not(conds ...gen.Condition) ILeaderboardMemberDo
+	Or(conds ...gen.Condition) ILeaderboardMemberDo
+	Select(conds ...field.Expr) ILeaderboardMemberDo
+	Where(conds ...gen.Condition) ILeaderboardMemberDo
+	Order(conds ...field.Expr) ILeaderboardMemberDo
+	Distinct(cols ...field.Expr) ILeaderboardMemberDo
+	Omit(cols ...field.Expr) ILeaderboardMemberDo
+	Join(table schema.Tabler, on ...field.Expr) ILeaderboardMemberDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) ILeaderboardMemberDo
+	RightJoin(table schema.Tabler, on ...field.Expr) ILeaderboardMemberDo
+	Group(cols ...field.Expr) ILeaderboardMemberDo
+	Having(conds ...gen.Condition) ILeaderboardMemberDo
+	Limit(limit int) ILeaderboardMemberDo
+	Offset(offset int) ILeaderboardMemberDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) ILeaderboardMemberDo
+	Unscoped() ILeaderboardMemberDo
+	Create(values ...*model.LeaderboardMember) error
+	CreateInBatches(values []*model.LeaderboardMember, batchSize int) error
+	Save(values ...*model.LeaderboardMember) error
+	First() (*model.LeaderboardMember, error)
+	Take() (*model.LeaderboardMember, error)
+	Last() (*model.LeaderboardMember, error)
+	Find() ([]*model.LeaderboardMember, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.LeaderboardMember, err error)
+	FindInBatches(result *[]*model.LeaderboardMember, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.LeaderboardMember) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) ILeaderboardMemberDo
+	Assign(attrs ...field.AssignExpr) ILeaderboardMemberDo
+	Joins(fields ...field.RelationField) ILeaderboardMemberDo
+	Preload(fields ...field.RelationField) ILeaderboardMemberDo
+	FirstOrInit() (*model.LeaderboardMember, error)
+	FirstOrCreate() (*model.LeaderboardMember, error)
+	FindByPage(offset int, limit int) (result []*model.LeaderboardMember, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Rows() (*sql.Rows, error)
+	Row() *sql.Row
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) ILeaderboardMemberDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (l leaderboardMemberDo) Debug() ILeaderboardMemberDo {
+	return l.withDO(l.DO.Debug())
+}
+
+func (l leaderboardMemberDo) WithContext(ctx context.Context) ILeaderboardMemberDo {
+	return l.withDO(l.DO.WithContext(ctx))
+}
+
+func (l leaderboardMemberDo) ReadDB() ILeaderboardMemberDo {
+	return l.Clauses(dbresolver.Read)
+}
+
+func (l leaderboardMemberDo) WriteDB() ILeaderboardMemberDo {
+	return l.Clauses(dbresolver.Write)
+}
+
+func (l leaderboardMemberDo) Session(config *gorm.Session) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Session(config))
+}
+
+func (l leaderboardMemberDo) Clauses(conds ...clause.Expression) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Clauses(conds...))
+}
+
+func (l leaderboardMemberDo) Returning(value interface{}, columns ...string) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Returning(value, columns...))
+}
+
+func (l leaderboardMemberDo) Not(conds ...gen.Condition) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Not(conds...))
+}
+
+func (l leaderboardMemberDo) Or(conds ...gen.Condition) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Or(conds...))
+}
+
+func (l leaderboardMemberDo) Select(conds ...field.Expr) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Select(conds...))
+}
+
+func (l leaderboardMemberDo) Where(conds ...gen.Condition) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Where(conds...))
+}
+
+func (l leaderboardMemberDo) Order(conds ...field.Expr) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Order(conds...))
+}
+
+func (l leaderboardMemberDo) Distinct(cols ...field.Expr) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Distinct(cols...))
+}
+
+func (l leaderboardMemberDo) Omit(cols ...field.Expr) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Omit(cols...))
+}
+
+func (l leaderboardMemberDo) Join(table schema.Tabler, on ...field.Expr) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Join(table, on...))
+}
+
+func (l leaderboardMemberDo) LeftJoin(table schema.Tabler, on ...field.Expr) ILeaderboardMemberDo {
+	return l.withDO(l.DO.LeftJoin(table, on...))
+}
+
+func (l leaderboardMemberDo) RightJoin(table schema.Tabler, on ...field.Expr) ILeaderboardMemberDo {
+	return l.withDO(l.DO.RightJoin(table, on...))
+}
+
+func (l leaderboardMemberDo) Group(cols ...field.Expr) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Group(cols...))
+}
+
+func (l leaderboardMemberDo) Having(conds ...gen.Condition) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Having(conds...))
+}
+
+func (l leaderboardMemberDo) Limit(limit int) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Limit(limit))
+}
+
+func (l leaderboardMemberDo) Offset(offset int) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Offset(offset))
+}
+
+func (l leaderboardMemberDo) Scopes(funcs ...func(gen.Dao) gen.Dao) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Scopes(funcs...))
+}
+
+func (l leaderboardMemberDo) Unscoped() ILeaderboardMemberDo {
+	return l.withDO(l.DO.Unscoped())
+}
+
+func (l leaderboardMemberDo) Create(values ...*model.LeaderboardMember) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return l.DO.Create(values)
+}
+
+func (l leaderboardMemberDo) CreateInBatches(values []*model.LeaderboardMember, batchSize int) error {
+	return l.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (l leaderboardMemberDo) Save(values ...*model.LeaderboardMember) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return l.DO.Save(values)
+}
+
+func (l leaderboardMemberDo) First() (*model.LeaderboardMember, error) {
+	if result, err := l.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.LeaderboardMember), nil
+	}
+}
+
+func (l leaderboardMemberDo) Take() (*model.LeaderboardMember, error) {
+	if result, err := l.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.LeaderboardMember), nil
+	}
+}
+
+func (l leaderboardMemberDo) Last() (*model.LeaderboardMember, error) {
+	if result, err := l.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.LeaderboardMember), nil
+	}
+}
+
+func (l leaderboardMemberDo) Find() ([]*model.LeaderboardMember, error) {
+	result, err := l.DO.Find()
+	return result.([]*model.LeaderboardMember), err
+}
+
+func (l leaderboardMemberDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.LeaderboardMember, err error) {
+	buf := make([]*model.LeaderboardMember, 0, batchSize)
+	err = l.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (l leaderboardMemberDo) FindInBatches(result *[]*model.LeaderboardMember, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return l.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (l leaderboardMemberDo) Attrs(attrs ...field.AssignExpr) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Attrs(attrs...))
+}
+
+func (l leaderboardMemberDo) Assign(attrs ...field.AssignExpr) ILeaderboardMemberDo {
+	return l.withDO(l.DO.Assign(attrs...))
+}
+
+func (l leaderboardMemberDo) Joins(fields ...field.RelationField) ILeaderboardMemberDo {
+	for _, _f := range fields {
+		l = *l.withDO(l.DO.Joins(_f))
+	}
+	return &l
+}
+
+func (l leaderboardMemberDo) Preload(fields ...field.RelationField) ILeaderboardMemberDo {
+	for _, _f := range fields {
+		l = *l.withDO(l.DO.Preload(_f))
+	}
+	return &l
+}
+
+func (l leaderboardMemberDo) FirstOrInit() (*model.LeaderboardMember, error) {
+	if result, err := l.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.LeaderboardMember), nil
+	}
+}
+
+func (l leaderboardMemberDo) FirstOrCreate() (*model.LeaderboardMember, error) {
+	if result, err := l.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.LeaderboardMember), nil
+	}
+}
+
+func (l leaderboardMemberDo) FindByPage(offset int, limit int) (result []*model.LeaderboardMember, count int64, err error) {
+	result, err = l.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = l.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (l leaderboardMemberDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = l.Count()
+	if err != nil {
+		return
+	}
+
+	err = l.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (l leaderboardMemberDo) Scan(result interface{}) (err error) {
+	return l.DO.Scan(result)
+}
+
+func (l leaderboardMemberDo) Delete(models ...*model.LeaderboardMember) (result gen.ResultInfo, err error) {
+	return l.DO.Delete(models)
+}
+
+func (l *leaderboardMemberDo) withDO(do gen.Dao) *leaderboardMemberDo {
+	l.DO = *do.(*gen.DO)
+	return l
+}
@@ -0,0 +1,173 @@
+// File:		leaderboard.go
+// Created by:	Hoven
+// Created on:	2025-06-14
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package leaderboardRepo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yazl-tech/beauty-rating-server/domain/leaderboard"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/base"
+	"github.com/yazl-tech/beauty-rating-server/pkg/dal/model"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ leaderboard.Repo = (*LeaderboardRepo)(nil)
+
+type LeaderboardRepo struct {
+	db *base.Query
+}
+
+func NewLeaderboardRepo(db *gorm.DB) *LeaderboardRepo {
+	return &LeaderboardRepo{db: base.Use(db)}
+}
+
+func (lr *LeaderboardRepo) SaveMember(ctx context.Context, member *leaderboard.Member) error {
+	db := lr.db.LeaderboardMember
+
+	memberDal := new(model.LeaderboardMember)
+	memberDal.FromEntity(member)
+	err := db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: db.AppName.ColumnName().String()}, {Name: db.UserId.ColumnName().String()}},
+			DoUpdates: clause.AssignmentColumns([]string{db.DisplayName.ColumnName().String(), db.Avatar.ColumnName().String(), db.UpdatedAt.ColumnName().String()}),
+		}).
+		Create(memberDal)
+	if err != nil {
+		return err
+	}
+
+	saved, err := lr.GetMember(ctx, member.AppName, member.UserID)
+	if err != nil {
+		return err
+	}
+	if saved != nil {
+		member.JoinedAt = saved.JoinedAt
+	}
+	return nil
+}
+
+func (lr *LeaderboardRepo) GetMember(ctx context.Context, appName string, userId int) (*leaderboard.Member, error) {
+	db := lr.db.LeaderboardMember
+
+	member, err := db.WithContext(ctx).Where(db.AppName.Eq(appName), db.UserId.Eq(userId)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return member.ToEntity(), nil
+}
+
+func (lr *LeaderboardRepo) DeleteMember(ctx context.Context, appName string, userId int) error {
+	db := lr.db.LeaderboardMember
+
+	_, err := db.WithContext(ctx).Where(db.AppName.Eq(appName), db.UserId.Eq(userId)).Delete()
+	return err
+}
+
+// originalVersion 报告第一次分析的结果对应的版本号，报告重新分析之前没有版本记录，结果就是报告本身
+const originalVersion = 1
+
+// bestScore 报告第一次分析的最高分
+func (lr *LeaderboardRepo) bestScore() field.Field {
+	return field.NewUnsafeFieldRaw("MAX(COALESCE(?, ?))", lr.db.AnalysisVersion.Score, lr.db.Analysis.Score)
+}
+
+// rankedMembers 按用户汇总榜单成员在范围内的最高分，没有报告的成员不出现在结果中
+func (lr *LeaderboardRepo) rankedMembers(ctx context.Context, scope *leaderboard.Scope) base.ILeaderboardMemberDo {
+	m := lr.db.LeaderboardMember
+	a := lr.db.Analysis
+	v := lr.db.AnalysisVersion
+
+	on := []field.Expr{a.UserId.EqCol(m.UserId), a.DeletedAt.IsNull()}
+	if !scope.Since.IsZero() {
+		on = append(on, a.CreatedAt.Gte(scope.Since))
+	}
+
+	conds := []gen.Condition{m.AppName.Eq(scope.AppName)}
+	if scope.AiOnly {
+		conds = append(conds,
+			field.NewUnsafeFieldRaw("COALESCE(?, ?) = ?", v.AnalyisType, a.AnalyisType, int(analyst.TypeAi)),
+			field.NewUnsafeFieldRaw("NOT COALESCE(?, ?)", v.IsFallback, a.IsFallback),
+		)
+	}
+
+	return m.WithContext(ctx).
+		Select(
+			m.UserId.As("user_id"),
+			m.DisplayName.As("name"),
+			m.Avatar.As("avatar"),
+			lr.bestScore().As("best_score"),
+		).
+		Join(a, on...).
+		LeftJoin(v, v.ReportId.EqCol(a.ID), v.Version.Eq(originalVersion), v.DeletedAt.IsNull()).
+		Where(conds...).
+		Group(m.UserId, m.DisplayName, m.Avatar)
+}
+
+// countRows 统计分组查询的结果行数
+func (lr *LeaderboardRepo) countRows(ctx context.Context, do base.ILeaderboardMemberDo) (int64, error) {
+	var count int64
+	err := lr.db.LeaderboardMember.WithContext(ctx).UnderlyingDB().
+		Session(&gorm.Session{NewDB: true, Context: ctx}).
+		Table("(?) AS ranked", do.UnderlyingDB()).
+		Count(&count).Error
+	return count, err
+}
+
+func (lr *LeaderboardRepo) GetTopEntries(ctx context.Context, scope *leaderboard.Scope, limit int) ([]*leaderboard.Entry, error) {
+	m := lr.db.LeaderboardMember
+
+	var entries []*leaderboard.Entry
+	err := lr.rankedMembers(ctx, scope).
+		Order(field.NewField("", "best_score").Desc(), m.UserId).
+		Limit(limit).
+		Scan(&entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (lr *LeaderboardRepo) GetEntry(ctx context.Context, scope *leaderboard.Scope, userId int) (*leaderboard.Entry, error) {
+	m := lr.db.LeaderboardMember
+
+	var entries []*leaderboard.Entry
+	err := lr.rankedMembers(ctx, scope).Where(m.UserId.Eq(userId)).Scan(&entries)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	return entries[0], nil
+}
+
+func (lr *LeaderboardRepo) CountAhead(ctx context.Context, scope *leaderboard.Scope, score, userId int) (int64, error) {
+	m := lr.db.LeaderboardMember
+
+	best := lr.bestScore()
+	ahead := lr.rankedMembers(ctx, scope).Having(field.Or(
+		field.NewUnsafeFieldRaw("? > ?", best, score),
+		field.And(field.NewUnsafeFieldRaw("? = ?", best, score), m.UserId.Lt(userId)),
+	))
+	return lr.countRows(ctx, ahead)
+}
+
+func (lr *LeaderboardRepo) CountRanked(ctx context.Context, scope *leaderboard.Scope) (int64, error) {
+	return lr.countRows(ctx, lr.rankedMembers(ctx, scope))
+}
@@ -12,7 +12,6 @@ import (
 	"encoding/json"
 	"time"
 
-	"github.com/go-puzzles/puzzles/putils"
 	"github.com/pkg/errors"
 	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
 	"gorm.io/datatypes"
@@ -20,17 +19,35 @@ import (
 )
 
 type Analysis struct {
-	ID           int    `gorm:"primaryKey;autoIncrement"`
-	UserId       int    `gorm:"not null"`
-	ImageUrl     string `gorm:"not null;type:varchar(256)"`
-	Score        int    `gorm:"not null"`
-	Description  string `gorm:"type:text"`
-	Tags         datatypes.JSON
-	ScoreDetails datatypes.JSON
-	IsFavorite   bool
-	AnalyisType  int
-
-	CreatedAt time.Time      `gorm:"comment:创建时间"`
+	ID            int    `gorm:"primaryKey;autoIncrement"`
+	UserId        int    `gorm:"not null;index:idx_user_date,priority:1;index:idx_user_score,priority:1"`
+	ImageUrl      string `gorm:"not null;type:varchar(256)"`
+	Score         int    `gorm:"not null;index:idx_user_score,priority:2"`
+	Description   string `gorm:"type:text"`
+	Tags          datatypes.JSON
+	ScoreDetails  datatypes.JSON
+	AnalyisType   int
+	Gender        int
+	Percentile    *int   `gorm:"not null;default:-1"`
+	AnalystName   string `gorm:"type:varchar(64)"`
+	IsFallback    bool
+	ImageHash     string `gorm:"type:char(64);index"`
+	PromptVersion string `gorm:"type:varchar(32);index"`
+	Model         string `gorm:"type:varchar(64);index"`
+	HasVariants   bool   `gorm:"not null;default:false"`
+	Version       int    `gorm:"not null;default:1"`
+	// ModerationStatus 审核结论：allow/review，被拒绝的图片不会入库
+	ModerationStatus string `gorm:"type:varchar(16);index"`
+	ModerationReason string `gorm:"type:varchar(256)"`
+	Experiment       string `gorm:"type:varchar(64);index:idx_experiment_variant"`
+	Variant          string `gorm:"type:varchar(64);index:idx_experiment_variant"`
+	IsShared         bool   `gorm:"not null;default:false"`
+	JobId            *int   `gorm:"uniqueIndex"`
+	// Contributions 组合分析器各成员的结果，用于审计合并后的分数
+	Contributions datatypes.JSON
+
+	// 报告列表按用户筛选后按时间或分数排序
+	CreatedAt time.Time      `gorm:"comment:创建时间;index:idx_user_date,priority:2"`
 	UpdatedAt time.Time      `gorm:"comment:更新时间"`
 	DeletedAt gorm.DeletedAt `gorm:"index;comment:软删除时间"`
 }
@@ -49,16 +66,51 @@ func (a *Analysis) FromEntity(entity *analysis.AnalysisDetail) (err error) {
 	a.ImageUrl = entity.ImageUrl
 	a.Score = entity.Score
 	a.Description = entity.Description
-	a.Tags, err = a.convertDBJson(entity.Tags)
-	a.ScoreDetails, err = a.convertDBJson(entity.ScoreDetails)
-	a.IsFavorite = entity.IsFavorite
+	a.Tags, err = convertDBJson(entity.Tags)
+	a.ScoreDetails, err = convertDBJson(entity.ScoreDetails)
 	a.AnalyisType = entity.AnalyisType
+	a.Gender = entity.Gender
+	a.Percentile = toPercentile(entity.Percentile)
+	a.AnalystName = entity.AnalystName
+	a.IsFallback = entity.IsFallback
+	a.ImageHash = entity.ImageHash
+	a.PromptVersion = entity.PromptVersion
+	a.Model = entity.Model
+	a.HasVariants = entity.HasVariants
+	a.Version = entity.Version
+	a.ModerationStatus = entity.ModerationStatus
+	a.ModerationReason = entity.ModerationReason
+	a.Experiment = entity.Experiment
+	a.Variant = entity.Variant
+	a.IsShared = entity.IsShared
+	if entity.JobID != 0 {
+		jobId := entity.JobID
+		a.JobId = &jobId
+	}
 	a.CreatedAt = entity.Date
+	if len(entity.Contributions) > 0 {
+		if a.Contributions, err = convertDBJson(entity.Contributions); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-func (a *Analysis) convertDBJson(v any) (datatypes.JSON, error) {
+// toPercentile 百分位字段使用指针，否则 gorm 创建记录时会忽略 0 分位而写入默认值，
+// 默认值只用于将加字段之前的历史记录标记为未排名
+func toPercentile(percentile int) *int {
+	return &percentile
+}
+
+func fromPercentile(percentile *int) int {
+	if percentile == nil {
+		return analysis.PercentileUnranked
+	}
+	return *percentile
+}
+
+func convertDBJson(v any) (datatypes.JSON, error) {
 	b, err := json.Marshal(v)
 	if err != nil {
 		return nil, errors.Wrap(err, "marshal")
@@ -73,33 +125,54 @@ func (a *Analysis) ToEntity() (ad *analysis.AnalysisDetail, err error) {
 	}
 
 	ad = &analysis.AnalysisDetail{
-		ID:          a.ID,
-		UserID:      a.UserId,
-		ImageUrl:    a.ImageUrl,
-		Score:       a.Score,
-		Description: a.Description,
-		IsFavorite:  a.IsFavorite,
-		// TODO:
-		Percentile:   putils.RandInt(80, 99),
-		Date:         a.CreatedAt,
-		Tags:         make([]string, 0),
-		ScoreDetails: make([]analysis.ScoreDetail, 0),
-		AnalyisType:  a.AnalyisType,
+		ID:            a.ID,
+		UserID:        a.UserId,
+		ImageUrl:      a.ImageUrl,
+		Score:         a.Score,
+		Description:   a.Description,
+		Percentile:    fromPercentile(a.Percentile),
+		Date:          a.CreatedAt,
+		Tags:          make([]string, 0),
+		ScoreDetails:  make([]analysis.ScoreDetail, 0),
+		AnalyisType:   a.AnalyisType,
+		Gender:        a.Gender,
+		AnalystName:   a.AnalystName,
+		IsFallback:    a.IsFallback,
+		ImageHash:     a.ImageHash,
+		PromptVersion: a.PromptVersion,
+		Model:         a.Model,
+		HasVariants:   a.HasVariants,
+		Version:       a.Version,
+
+		ModerationStatus: a.ModerationStatus,
+		ModerationReason: a.ModerationReason,
+		Experiment:       a.Experiment,
+		Variant:          a.Variant,
+		IsShared:         a.IsShared,
+	}
+	if a.JobId != nil {
+		ad.JobID = *a.JobId
 	}
 
-	err = a.parseDBJson(a.Tags, &ad.Tags)
+	err = parseDBJson(a.Tags, &ad.Tags)
 	if err != nil {
 		return nil, err
 	}
 
-	err = a.parseDBJson(a.ScoreDetails, &ad.ScoreDetails)
+	err = parseDBJson(a.ScoreDetails, &ad.ScoreDetails)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(a.Contributions) > 0 {
+		if err := parseDBJson(a.Contributions, &ad.Contributions); err != nil {
+			return nil, err
+		}
+	}
+
 	return ad, nil
 }
 
-func (a *Analysis) parseDBJson(j datatypes.JSON, v any) error {
+func parseDBJson(j datatypes.JSON, v any) error {
 	return json.Unmarshal(j, v)
 }
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// dryRunDB 只生成 SQL 不连接数据库
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "user:pass@tcp(127.0.0.1:3306)/beauty", SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestAnalysis_ZeroPercentile(t *testing.T) {
+	detail := &analysis.AnalysisDetail{UserID: 1, ImageUrl: "1.jpg", Score: 30, Percentile: 0}
+
+	a := new(Analysis)
+	if err := a.FromEntity(detail); err != nil {
+		t.Fatal(err)
+	}
+
+	stmt := dryRunDB(t).Create(a).Statement
+	if !strings.Contains(stmt.SQL.String(), "`percentile`") {
+		t.Fatalf("期望创建记录时写入 0 分位，实际 SQL: %v", stmt.SQL.String())
+	}
+	if !slices.ContainsFunc(stmt.Vars, func(v any) bool { p, ok := v.(*int); return ok && *p == 0 }) {
+		t.Errorf("期望写入的百分位为 0，实际参数: %v", stmt.Vars)
+	}
+
+	entity, err := a.ToEntity()
+	if err != nil || entity.Percentile != 0 {
+		t.Errorf("期望读取的百分位为 0，实际: %+v, %v", entity, err)
+	}
+
+	a.Percentile = nil
+	if got, err := a.ToEntity(); err != nil || got.Percentile != analysis.PercentileUnranked {
+		t.Errorf("期望没有百分位的记录未排名，实际: %+v, %v", got, err)
+	}
+}
+
+func TestAnalysisVersion_ZeroPercentile(t *testing.T) {
+	v := new(AnalysisVersion)
+	if err := v.FromEntity(&analysis.AnalysisVersion{ReportID: 1, Version: 1, Percentile: 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	stmt := dryRunDB(t).Create(v).Statement
+	if !strings.Contains(stmt.SQL.String(), "`percentile`") {
+		t.Errorf("期望创建版本时写入 0 分位，实际 SQL: %v", stmt.SQL.String())
+	}
+}
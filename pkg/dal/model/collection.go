@@ -0,0 +1,87 @@
+// File:		collection.go
+// Created by:	Hoven
+// Created on:	2025-06-15
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package model
+
+import (
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+)
+
+// AnalysisCollection 用户的收藏夹，同一用户的收藏夹不能重名，删除时直接删除记录
+type AnalysisCollection struct {
+	ID            int    `gorm:"primaryKey;autoIncrement"`
+	UserId        int    `gorm:"not null;uniqueIndex:idx_user_name,priority:1"`
+	Name          string `gorm:"not null;type:varchar(64);uniqueIndex:idx_user_name,priority:2"`
+	IsDefault     bool   `gorm:"not null;default:false"`
+	CoverReportId int
+	Position      int `gorm:"not null;default:0"`
+
+	CreatedAt time.Time `gorm:"comment:创建时间"`
+	UpdatedAt time.Time `gorm:"comment:更新时间"`
+}
+
+func (c *AnalysisCollection) TableName() string {
+	return "analysis_collections"
+}
+
+func (c *AnalysisCollection) FromEntity(entity *analysis.Collection) {
+	if entity == nil {
+		return
+	}
+
+	c.ID = entity.ID
+	c.UserId = entity.UserID
+	c.Name = entity.Name
+	c.IsDefault = entity.IsDefault
+	c.CoverReportId = entity.CoverReportID
+	c.Position = entity.Position
+	c.CreatedAt = entity.Date
+}
+
+func (c *AnalysisCollection) ToEntity() *analysis.Collection {
+	if c == nil {
+		return nil
+	}
+
+	return &analysis.Collection{
+		ID:            c.ID,
+		UserID:        c.UserId,
+		Name:          c.Name,
+		IsDefault:     c.IsDefault,
+		CoverReportID: c.CoverReportId,
+		Position:      c.Position,
+		Date:          c.CreatedAt,
+	}
+}
+
+// AnalysisCollectionItem 报告与收藏夹的关联，一份报告可以加入多个收藏夹
+type AnalysisCollectionItem struct {
+	ID           int `gorm:"primaryKey;autoIncrement"`
+	CollectionId int `gorm:"not null;uniqueIndex:idx_collection_report,priority:1"`
+	ReportId     int `gorm:"not null;uniqueIndex:idx_collection_report,priority:2;index"`
+	UserId       int `gorm:"not null"`
+
+	CreatedAt time.Time `gorm:"comment:加入收藏夹的时间"`
+}
+
+func (i *AnalysisCollectionItem) TableName() string {
+	return "analysis_collection_items"
+}
+
+func (i *AnalysisCollectionItem) FromEntity(entity *analysis.CollectionItem) {
+	if entity == nil {
+		return
+	}
+
+	i.CollectionId = entity.CollectionID
+	i.ReportId = entity.ReportID
+	i.UserId = entity.UserID
+	i.CreatedAt = entity.Date
+}
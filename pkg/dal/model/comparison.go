@@ -0,0 +1,89 @@
+// File:		comparison.go
+// Created by:	Hoven
+// Created on:	2025-06-11
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package model
+
+import (
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type AnalysisComparison struct {
+	ID          int `gorm:"primaryKey;autoIncrement"`
+	UserId      int `gorm:"not null;index"`
+	BeforeId    int `gorm:"not null"`
+	AfterId     int `gorm:"not null"`
+	BeforeScore int
+	AfterScore  int
+	ScoreDelta  int
+	LabelDeltas datatypes.JSON
+	Narrative   string `gorm:"type:text"`
+	IsFavorite  bool
+	IsShared    bool `gorm:"not null;default:false"`
+
+	CreatedAt time.Time      `gorm:"comment:创建时间"`
+	UpdatedAt time.Time      `gorm:"comment:更新时间"`
+	DeletedAt gorm.DeletedAt `gorm:"index;comment:软删除时间"`
+}
+
+func (c *AnalysisComparison) TableName() string {
+	return "analysis_comparisons"
+}
+
+func (c *AnalysisComparison) FromEntity(entity *analysis.Comparison) (err error) {
+	if entity == nil {
+		return nil
+	}
+
+	c.ID = entity.ID
+	c.UserId = entity.UserID
+	c.BeforeId = entity.BeforeID
+	c.AfterId = entity.AfterID
+	c.BeforeScore = entity.BeforeScore
+	c.AfterScore = entity.AfterScore
+	c.ScoreDelta = entity.ScoreDelta
+	if c.LabelDeltas, err = convertDBJson(entity.LabelDeltas); err != nil {
+		return err
+	}
+	c.Narrative = entity.Narrative
+	c.IsFavorite = entity.IsFavorite
+	c.IsShared = entity.IsShared
+	c.CreatedAt = entity.Date
+
+	return nil
+}
+
+func (c *AnalysisComparison) ToEntity() (*analysis.Comparison, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	ac := &analysis.Comparison{
+		ID:          c.ID,
+		UserID:      c.UserId,
+		BeforeID:    c.BeforeId,
+		AfterID:     c.AfterId,
+		BeforeScore: c.BeforeScore,
+		AfterScore:  c.AfterScore,
+		ScoreDelta:  c.ScoreDelta,
+		LabelDeltas: make([]analysis.LabelDelta, 0),
+		Narrative:   c.Narrative,
+		IsFavorite:  c.IsFavorite,
+		IsShared:    c.IsShared,
+		Date:        c.CreatedAt,
+	}
+
+	if err := parseDBJson(c.LabelDeltas, &ac.LabelDeltas); err != nil {
+		return nil, err
+	}
+
+	return ac, nil
+}
@@ -0,0 +1,74 @@
+// File:		job.go
+// Created by:	Hoven
+// Created on:	2025-05-29
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package model
+
+import (
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"gorm.io/gorm"
+)
+
+type AnalysisJob struct {
+	ID       int    `gorm:"primaryKey;autoIncrement"`
+	UserId   int    `gorm:"not null;index"`
+	ImageUrl string `gorm:"not null;type:varchar(256)"`
+	Gender   int
+	Status   string `gorm:"not null;type:varchar(16);index"`
+	DetailId int
+	Error    string `gorm:"type:text"`
+	Message  string `gorm:"type:varchar(256)"`
+	Attempts int    `gorm:"not null;default:0"`
+
+	CreatedAt time.Time      `gorm:"comment:创建时间"`
+	UpdatedAt time.Time      `gorm:"comment:更新时间"`
+	DeletedAt gorm.DeletedAt `gorm:"index;comment:软删除时间"`
+}
+
+func (a *AnalysisJob) TableName() string {
+	return "analysis_jobs"
+}
+
+func (a *AnalysisJob) FromEntity(entity *analysis.AnalysisJob) {
+	if entity == nil {
+		return
+	}
+
+	a.ID = entity.ID
+	a.UserId = entity.UserID
+	a.ImageUrl = entity.ImageUrl
+	a.Gender = entity.Gender
+	a.Status = string(entity.Status)
+	a.DetailId = entity.DetailID
+	a.Error = entity.Error
+	a.Message = entity.Message
+	a.Attempts = entity.Attempts
+	a.CreatedAt = entity.CreatedAt
+	a.UpdatedAt = entity.UpdatedAt
+}
+
+func (a *AnalysisJob) ToEntity() *analysis.AnalysisJob {
+	if a == nil {
+		return nil
+	}
+
+	return &analysis.AnalysisJob{
+		ID:        a.ID,
+		UserID:    a.UserId,
+		ImageUrl:  a.ImageUrl,
+		Gender:    a.Gender,
+		Status:    analysis.JobStatus(a.Status),
+		DetailID:  a.DetailId,
+		Error:     a.Error,
+		Message:   a.Message,
+		Attempts:  a.Attempts,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+	}
+}
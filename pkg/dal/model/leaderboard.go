@@ -0,0 +1,57 @@
+// File:		leaderboard.go
+// Created by:	Hoven
+// Created on:	2025-06-14
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package model
+
+import (
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/domain/leaderboard"
+)
+
+// LeaderboardMember 加入榜单的用户，退出榜单时直接删除记录
+type LeaderboardMember struct {
+	ID          int    `gorm:"primaryKey;autoIncrement"`
+	AppName     string `gorm:"not null;type:varchar(64);uniqueIndex:idx_app_user,priority:1"`
+	UserId      int    `gorm:"not null;uniqueIndex:idx_app_user,priority:2"`
+	DisplayName string `gorm:"type:varchar(64)"`
+	Avatar      string `gorm:"type:varchar(256)"`
+
+	CreatedAt time.Time `gorm:"comment:创建时间"`
+	UpdatedAt time.Time `gorm:"comment:更新时间"`
+}
+
+func (m *LeaderboardMember) TableName() string {
+	return "leaderboard_members"
+}
+
+func (m *LeaderboardMember) FromEntity(entity *leaderboard.Member) {
+	if entity == nil {
+		return
+	}
+
+	m.AppName = entity.AppName
+	m.UserId = entity.UserID
+	m.DisplayName = entity.Name
+	m.Avatar = entity.Avatar
+	m.CreatedAt = entity.JoinedAt
+}
+
+func (m *LeaderboardMember) ToEntity() *leaderboard.Member {
+	if m == nil {
+		return nil
+	}
+
+	return &leaderboard.Member{
+		AppName:  m.AppName,
+		UserID:   m.UserId,
+		Name:     m.DisplayName,
+		Avatar:   m.Avatar,
+		JoinedAt: m.CreatedAt,
+	}
+}
@@ -13,5 +13,11 @@ import "github.com/go-puzzles/puzzles/pgorm"
 func AllTables() []pgorm.SqlModel {
 	return []pgorm.SqlModel{
 		new(Analysis),
+		new(AnalysisJob),
+		new(AnalysisVersion),
+		new(AnalysisComparison),
+		new(LeaderboardMember),
+		new(AnalysisCollection),
+		new(AnalysisCollectionItem),
 	}
 }
@@ -0,0 +1,117 @@
+// File:		version.go
+// Created by:	Hoven
+// Created on:	2025-06-09
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package model
+
+import (
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type AnalysisVersion struct {
+	ID            int    `gorm:"primaryKey;autoIncrement"`
+	ReportId      int    `gorm:"not null;uniqueIndex:idx_report_version"`
+	Version       int    `gorm:"not null;uniqueIndex:idx_report_version"`
+	Score         int    `gorm:"not null"`
+	Percentile    *int   `gorm:"not null;default:-1"`
+	Description   string `gorm:"type:text"`
+	Tags          datatypes.JSON
+	ScoreDetails  datatypes.JSON
+	AnalyisType   int
+	AnalystName   string `gorm:"type:varchar(64)"`
+	IsFallback    bool
+	PromptVersion string `gorm:"type:varchar(32)"`
+	Model         string `gorm:"type:varchar(64)"`
+	Experiment    string `gorm:"type:varchar(64)"`
+	Variant       string `gorm:"type:varchar(64)"`
+	Contributions datatypes.JSON
+
+	CreatedAt time.Time      `gorm:"comment:创建时间"`
+	UpdatedAt time.Time      `gorm:"comment:更新时间"`
+	DeletedAt gorm.DeletedAt `gorm:"index;comment:软删除时间"`
+}
+
+func (v *AnalysisVersion) TableName() string {
+	return "analysis_versions"
+}
+
+func (v *AnalysisVersion) FromEntity(entity *analysis.AnalysisVersion) (err error) {
+	if entity == nil {
+		return nil
+	}
+
+	v.ID = entity.ID
+	v.ReportId = entity.ReportID
+	v.Version = entity.Version
+	v.Score = entity.Score
+	v.Percentile = toPercentile(entity.Percentile)
+	v.Description = entity.Description
+	if v.Tags, err = convertDBJson(entity.Tags); err != nil {
+		return err
+	}
+	if v.ScoreDetails, err = convertDBJson(entity.ScoreDetails); err != nil {
+		return err
+	}
+	v.AnalyisType = entity.AnalyisType
+	v.AnalystName = entity.AnalystName
+	v.IsFallback = entity.IsFallback
+	v.PromptVersion = entity.PromptVersion
+	v.Model = entity.Model
+	v.Experiment = entity.Experiment
+	v.Variant = entity.Variant
+	v.CreatedAt = entity.Date
+	if len(entity.Contributions) > 0 {
+		if v.Contributions, err = convertDBJson(entity.Contributions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *AnalysisVersion) ToEntity() (*analysis.AnalysisVersion, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	av := &analysis.AnalysisVersion{
+		ID:            v.ID,
+		ReportID:      v.ReportId,
+		Version:       v.Version,
+		Score:         v.Score,
+		Percentile:    fromPercentile(v.Percentile),
+		Description:   v.Description,
+		Tags:          make([]string, 0),
+		ScoreDetails:  make([]analysis.ScoreDetail, 0),
+		AnalyisType:   v.AnalyisType,
+		Date:          v.CreatedAt,
+		AnalystName:   v.AnalystName,
+		IsFallback:    v.IsFallback,
+		PromptVersion: v.PromptVersion,
+		Model:         v.Model,
+		Experiment:    v.Experiment,
+		Variant:       v.Variant,
+	}
+
+	if err := parseDBJson(v.Tags, &av.Tags); err != nil {
+		return nil, err
+	}
+	if err := parseDBJson(v.ScoreDetails, &av.ScoreDetails); err != nil {
+		return nil, err
+	}
+	if len(v.Contributions) > 0 {
+		if err := parseDBJson(v.Contributions, &av.Contributions); err != nil {
+			return nil, err
+		}
+	}
+
+	return av, nil
+}
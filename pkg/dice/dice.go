@@ -10,25 +10,118 @@ package dice
 
 import (
 	"math/rand"
+	"sync"
+	"time"
 )
 
+type Option func(*Dice)
+
+// WithSource 指定随机源，传入固定种子的 Source 可以得到可复现的抽样序列
+func WithSource(src rand.Source) Option {
+	return func(d *Dice) {
+		d.rander = rand.New(src)
+	}
+}
+
+// Dice 按权重随机抽取下标，所有方法均可并发调用
 type Dice struct {
+	mu       sync.Mutex
+	rander   *rand.Rand
 	total    int
 	weights  []int
 	original []int
+
+	// alias method 查表，用于 O(1) 的放回抽样
+	originalTotal int
+	prob          []float64
+	alias         []int
 }
 
-func NewDice(weights []int) *Dice {
+func NewDice(weights []int, opts ...Option) *Dice {
 	total := 0
 
 	for _, w := range weights {
 		total += w
 	}
-	return &Dice{
-		total:    total,
-		weights:  append([]int(nil), weights...),
-		original: append([]int(nil), weights...),
+	d := &Dice{
+		total:         total,
+		weights:       append([]int(nil), weights...),
+		original:      append([]int(nil), weights...),
+		originalTotal: total,
 	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.rander == nil {
+		d.rander = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	d.buildAlias()
+	return d
+}
+
+// buildAlias 使用 Vose 算法根据原始权重构建 alias 表
+func (d *Dice) buildAlias() {
+	n := len(d.original)
+	d.prob = make([]float64, n)
+	d.alias = make([]int, n)
+
+	sum := d.originalTotal
+	if sum == 0 {
+		return
+	}
+
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, w := range d.original {
+		scaled[i] = float64(w) * float64(n) / float64(sum)
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+
+		d.prob[s] = scaled[s]
+		d.alias[s] = l
+
+		scaled[l] = scaled[l] + scaled[s] - 1
+		if scaled[l] < 1 {
+			small = append(small, l)
+		} else {
+			large = append(large, l)
+		}
+	}
+
+	for _, i := range large {
+		d.prob[i] = 1
+	}
+	// 浮点误差导致残留在 small 中的元素概率实际为 1
+	for _, i := range small {
+		d.prob[i] = 1
+	}
+}
+
+// Sample 按原始权重进行放回抽样，时间复杂度 O(1)，不影响 Next 的轮次状态
+//
+// 所有权重为 0 时返回 -1
+func (d *Dice) Sample() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.originalTotal == 0 {
+		return -1
+	}
+
+	i := d.rander.Intn(len(d.prob))
+	if d.rander.Float64() < d.prob[i] {
+		return i
+	}
+	return d.alias[i]
 }
 
 // Next 根据权重进行随机抽取，支持不放回抽样模式
@@ -84,11 +177,14 @@ func NewDice(weights []int) *Dice {
 //   - 返回被抽中元素的索引（从0开始）
 //   - 当所有元素都被抽取后返回-1
 func (d *Dice) Next() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	if d.total == 0 {
 		return -1
 	}
 
-	v := rand.Intn(d.total)
+	v := d.rander.Intn(d.total)
 	for i, w := range d.weights {
 		if v < w {
 			d.total -= w
@@ -101,6 +197,9 @@ func (d *Dice) Next() int {
 }
 
 func (d *Dice) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	d.total = 0
 	copy(d.weights, d.original)
 	for _, w := range d.weights {
@@ -2,6 +2,8 @@ package dice
 
 import (
 	"math"
+	"math/rand"
+	"sync"
 	"testing"
 )
 
@@ -164,3 +166,73 @@ func TestDice_DrawingOrder(t *testing.T) {
 		t.Errorf("权重分布异常：首次抽取次数未按权重大小排序")
 	}
 }
+
+func TestDice_SampleRate(t *testing.T) {
+	times := 100000
+	weights := []int{1, 2, 8, 0}
+	cnt := make(map[int]int)
+
+	d := NewDice(weights, WithSource(rand.NewSource(1)))
+	for i := 0; i < times; i++ {
+		cnt[d.Sample()]++
+	}
+	t.Log(cnt)
+
+	if cnt[-1] > 0 || cnt[3] > 0 {
+		t.Errorf("抽到了不应出现的结果: %v", cnt)
+	}
+
+	total := 11
+	for i, w := range weights {
+		expect := float64(times) * float64(w) / float64(total)
+		if math.Abs(float64(cnt[i])-expect) >= float64(times)*0.01 {
+			t.Errorf("索引 %d 抽中 %d 次，期望约 %.0f 次", i, cnt[i], expect)
+		}
+	}
+}
+
+func TestDice_SampleZeroWeights(t *testing.T) {
+	d := NewDice([]int{0, 0})
+	if result := d.Sample(); result != -1 {
+		t.Errorf("全零权重时期望返回 -1，实际返回 %d", result)
+	}
+}
+
+func TestDice_Seeded(t *testing.T) {
+	weights := []int{1, 3, 8, 20}
+	d1 := NewDice(weights, WithSource(rand.NewSource(42)))
+	d2 := NewDice(weights, WithSource(rand.NewSource(42)))
+
+	for i := 0; i < 100; i++ {
+		if a, b := d1.Sample(), d2.Sample(); a != b {
+			t.Fatalf("相同种子第 %d 次抽样结果不一致: %d != %d", i, a, b)
+		}
+		if a, b := d1.Next(), d2.Next(); a != b {
+			t.Fatalf("相同种子第 %d 次不放回抽样结果不一致: %d != %d", i, a, b)
+		}
+		if i%len(weights) == len(weights)-1 {
+			d1.Reset()
+			d2.Reset()
+		}
+	}
+}
+
+func TestDice_Concurrent(t *testing.T) {
+	d := NewDice([]int{1, 2, 3})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if n := d.Sample(); n < 0 || n > 2 {
+					t.Errorf("并发抽样得到非法结果 %d", n)
+				}
+				d.Next()
+				d.Reset()
+			}
+		}()
+	}
+	wg.Wait()
+}
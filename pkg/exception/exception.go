@@ -47,28 +47,79 @@ func (be *BeautyException) Message() string {
 }
 
 var (
-	ErrUnauthorized          = New(http.StatusUnauthorized, "登录过期或未登录")
-	ErrFileTooLarge          = New(http.StatusRequestEntityTooLarge, "文件大小超出预期")
-	ErrDetailNotFound        = New(http.StatusNotFound, "分析报告不存在")
-	ErrNotSpecifyDetail      = New(http.StatusBadRequest, "没有指定报告")
-	ErrUploadAvatar          = New(http.StatusBadRequest, "上传头像失败")
-	ErrGetAvatar             = New(http.StatusBadRequest, "获取头像失败")
-	ErrUploadImage           = New(http.StatusBadRequest, "上传图片失败")
-	ErrGetImage              = New(http.StatusBadRequest, "获取照片失败")
-	ErrWechatLogin           = New(http.StatusBadRequest, "微信登录失败")
-	ErrGetUserInfo           = New(http.StatusBadRequest, "获取用户信息失败")
-	ErrUpdateUsername        = New(http.StatusBadRequest, "更新用户姓名失败")
-	ErrUpdateGender          = New(http.StatusBadRequest, "更新性别失败")
-	ErrDoAnalysis            = New(http.StatusBadRequest, "分析图片失败")
-	ErrDoFavorite            = New(http.StatusBadRequest, "收藏失败")
-	ErrDoUnFavorite          = New(http.StatusBadRequest, "取消收藏失败")
-	ErrDeleteAnalysis        = New(http.StatusBadRequest, "删除分析报告失败")
-	ErrGetAnalysisDetails    = New(http.StatusBadRequest, "获取分析报告列表失败")
-	ErrGetFavoriteDetails    = New(http.StatusBadRequest, "获取收藏报告列表失败")
-	ErrShareExpires          = New(http.StatusBadRequest, "分享已过期")
-	ErrShareTokenInvalidates = New(http.StatusBadRequest, "分享链接异常")
-	ErrShareAnalysisDetail   = New(http.StatusBadRequest, "分享报告失败")
-	ErrGetShareDetail        = New(http.StatusBadRequest, "获取分享报告失败")
+	ErrUnauthorized             = New(http.StatusUnauthorized, "登录过期或未登录")
+	ErrFileTooLarge             = New(http.StatusRequestEntityTooLarge, "文件大小超出预期")
+	ErrDetailNotFound           = New(http.StatusNotFound, "分析报告不存在")
+	ErrNotSpecifyDetail         = New(http.StatusBadRequest, "没有指定报告")
+	ErrUploadAvatar             = New(http.StatusBadRequest, "上传头像失败")
+	ErrGetAvatar                = New(http.StatusBadRequest, "获取头像失败")
+	ErrUploadImage              = New(http.StatusBadRequest, "上传图片失败")
+	ErrGetImage                 = New(http.StatusBadRequest, "获取照片失败")
+	ErrWechatLogin              = New(http.StatusBadRequest, "微信登录失败")
+	ErrGetUserInfo              = New(http.StatusBadRequest, "获取用户信息失败")
+	ErrUpdateUsername           = New(http.StatusBadRequest, "更新用户姓名失败")
+	ErrUpdateGender             = New(http.StatusBadRequest, "更新性别失败")
+	ErrDoAnalysis               = New(http.StatusBadRequest, "分析图片失败")
+	ErrDoFavorite               = New(http.StatusBadRequest, "收藏失败")
+	ErrDoUnFavorite             = New(http.StatusBadRequest, "取消收藏失败")
+	ErrDeleteAnalysis           = New(http.StatusBadRequest, "删除分析报告失败")
+	ErrGetAnalysisDetails       = New(http.StatusBadRequest, "获取分析报告列表失败")
+	ErrGetFavoriteDetails       = New(http.StatusBadRequest, "获取收藏报告列表失败")
+	ErrShareExpires             = New(http.StatusBadRequest, "分享已过期")
+	ErrShareTokenInvalidates    = New(http.StatusBadRequest, "分享链接异常")
+	ErrShareAnalysisDetail      = New(http.StatusBadRequest, "分享报告失败")
+	ErrGetShareDetail           = New(http.StatusBadRequest, "获取分享报告失败")
+	ErrForbidden                = New(http.StatusForbidden, "没有操作权限")
+	ErrInvalidAnalystWeights    = New(http.StatusBadRequest, "分析器权重配置不合法")
+	ErrGetAnalystWeights        = New(http.StatusBadRequest, "获取分析器权重失败")
+	ErrUpdateAnalystWeights     = New(http.StatusBadRequest, "更新分析器权重失败")
+	ErrGetExperimentReport      = New(http.StatusBadRequest, "获取实验报告失败")
+	ErrInvalidImage             = New(http.StatusBadRequest, "不支持的图片格式")
+	ErrImageTooLarge            = New(http.StatusRequestEntityTooLarge, "图片分辨率过大，请压缩后重新上传")
+	ErrImageRejected            = New(http.StatusBadRequest, "图片未通过审核，请更换一张照片")
+	ErrImageExplicit            = New(http.StatusBadRequest, "图片包含违规内容，请更换一张照片")
+	ErrImageNoPerson            = New(http.StatusBadRequest, "没有识别到人像，请上传本人的正面照片")
+	ErrImageScreenshot          = New(http.StatusBadRequest, "请上传照片，不支持截图或拼图")
+	ErrImageLowQuality          = New(http.StatusBadRequest, "图片太小或内容为空，请更换一张照片")
+	ErrModerateImage            = New(http.StatusServiceUnavailable, "图片审核暂时不可用，请稍后重试")
+	ErrJobNotFound              = New(http.StatusNotFound, "分析任务不存在")
+	ErrSubmitAnalysisJob        = New(http.StatusBadRequest, "提交分析任务失败")
+	ErrGetAnalysisJob           = New(http.StatusBadRequest, "获取分析任务失败")
+	ErrInvalidAnalystType       = New(http.StatusBadRequest, "不支持的分析器类型")
+	ErrVersionNotFound          = New(http.StatusNotFound, "分析版本不存在")
+	ErrReanalyze                = New(http.StatusBadRequest, "重新分析失败")
+	ErrGetAnalysisVersions      = New(http.StatusBadRequest, "获取历史版本失败")
+	ErrSetPrimaryVersion        = New(http.StatusBadRequest, "设置主版本失败")
+	ErrCompareSameReport        = New(http.StatusBadRequest, "请选择两份不同的报告进行对比")
+	ErrCompareInput             = New(http.StatusBadRequest, "请上传两张照片或选择两份报告")
+	ErrComparisonNotFound       = New(http.StatusNotFound, "对比报告不存在")
+	ErrCompare                  = New(http.StatusBadRequest, "对比照片失败")
+	ErrGetComparisons           = New(http.StatusBadRequest, "获取对比报告失败")
+	ErrFavoriteComparison       = New(http.StatusBadRequest, "收藏对比报告失败")
+	ErrUnFavoriteComparison     = New(http.StatusBadRequest, "取消收藏对比报告失败")
+	ErrShareComparison          = New(http.StatusBadRequest, "分享对比报告失败")
+	ErrInvalidCursor            = New(http.StatusBadRequest, "分页参数无效，请从第一页重新加载")
+	ErrInvalidDetailQuery       = New(http.StatusBadRequest, "筛选条件无效")
+	ErrInvalidStatsPeriod       = New(http.StatusBadRequest, "统计周期无效")
+	ErrGetStats                 = New(http.StatusBadRequest, "获取统计数据失败")
+	ErrInvalidLeaderboardWindow = New(http.StatusBadRequest, "排行榜类型无效")
+	ErrJoinLeaderboard          = New(http.StatusBadRequest, "加入排行榜失败")
+	ErrLeaveLeaderboard         = New(http.StatusBadRequest, "退出排行榜失败")
+	ErrGetLeaderboard           = New(http.StatusBadRequest, "获取排行榜失败")
+	ErrGetLeaderboardRank       = New(http.StatusBadRequest, "获取排名失败")
+	ErrCollectionNotFound       = New(http.StatusNotFound, "收藏夹不存在")
+	ErrInvalidCollectionName    = New(http.StatusBadRequest, "收藏夹名称无效")
+	ErrCollectionNameExists     = New(http.StatusBadRequest, "收藏夹名称已存在")
+	ErrDefaultCollection        = New(http.StatusBadRequest, "默认收藏夹不能修改名称或删除")
+	ErrCoverNotInCollection     = New(http.StatusBadRequest, "封面报告不在收藏夹中")
+	ErrInvalidCollectionSort    = New(http.StatusBadRequest, "收藏夹排序无效，请刷新后重试")
+	ErrCreateCollection         = New(http.StatusBadRequest, "创建收藏夹失败")
+	ErrGetCollections           = New(http.StatusBadRequest, "获取收藏夹失败")
+	ErrUpdateCollection         = New(http.StatusBadRequest, "修改收藏夹失败")
+	ErrDeleteCollection         = New(http.StatusBadRequest, "删除收藏夹失败")
+	ErrSortCollections          = New(http.StatusBadRequest, "收藏夹排序失败")
+	ErrAddToCollection          = New(http.StatusBadRequest, "加入收藏夹失败")
+	ErrRemoveFromCollection     = New(http.StatusBadRequest, "移出收藏夹失败")
 )
 
 func CheckException(err error) bool {
@@ -0,0 +1,91 @@
+// File:		experiment.go
+// Created by:	Hoven
+// Created on:	2025-06-06
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package experiment
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+)
+
+// Variant 实验分组，一个分组对应一个配置好的分析器
+type Variant struct {
+	Name    string
+	Weight  int
+	Analyst analyst.Analyst
+}
+
+// Experiment 按用户固定分组的 A/B 实验
+//
+// 分组由实验 key 和用户 id 的哈希决定，同一用户在同一实验中永远落在同一个分组，
+// 修改实验 key 即可重新打散所有用户。
+type Experiment struct {
+	key      string
+	variants []*Variant
+	total    uint64
+}
+
+func NewExperiment(key string, variants ...*Variant) (*Experiment, error) {
+	if key == "" {
+		return nil, errors.New("missing experiment key")
+	}
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("experiment: %v has no variants", key)
+	}
+
+	e := &Experiment{key: key, variants: variants}
+	seen := make(map[string]bool, len(variants))
+	for _, v := range variants {
+		if v.Name == "" || seen[v.Name] {
+			return nil, fmt.Errorf("experiment: %v has empty or duplicate variant name: %q", key, v.Name)
+		}
+		if v.Weight <= 0 {
+			return nil, fmt.Errorf("experiment: %v variant: %v weight must be positive", key, v.Name)
+		}
+		if v.Analyst == nil {
+			return nil, fmt.Errorf("experiment: %v variant: %v missing analyst", key, v.Name)
+		}
+
+		seen[v.Name] = true
+		e.total += uint64(v.Weight)
+	}
+
+	return e, nil
+}
+
+func (e *Experiment) Key() string {
+	return e.key
+}
+
+func (e *Experiment) Variants() []*Variant {
+	return e.variants
+}
+
+// bucket 使用 sha256 而不是 fnv 等快速哈希，保证低位分布均匀，对小权重取模时各分组比例准确
+func (e *Experiment) bucket(userId int) uint64 {
+	sum := sha256.Sum256([]byte(e.key + ":" + strconv.Itoa(userId)))
+	return binary.BigEndian.Uint64(sum[:8]) % e.total
+}
+
+// Assign 返回用户所在的分组
+func (e *Experiment) Assign(userId int) *Variant {
+	bucket := e.bucket(userId)
+	for _, v := range e.variants {
+		if bucket < uint64(v.Weight) {
+			return v
+		}
+		bucket -= uint64(v.Weight)
+	}
+
+	return e.variants[len(e.variants)-1]
+}
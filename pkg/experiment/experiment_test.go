@@ -0,0 +1,74 @@
+package experiment
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+)
+
+type nopAnalyst struct{}
+
+func (nopAnalyst) Name() string             { return "nop" }
+func (nopAnalyst) Typ() analyst.AnalystType { return analyst.TypeMock }
+
+func (nopAnalyst) DoAnalysis(context.Context, string, string, []byte) (*analyst.Result, error) {
+	return &analyst.Result{}, nil
+}
+
+func TestAssign(t *testing.T) {
+	e, err := NewExperiment("prompt-v2",
+		&Variant{Name: "control", Weight: 3, Analyst: nopAnalyst{}},
+		&Variant{Name: "treatment", Weight: 1, Analyst: nopAnalyst{}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[string]int)
+	for userId := 1; userId <= 10000; userId++ {
+		v := e.Assign(userId)
+		if again := e.Assign(userId); again != v {
+			t.Fatalf("用户 %d 两次分组不一致: %v, %v", userId, v.Name, again.Name)
+		}
+		counts[v.Name]++
+	}
+
+	if ratio := float64(counts["treatment"]) / 10000; math.Abs(ratio-0.25) > 0.03 {
+		t.Errorf("分组比例偏离权重过多: %v", counts)
+	}
+}
+
+func TestAssignDependsOnKey(t *testing.T) {
+	variants := []*Variant{
+		{Name: "a", Weight: 1, Analyst: nopAnalyst{}},
+		{Name: "b", Weight: 1, Analyst: nopAnalyst{}},
+	}
+	e1, _ := NewExperiment("exp-1", variants...)
+	e2, _ := NewExperiment("exp-2", variants...)
+
+	var moved int
+	for userId := 1; userId <= 1000; userId++ {
+		if e1.Assign(userId) != e2.Assign(userId) {
+			moved++
+		}
+	}
+	if moved < 300 || moved > 700 {
+		t.Errorf("更换实验 key 后期望约一半用户换组，实际 %d", moved)
+	}
+}
+
+func TestNewExperimentInvalid(t *testing.T) {
+	cases := [][]*Variant{
+		nil,
+		{{Name: "a", Weight: 0, Analyst: nopAnalyst{}}},
+		{{Name: "a", Weight: 1, Analyst: nopAnalyst{}}, {Name: "a", Weight: 1, Analyst: nopAnalyst{}}},
+		{{Name: "a", Weight: 1}},
+	}
+	for i, variants := range cases {
+		if _, err := NewExperiment("exp", variants...); err == nil {
+			t.Errorf("case %d: 期望返回错误", i)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+// File:		fakebot.go
+// Created by:	Hoven
+// Created on:	2025-06-04
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+// Package fakebot 提供进程内的 ai-bot gRPC 服务，用于在测试中离线驱动所有依赖
+// DoubaoHandlerClient 的代码
+package fakebot
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	botpb "github.com/yazl-tech/ai-bot/pkg/proto/bot"
+	doubaopb "github.com/yazl-tech/ai-bot/pkg/proto/doubao"
+)
+
+const bufSize = 1 << 20
+
+// Reply 一次脚本化的回复，Err 不为空时返回错误
+type Reply struct {
+	Content string
+	Err     error
+	// Latency 返回这次回复前额外等待的时间
+	Latency time.Duration
+}
+
+// Handler 自定义处理请求，优先于脚本化回复和录制回放
+type Handler func(ctx context.Context, req *botpb.ChatRequest) (*botpb.ChatResponse, error)
+
+type Option func(*Server)
+
+// WithReplies 按顺序返回的回复，用完后再按录制回放处理
+func WithReplies(replies ...Reply) Option {
+	return func(s *Server) {
+		s.replies = append(s.replies, replies...)
+	}
+}
+
+func WithHandler(h Handler) Option {
+	return func(s *Server) {
+		s.handler = h
+	}
+}
+
+// WithLatency 每次请求返回前等待的时间，客户端超时或取消时提前返回
+func WithLatency(latency time.Duration) Option {
+	return func(s *Server) {
+		s.latency = latency
+	}
+}
+
+// WithError 所有请求都返回 err
+func WithError(err error) Option {
+	return func(s *Server) {
+		s.err = err
+		s.failures = -1
+	}
+}
+
+// WithFailures 前 n 次请求返回 err，之后正常处理
+func WithFailures(n int, err error) Option {
+	return func(s *Server) {
+		s.err = err
+		s.failures = n
+	}
+}
+
+// WithFixtures 从 dir 中回放录制的响应，未命中时转发给 upstream 并录制到 dir，upstream 为空时返回 NotFound
+func WithFixtures(dir string, upstream doubaopb.DoubaoHandlerClient) Option {
+	return func(s *Server) {
+		s.fixtures = newFixtureStore(dir)
+		s.upstream = upstream
+	}
+}
+
+// Server 基于 bufconn 的进程内 DoubaoHandler 服务
+type Server struct {
+	doubaopb.UnimplementedDoubaoHandlerServer
+
+	mu       sync.Mutex
+	replies  []Reply
+	handler  Handler
+	latency  time.Duration
+	err      error
+	failures int
+	fixtures *fixtureStore
+	upstream doubaopb.DoubaoHandlerClient
+	requests []*botpb.ChatRequest
+
+	lis  *bufconn.Listener
+	srv  *grpc.Server
+	conn *grpc.ClientConn
+}
+
+func NewServer(opts ...Option) (*Server, error) {
+	s := &Server{
+		lis: bufconn.Listen(bufSize),
+		srv: grpc.NewServer(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	doubaopb.RegisterDoubaoHandlerServer(s.srv, s)
+	go func() {
+		_ = s.srv.Serve(s.lis)
+	}()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///fakebot",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		s.srv.Stop()
+		return nil, errors.Wrap(err, "dial fakebot")
+	}
+	s.conn = conn
+
+	return s, nil
+}
+
+// TB Start 依赖的测试接口，testing.T 和 testing.B 都满足，避免非测试包引入 testing
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...any)
+	Cleanup(func())
+}
+
+// Start 启动服务并在测试结束时关闭
+func Start(tb TB, opts ...Option) *Server {
+	tb.Helper()
+
+	s, err := NewServer(opts...)
+	if err != nil {
+		tb.Fatalf("start fakebot: %v", err)
+	}
+	tb.Cleanup(s.Close)
+
+	return s
+}
+
+// Conn 连接到该服务的客户端连接，可以替代真实的 ai-bot 连接
+func (s *Server) Conn() grpc.ClientConnInterface {
+	return s.conn
+}
+
+func (s *Server) Client() doubaopb.DoubaoHandlerClient {
+	return doubaopb.NewDoubaoHandlerClient(s.conn)
+}
+
+// Push 追加脚本化回复
+func (s *Server) Push(replies ...Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replies = append(s.replies, replies...)
+}
+
+// Requests 返回收到的所有请求
+func (s *Server) Requests() []*botpb.ChatRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*botpb.ChatRequest(nil), s.requests...)
+}
+
+func (s *Server) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.requests)
+}
+
+func (s *Server) Close() {
+	s.conn.Close()
+	s.srv.Stop()
+}
+
+// TextResponse 构造只有一条文本回复的响应
+func TextResponse(content string) *botpb.ChatResponse {
+	return &botpb.ChatResponse{
+		Id: "fakebot",
+		Choices: []*botpb.Choice{
+			{
+				Message: &botpb.Message{
+					Role:    botpb.Message_assistant,
+					Content: &botpb.Message_StringContent{StringContent: content},
+				},
+				FinishReason: "stop",
+			},
+		},
+		Created: time.Now().Unix(),
+	}
+}
+
+// next 记录请求并取出本次要注入的错误和脚本化回复
+func (s *Server) next(req *botpb.ChatRequest) (*Reply, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, proto.Clone(req).(*botpb.ChatRequest))
+
+	// 注入的错误不消耗脚本化回复
+	if s.failures != 0 {
+		if s.failures > 0 {
+			s.failures--
+		}
+		return nil, s.err
+	}
+
+	if len(s.replies) == 0 {
+		return nil, nil
+	}
+	reply := s.replies[0]
+	s.replies = s.replies[1:]
+	return &reply, nil
+}
+
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func (s *Server) ChatCompletions(ctx context.Context, req *botpb.ChatRequest) (*botpb.ChatResponse, error) {
+	reply, injected := s.next(req)
+
+	latency := s.latency
+	if reply != nil {
+		latency += reply.Latency
+	}
+	if err := wait(ctx, latency); err != nil {
+		return nil, err
+	}
+
+	if injected != nil {
+		return nil, injected
+	}
+
+	if s.handler != nil {
+		return s.handler(ctx, req)
+	}
+
+	if reply != nil {
+		if reply.Err != nil {
+			return nil, reply.Err
+		}
+		return TextResponse(reply.Content), nil
+	}
+
+	if s.fixtures != nil {
+		return s.replay(ctx, req)
+	}
+
+	return nil, status.Error(codes.Unavailable, "fakebot: no reply scripted")
+}
+
+func (s *Server) replay(ctx context.Context, req *botpb.ChatRequest) (*botpb.ChatResponse, error) {
+	resp, err := s.fixtures.Load(req)
+	if err == nil {
+		return resp, nil
+	}
+	if !errors.Is(err, errFixtureNotFound) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if s.upstream == nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	resp, err = s.upstream.ChatCompletions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.fixtures.Save(req, resp); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return resp, nil
+}
@@ -0,0 +1,115 @@
+package fakebot
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	botpb "github.com/yazl-tech/ai-bot/pkg/proto/bot"
+)
+
+func chatRequest(content string) *botpb.ChatRequest {
+	return &botpb.ChatRequest{
+		Messages: []*botpb.Message{
+			{
+				Role:    botpb.Message_user,
+				Content: &botpb.Message_StringContent{StringContent: content},
+			},
+		},
+		Options: &botpb.ChatOptions{Model: "fake-model"},
+	}
+}
+
+func content(resp *botpb.ChatResponse) string {
+	return resp.GetChoices()[0].GetMessage().GetStringContent()
+}
+
+func TestScriptedReplies(t *testing.T) {
+	s := Start(t, WithReplies(
+		Reply{Content: "first"},
+		Reply{Err: status.Error(codes.ResourceExhausted, "quota")},
+	))
+	client := s.Client()
+
+	resp, err := client.ChatCompletions(context.Background(), chatRequest("a"))
+	if err != nil || content(resp) != "first" {
+		t.Fatalf("first reply: %v, %v", resp, err)
+	}
+
+	_, err = client.ChatCompletions(context.Background(), chatRequest("b"))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got: %v", err)
+	}
+
+	_, err = client.ChatCompletions(context.Background(), chatRequest("c"))
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable when script exhausted, got: %v", err)
+	}
+
+	requests := s.Requests()
+	if len(requests) != 3 || requests[1].GetMessages()[0].GetStringContent() != "b" {
+		t.Fatalf("unexpected recorded requests: %v", requests)
+	}
+}
+
+func TestInjectedFailuresAndLatency(t *testing.T) {
+	s := Start(t,
+		WithFailures(1, status.Error(codes.Internal, "boom")),
+		WithLatency(50*time.Millisecond),
+		WithReplies(Reply{Content: "too late"}),
+	)
+	client := s.Client()
+
+	if _, err := client.ChatCompletions(context.Background(), chatRequest("a")); status.Code(err) != codes.Internal {
+		t.Fatalf("expected injected Internal error, got: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.ChatCompletions(ctx, chatRequest("b")); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got: %v", err)
+	}
+
+	s.Push(Reply{Content: "late"})
+	start := time.Now()
+	resp, err := client.ChatCompletions(context.Background(), chatRequest("c"))
+	if err != nil || content(resp) != "late" {
+		t.Fatalf("expected late reply, got: %v, %v", resp, err)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Fatal("latency not injected")
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	upstream := Start(t, WithReplies(Reply{Content: "recorded"}))
+
+	recorder := Start(t, WithFixtures(dir, upstream.Client()))
+	resp, err := recorder.Client().ChatCompletions(context.Background(), chatRequest("a"))
+	if err != nil || content(resp) != "recorded" {
+		t.Fatalf("record: %v, %v", resp, err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 fixture, got: %d", len(entries))
+	}
+
+	replayer := Start(t, WithFixtures(dir, nil))
+	resp, err = replayer.Client().ChatCompletions(context.Background(), chatRequest("a"))
+	if err != nil || content(resp) != "recorded" {
+		t.Fatalf("replay: %v, %v", resp, err)
+	}
+	if upstream.Calls() != 1 {
+		t.Fatalf("replay should not reach upstream, calls: %d", upstream.Calls())
+	}
+
+	if _, err := replayer.Client().ChatCompletions(context.Background(), chatRequest("b")); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for unrecorded request, got: %v", err)
+	}
+}
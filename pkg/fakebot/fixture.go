@@ -0,0 +1,90 @@
+// File:		fixture.go
+// Created by:	Hoven
+// Created on:	2025-06-04
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package fakebot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	botpb "github.com/yazl-tech/ai-bot/pkg/proto/bot"
+)
+
+var errFixtureNotFound = errors.New("fixture not found")
+
+// fixtureStore 以请求内容的哈希为文件名保存响应，文件内容为 protojson 格式的 ChatResponse
+type fixtureStore struct {
+	dir string
+}
+
+func newFixtureStore(dir string) *fixtureStore {
+	return &fixtureStore{dir: dir}
+}
+
+// FixtureKey 请求的确定性哈希，相同的模型、提示词和图片得到相同的 key
+func FixtureKey(req *botpb.ChatRequest) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", errors.Wrap(err, "marshal request")
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+func (fs *fixtureStore) path(req *botpb.ChatRequest) (string, error) {
+	key, err := FixtureKey(req)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(fs.dir, key+".json"), nil
+}
+
+func (fs *fixtureStore) Load(req *botpb.ChatRequest) (*botpb.ChatResponse, error) {
+	path, err := fs.path(req)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %v", errFixtureNotFound, filepath.Base(path))
+	} else if err != nil {
+		return nil, errors.Wrap(err, "read fixture")
+	}
+
+	resp := &botpb.ChatResponse{}
+	if err := protojson.Unmarshal(b, resp); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal fixture: %v", path)
+	}
+	return resp, nil
+}
+
+func (fs *fixtureStore) Save(req *botpb.ChatRequest, resp *botpb.ChatResponse) error {
+	path, err := fs.path(req)
+	if err != nil {
+		return err
+	}
+
+	b, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(resp)
+	if err != nil {
+		return errors.Wrap(err, "marshal fixture")
+	}
+
+	if err := os.MkdirAll(fs.dir, 0o755); err != nil {
+		return errors.Wrap(err, "create fixture dir")
+	}
+	return errors.Wrap(os.WriteFile(path, b, 0o644), "write fixture")
+}
@@ -0,0 +1,217 @@
+// File:		imageproc.go
+// Created by:	Hoven
+// Created on:	2025-05-31
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package imageproc
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+
+	_ "image/gif"
+
+	"github.com/pkg/errors"
+	"golang.org/x/image/draw"
+
+	_ "golang.org/x/image/webp"
+)
+
+type Format string
+
+const (
+	FormatJPEG Format = "jpeg"
+	FormatPNG  Format = "png"
+	FormatGIF  Format = "gif"
+	FormatWEBP Format = "webp"
+)
+
+var (
+	ErrNotImage      = errors.New("not a supported image")
+	ErrImageTooLarge = errors.New("image exceeds max pixels")
+)
+
+// Sniff 根据文件头的魔数判断真实的图片格式，与文件名无关
+func Sniff(b []byte) (Format, error) {
+	switch {
+	case bytes.HasPrefix(b, []byte{0xFF, 0xD8, 0xFF}):
+		return FormatJPEG, nil
+	case bytes.HasPrefix(b, []byte("\x89PNG\r\n\x1a\n")):
+		return FormatPNG, nil
+	case bytes.HasPrefix(b, []byte("GIF87a")), bytes.HasPrefix(b, []byte("GIF89a")):
+		return FormatGIF, nil
+	case len(b) >= 12 && string(b[:4]) == "RIFF" && string(b[8:12]) == "WEBP":
+		return FormatWEBP, nil
+	default:
+		return "", ErrNotImage
+	}
+}
+
+// Image 预处理后的图片，只会是 jpeg 或 png
+type Image struct {
+	Data   []byte
+	Format Format
+	Width  int
+	Height int
+}
+
+func (i *Image) MimeType() string {
+	return "image/" + string(i.Format)
+}
+
+func (i *Image) Ext() string {
+	if i.Format == FormatPNG {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+type ProcessorOption func(*Processor)
+
+// WithMaxDimension 长边超过 n 像素的图片等比缩小到 n，n 为 0 时不缩放
+func WithMaxDimension(n int) ProcessorOption {
+	return func(p *Processor) {
+		p.maxDimension = n
+	}
+}
+
+// WithMaxPixels 文件头声明的宽高乘积超过 n 的图片直接拒绝，避免解码超大图片耗尽内存，n 为 0 时不限制
+func WithMaxPixels(n int) ProcessorOption {
+	return func(p *Processor) {
+		p.maxPixels = n
+	}
+}
+
+func WithJpegQuality(quality int) ProcessorOption {
+	return func(p *Processor) {
+		p.jpegQuality = quality
+	}
+}
+
+// Processor 在图片存储和分析之前统一处理用户上传的图片
+//
+// 依次完成：按魔数识别格式并拒绝非图片、按文件头的宽高拒绝像素数过多的图片、按最大边长缩放、按 EXIF 方向旋转，
+// 最后重新编码。重新编码会丢弃包括 GPS 在内的全部 EXIF 元数据，
+// png 保持 png 以保留透明度，其余格式统一输出 jpeg。
+type Processor struct {
+	maxDimension int
+	maxPixels    int
+	jpegQuality  int
+}
+
+func NewProcessor(opts ...ProcessorOption) *Processor {
+	p := &Processor{
+		maxDimension: 2048,
+		maxPixels:    40_000_000,
+		jpegQuality:  90,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *Processor) Process(b []byte) (*Image, error) {
+	format, err := Sniff(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// 完整解码前先只读取文件头中的宽高，解码需要的内存与像素数成正比
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.Wrapf(ErrNotImage, "decode %v config: %v", format, err)
+	}
+	if p.maxPixels > 0 && int64(cfg.Width)*int64(cfg.Height) > int64(p.maxPixels) {
+		return nil, errors.Wrapf(ErrImageTooLarge, "%v %dx%d", format, cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.Wrapf(ErrNotImage, "decode %v: %v", format, err)
+	}
+
+	orientation := orientationNormal
+	if format == FormatJPEG {
+		orientation = jpegOrientation(b)
+	}
+
+	img = orient(resize(img, p.maxDimension), orientation)
+
+	out := FormatJPEG
+	if format == FormatPNG {
+		out = FormatPNG
+	} else if format != FormatJPEG {
+		img = flatten(img)
+	}
+
+	return p.encode(img, out)
+}
+
+// Variant 将 Process 输出的图片缩小到长边不超过 maxDimension，格式保持不变
+func (p *Processor) Variant(src *Image, maxDimension int) (*Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(src.Data))
+	if err != nil {
+		return nil, errors.Wrapf(ErrNotImage, "decode %v: %v", src.Format, err)
+	}
+
+	return p.encode(resize(img, maxDimension), src.Format)
+}
+
+func (p *Processor) encode(img image.Image, format Format) (*Image, error) {
+	var (
+		err error
+		buf = new(bytes.Buffer)
+	)
+	switch format {
+	case FormatPNG:
+		err = png.Encode(buf, img)
+	default:
+		format = FormatJPEG
+		err = jpeg.Encode(buf, img, &jpeg.Options{Quality: p.jpegQuality})
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "encode %v", format)
+	}
+
+	bounds := img.Bounds()
+	return &Image{
+		Data:   buf.Bytes(),
+		Format: format,
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+	}, nil
+}
+
+// resize 等比缩小到长边不超过 maxDimension，maxDimension 为 0 或图片本身更小时原样返回
+func resize(img image.Image, maxDimension int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	long := max(w, h)
+	if maxDimension <= 0 || long <= maxDimension {
+		return img
+	}
+
+	nw := max(1, w*maxDimension/long)
+	nh := max(1, h*maxDimension/long)
+
+	dst := image.NewRGBA(image.Rect(0, 0, nw, nh))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// flatten 将可能带透明度的图片铺在白色背景上，避免输出 jpeg 时透明区域变黑
+func flatten(img image.Image) image.Image {
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Over)
+	return dst
+}
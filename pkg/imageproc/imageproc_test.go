@@ -0,0 +1,155 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// exifSegment 构造只包含 Orientation 和一个 GPS IFD 指针的 APP1 段
+func exifSegment(orientation uint16) []byte {
+	tiff := new(bytes.Buffer)
+	tiff.WriteString("MM")
+	binary.Write(tiff, binary.BigEndian, uint16(42))
+	binary.Write(tiff, binary.BigEndian, uint32(8))
+	binary.Write(tiff, binary.BigEndian, uint16(2))
+	// Orientation, SHORT, 1
+	binary.Write(tiff, binary.BigEndian, []uint16{0x0112, 3})
+	binary.Write(tiff, binary.BigEndian, uint32(1))
+	binary.Write(tiff, binary.BigEndian, []uint16{orientation, 0})
+	// GPSInfo, LONG, 1
+	binary.Write(tiff, binary.BigEndian, []uint16{0x8825, 4})
+	binary.Write(tiff, binary.BigEndian, []uint32{1, 0})
+	binary.Write(tiff, binary.BigEndian, uint32(0))
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	seg := []byte{0xFF, markerAPP1}
+	seg = binary.BigEndian.AppendUint16(seg, uint16(len(payload)+2))
+	return append(seg, payload...)
+}
+
+func testJpeg(t *testing.T, w, h int, orientation uint16) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 255 / w), G: uint8(y * 255 / h), B: 128, A: 255})
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	b := buf.Bytes()
+	if orientation == 0 {
+		return b
+	}
+	return append(append(append([]byte{}, b[:2]...), exifSegment(orientation)...), b[2:]...)
+}
+
+func TestProcess_Orientation(t *testing.T) {
+	b := testJpeg(t, 40, 20, orientationRotate90)
+	if o := jpegOrientation(b); o != orientationRotate90 {
+		t.Fatalf("期望读到方向 6，实际 %d", o)
+	}
+
+	img, err := NewProcessor().Process(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Width != 20 || img.Height != 40 {
+		t.Errorf("旋转后尺寸应为 20x40，实际 %dx%d", img.Width, img.Height)
+	}
+	if bytes.Contains(img.Data, []byte("Exif")) {
+		t.Error("输出中仍包含 EXIF 数据")
+	}
+	if img.Format != FormatJPEG || jpegOrientation(img.Data) != orientationNormal {
+		t.Errorf("输出格式或方向不符合预期: %v", img.Format)
+	}
+
+	// 顺时针旋转 90 度后，原图左下角（绿色最强）应位于左上角
+	decoded, err := jpeg.Decode(bytes.NewReader(img.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, g, _, _ := decoded.At(0, 0).RGBA()
+	_, g2, _, _ := decoded.At(19, 0).RGBA()
+	if g>>8 < 200 || g2>>8 > 60 {
+		t.Errorf("旋转方向不正确: 左上角 G=%d，右上角 G=%d", g>>8, g2>>8)
+	}
+}
+
+func TestProcess_Resize(t *testing.T) {
+	img, err := NewProcessor(WithMaxDimension(50)).Process(testJpeg(t, 200, 100, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Width != 50 || img.Height != 25 {
+		t.Errorf("缩放后尺寸应为 50x25，实际 %dx%d", img.Width, img.Height)
+	}
+}
+
+func TestVariant(t *testing.T) {
+	p := NewProcessor()
+	src, err := p.Process(testJpeg(t, 300, 200, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	thumb, err := p.Variant(src, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thumb.Width != 60 || thumb.Height != 40 || thumb.Format != src.Format {
+		t.Errorf("缩略图不符合预期: %v %dx%d", thumb.Format, thumb.Width, thumb.Height)
+	}
+}
+
+func TestProcess_NotImage(t *testing.T) {
+	for _, b := range [][]byte{
+		[]byte("%PDF-1.4 not an image"),
+		[]byte("\xFF\xD8\xFF broken jpeg"),
+		nil,
+	} {
+		if _, err := NewProcessor().Process(b); !errors.Is(err, ErrNotImage) {
+			t.Errorf("期望返回 ErrNotImage，实际 %v", err)
+		}
+	}
+}
+
+// hugePng 返回一张 1x1 的 png，但 IHDR 中声明的宽高为 w x h
+func hugePng(t *testing.T, w, h uint32) []byte {
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+
+	// 8 字节签名之后是 IHDR：长度(4) + 类型(4) + 宽(4) + 高(4) + 其余 5 字节 + CRC(4)
+	b := buf.Bytes()
+	binary.BigEndian.PutUint32(b[16:20], w)
+	binary.BigEndian.PutUint32(b[20:24], h)
+	binary.BigEndian.PutUint32(b[29:33], crc32.ChecksumIEEE(b[12:29]))
+	return b
+}
+
+func TestProcess_MaxPixels(t *testing.T) {
+	b := hugePng(t, 50000, 50000)
+	if _, err := NewProcessor().Process(b); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("期望返回 ErrImageTooLarge，实际 %v", err)
+	}
+
+	if _, err := NewProcessor(WithMaxPixels(799)).Process(testJpeg(t, 40, 20, 0)); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("期望 800 像素的图片超过 799 的限制，实际 %v", err)
+	}
+
+	if _, err := NewProcessor(WithMaxPixels(800)).Process(testJpeg(t, 40, 20, 0)); err != nil {
+		t.Fatalf("期望 800 像素的图片不超过限制，实际 %v", err)
+	}
+}
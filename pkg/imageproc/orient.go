@@ -0,0 +1,168 @@
+// File:		orient.go
+// Created by:	Hoven
+// Created on:	2025-05-31
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package imageproc
+
+import (
+	"encoding/binary"
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// EXIF Orientation 取值，见 EXIF 2.3 规范 4.6.4 节
+const (
+	orientationNormal     = 1
+	orientationFlipH      = 2
+	orientationRotate180  = 3
+	orientationFlipV      = 4
+	orientationTranspose  = 5
+	orientationRotate90   = 6
+	orientationTransverse = 7
+	orientationRotate270  = 8
+)
+
+const (
+	markerSOI  = 0xD8
+	markerAPP1 = 0xE1
+	markerSOS  = 0xDA
+
+	tagOrientation = 0x0112
+)
+
+// jpegOrientation 从 jpeg 的 APP1 段读取 EXIF 方向，没有或无法解析时返回 orientationNormal
+func jpegOrientation(b []byte) int {
+	if len(b) < 4 || b[0] != 0xFF || b[1] != markerSOI {
+		return orientationNormal
+	}
+
+	for i := 2; i+4 <= len(b); {
+		if b[i] != 0xFF {
+			return orientationNormal
+		}
+		marker := b[i+1]
+		if marker == markerSOS {
+			return orientationNormal
+		}
+
+		size := int(binary.BigEndian.Uint16(b[i+2 : i+4]))
+		end := i + 2 + size
+		if size < 2 || end > len(b) {
+			return orientationNormal
+		}
+
+		if marker == markerAPP1 {
+			if o, ok := exifOrientation(b[i+4 : end]); ok {
+				return o
+			}
+		}
+		i = end
+	}
+
+	return orientationNormal
+}
+
+// exifOrientation 解析 APP1 段内容，只读取 IFD0 中的 Orientation 标签
+func exifOrientation(seg []byte) (int, bool) {
+	const header = "Exif\x00\x00"
+	if len(seg) < len(header)+8 || string(seg[:len(header)]) != header {
+		return 0, false
+	}
+	tiff := seg[len(header):]
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, false
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd+2 > len(tiff) {
+		return 0, false
+	}
+
+	count := int(order.Uint16(tiff[ifd : ifd+2]))
+	for n := 0; n < count; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 0, false
+		}
+		if order.Uint16(tiff[entry:entry+2]) != tagOrientation {
+			continue
+		}
+
+		o := int(order.Uint16(tiff[entry+8 : entry+10]))
+		if o < orientationNormal || o > orientationRotate270 {
+			return 0, false
+		}
+		return o, true
+	}
+
+	return 0, false
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+	return dst
+}
+
+// orient 将图片按 EXIF 方向变换为正常朝向
+func orient(img image.Image, orientation int) image.Image {
+	if orientation == orientationNormal {
+		return img
+	}
+
+	src := toRGBA(img)
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+
+	dw, dh := w, h
+	if orientation >= orientationTranspose {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case orientationFlipH:
+				sx, sy = w-1-x, y
+			case orientationRotate180:
+				sx, sy = w-1-x, h-1-y
+			case orientationFlipV:
+				sx, sy = x, h-1-y
+			case orientationTranspose:
+				sx, sy = y, x
+			case orientationRotate90:
+				sx, sy = y, h-1-x
+			case orientationTransverse:
+				sx, sy = w-1-y, h-1-x
+			case orientationRotate270:
+				sx, sy = w-1-y, x
+			default:
+				sx, sy = x, y
+			}
+
+			si := src.PixOffset(sx, sy)
+			di := dst.PixOffset(x, y)
+			copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,60 @@
+// File:		analyst.go
+// Created by:	Hoven
+// Created on:	2025-06-05
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+)
+
+var _ analyst.Analyst = (*Analyst)(nil)
+
+// Analyst 统计被装饰分析器的调用次数、结果、耗时和分数分布
+type Analyst struct {
+	analyst.Analyst
+}
+
+func NewAnalyst(a analyst.Analyst) *Analyst {
+	return &Analyst{Analyst: a}
+}
+
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.Is(err, context.DeadlineExceeded):
+		return OutcomeTimeout
+	case errors.Is(err, context.Canceled):
+		return OutcomeCanceled
+	default:
+		return OutcomeError
+	}
+}
+
+func (a *Analyst) DoAnalysis(ctx context.Context, imageName, imageUrl string, image []byte) (*analyst.Result, error) {
+	start := time.Now()
+	ret, err := a.Analyst.DoAnalysis(ctx, imageName, imageUrl, image)
+	observeStep(StepAnalyst, a.Name(), start)
+
+	// 超时可能由 ctx 截止触发而错误本身未包装 ctx 的错误
+	result := outcome(err)
+	if result == OutcomeError && ctx.Err() != nil {
+		result = outcome(ctx.Err())
+	}
+	analysisRequests.WithLabelValues(a.Name(), a.Typ().String(), result).Inc()
+
+	if err == nil && ret != nil {
+		analysisScore.WithLabelValues(ret.AnalystType.String()).Observe(float64(ret.Score))
+	}
+
+	return ret, err
+}
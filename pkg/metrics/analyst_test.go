@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
+)
+
+type stubAnalyst struct {
+	name  string
+	score int
+	err   error
+	delay time.Duration
+}
+
+func (s *stubAnalyst) Name() string             { return s.name }
+func (s *stubAnalyst) Typ() analyst.AnalystType { return analyst.TypeHeuristic }
+
+func (s *stubAnalyst) DoAnalysis(ctx context.Context, _, _ string, _ []byte) (*analyst.Result, error) {
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, errors.New("request aborted")
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &analyst.Result{AnalystType: analyst.TypeHeuristic, Score: s.score}, nil
+}
+
+func TestAnalyst(t *testing.T) {
+	ok := NewAnalyst(&stubAnalyst{name: "ok", score: 88})
+	failed := NewAnalyst(&stubAnalyst{name: "failed", err: errors.New("boom")})
+	slow := NewAnalyst(&stubAnalyst{name: "slow", delay: time.Second})
+
+	if _, err := ok.DoAnalysis(context.Background(), "", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = failed.DoAnalysis(context.Background(), "", "", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _ = slow.DoAnalysis(ctx, "", "", nil)
+
+	for _, c := range []struct {
+		name    string
+		outcome string
+	}{
+		{"ok", OutcomeSuccess},
+		{"failed", OutcomeError},
+		{"slow", OutcomeTimeout},
+	} {
+		if v := testutil.ToFloat64(analysisRequests.WithLabelValues(c.name, "heuristic", c.outcome)); v != 1 {
+			t.Errorf("analyst: %v outcome: %v, 期望计数 1，实际 %v", c.name, c.outcome, v)
+		}
+	}
+
+	if n := testutil.CollectAndCount(analysisScore); n != 1 {
+		t.Errorf("期望只记录成功结果的分数，实际 %d 个序列", n)
+	}
+}
@@ -0,0 +1,34 @@
+// File:		grpc.go
+// Created by:	Hoven
+// Created on:	2025-06-05
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package metrics
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var _ grpc.ClientConnInterface = (*ClientConn)(nil)
+
+// ClientConn 按状态码统计经由该连接发出的 unary 请求
+type ClientConn struct {
+	grpc.ClientConnInterface
+	service string
+}
+
+func NewClientConn(service string, conn grpc.ClientConnInterface) *ClientConn {
+	return &ClientConn{ClientConnInterface: conn, service: service}
+}
+
+func (c *ClientConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	err := c.ClientConnInterface.Invoke(ctx, method, args, reply, opts...)
+	grpcClientRequests.WithLabelValues(c.service, method, status.Code(err).String()).Inc()
+	return err
+}
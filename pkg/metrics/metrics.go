@@ -0,0 +1,126 @@
+// File:		metrics.go
+// Created by:	Hoven
+// Created on:	2025-06-05
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+// Package metrics 以装饰器的方式为分析链路上的各个接口采集 Prometheus 指标
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-puzzles/puzzles/cores"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "beauty"
+
+// 链路中各步骤的名称，对应 step_duration_seconds 的 step 标签
+const (
+	StepUpload   = "upload"
+	StepAnalyst  = "analyst"
+	StepRepo     = "repo"
+	StepPresign  = "presign"
+	StepModerate = "moderate"
+)
+
+// 分析请求的结果，对应 analysis_requests_total 的 outcome 标签
+const (
+	OutcomeSuccess  = "success"
+	OutcomeError    = "error"
+	OutcomeTimeout  = "timeout"
+	OutcomeCanceled = "canceled"
+)
+
+var (
+	analysisRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "analysis_requests_total",
+		Help:      "Analysis requests by analyst and outcome.",
+	}, []string{"analyst", "analyst_type", "outcome"})
+
+	stepDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "step_duration_seconds",
+		Help:      "Latency of analysis pipeline steps.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 40, 60},
+	}, []string{"step", "operation"})
+
+	analysisScore = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "analysis_score",
+		Help:      "Distribution of scores by analyst type.",
+		Buckets:   prometheus.LinearBuckets(60, 5, 9),
+	}, []string{"analyst_type"})
+
+	moderationRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "moderation_requests_total",
+		Help:      "Image moderation requests by moderator and verdict, failed requests are counted as error.",
+	}, []string{"moderator", "verdict"})
+
+	ossErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "oss_errors_total",
+		Help:      "Failed object storage operations.",
+	}, []string{"operation"})
+
+	grpcClientRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_client_requests_total",
+		Help:      "Outgoing gRPC requests by target service, method and status code.",
+	}, []string{"service", "method", "code"})
+)
+
+func observeStep(step, operation string, start time.Time) {
+	stepDuration.WithLabelValues(step, operation).Observe(time.Since(start).Seconds())
+}
+
+// Handler 以 Prometheus 文本格式输出所有指标
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// WithMetricsServer 在独立的内部端口上提供 /metrics，不经过对外的 API 端口，port 为 0 时不启动
+func WithMetricsServer(port int) cores.ServiceOption {
+	if port == 0 {
+		return func(*cores.Options) {}
+	}
+
+	return cores.WithDaemonNameWorker("metricsServer", func(ctx context.Context) error {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			return errors.Wrap(err, "listen metrics port")
+		}
+		return serve(ctx, lis)
+	})
+}
+
+// serve 在 lis 上提供 /metrics，ctx 结束时关闭
+func serve(ctx context.Context, lis net.Listener) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.Serve(lis); !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, "serve metrics")
+	}
+	return nil
+}
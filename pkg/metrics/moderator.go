@@ -0,0 +1,41 @@
+// File:		moderator.go
+// Created by:	Hoven
+// Created on:	2025-06-05
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/moderator"
+)
+
+var _ moderator.Moderator = (*Moderator)(nil)
+
+// Moderator 统计被装饰审核器的耗时和结论，出错的请求按 error 计数
+type Moderator struct {
+	moderator.Moderator
+}
+
+func NewModerator(m moderator.Moderator) *Moderator {
+	return &Moderator{Moderator: m}
+}
+
+func (m *Moderator) Moderate(ctx context.Context, image []byte) (*moderator.Result, error) {
+	start := time.Now()
+	ret, err := m.Moderator.Moderate(ctx, image)
+	observeStep(StepModerate, m.Name(), start)
+
+	verdict := OutcomeError
+	if err == nil {
+		verdict = string(ret.Verdict)
+	}
+	moderationRequests.WithLabelValues(m.Name(), verdict).Inc()
+
+	return ret, err
+}
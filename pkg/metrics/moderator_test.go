@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/yazl-tech/beauty-rating-server/pkg/moderator"
+)
+
+type stubModerator struct {
+	name string
+	err  error
+}
+
+func (s *stubModerator) Name() string { return s.name }
+
+func (s *stubModerator) Moderate(context.Context, []byte) (*moderator.Result, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &moderator.Result{Verdict: moderator.VerdictReject, Category: moderator.CategoryExplicit}, nil
+}
+
+func TestModerator(t *testing.T) {
+	_, _ = NewModerator(&stubModerator{name: "reject"}).Moderate(context.Background(), nil)
+	_, _ = NewModerator(&stubModerator{name: "failed", err: errors.New("boom")}).Moderate(context.Background(), nil)
+
+	if v := testutil.ToFloat64(moderationRequests.WithLabelValues("reject", "reject")); v != 1 {
+		t.Errorf("期望 reject 结论计数 1，实际 %v", v)
+	}
+	if v := testutil.ToFloat64(moderationRequests.WithLabelValues("failed", OutcomeError)); v != 1 {
+		t.Errorf("期望出错计数 1，实际 %v", v)
+	}
+}
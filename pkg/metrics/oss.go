@@ -0,0 +1,81 @@
+// File:		oss.go
+// Created by:	Hoven
+// Created on:	2025-06-05
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/oss"
+)
+
+var _ oss.IOSS = (*OSS)(nil)
+
+// OSS 统计对象存储的上传、签名耗时和失败次数
+type OSS struct {
+	oss oss.IOSS
+}
+
+func NewOSS(o oss.IOSS) *OSS {
+	return &OSS{oss: o}
+}
+
+func countOSSError(operation string, err error) {
+	if err != nil {
+		ossErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+func (o *OSS) UploadFile(ctx context.Context, size int64, dir, objName string, obj io.Reader) (string, error) {
+	start := time.Now()
+	uri, err := o.oss.UploadFile(ctx, size, dir, objName, obj)
+	observeStep(StepUpload, "UploadFile", start)
+	countOSSError("UploadFile", err)
+
+	return uri, err
+}
+
+func (o *OSS) PutFile(ctx context.Context, size int64, objName string, obj io.Reader) error {
+	start := time.Now()
+	err := o.oss.PutFile(ctx, size, objName, obj)
+	observeStep(StepUpload, "PutFile", start)
+	countOSSError("PutFile", err)
+
+	return err
+}
+
+func (o *OSS) GetFile(ctx context.Context, objName string, w io.Writer) error {
+	err := o.oss.GetFile(ctx, objName, w)
+	countOSSError("GetFile", err)
+
+	return err
+}
+
+func (o *OSS) RemoveFile(ctx context.Context, objName string) error {
+	err := o.oss.RemoveFile(ctx, objName)
+	countOSSError("RemoveFile", err)
+
+	return err
+}
+
+func (o *OSS) PresignedGetObject(ctx context.Context, objName string, expires time.Duration) (*url.URL, error) {
+	start := time.Now()
+	u, err := o.oss.PresignedGetObject(ctx, objName, expires)
+	observeStep(StepPresign, "PresignedGetObject", start)
+	countOSSError("PresignedGetObject", err)
+
+	return u, err
+}
+
+func (o *OSS) ProxyPresignedGetObject(objName string, rw http.ResponseWriter, req *http.Request) {
+	o.oss.ProxyPresignedGetObject(objName, rw, req)
+}
@@ -0,0 +1,222 @@
+// File:		repo.go
+// Created by:	Hoven
+// Created on:	2025-06-05
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+)
+
+var _ analysis.Repo = (*AnalysisRepo)(nil)
+
+// AnalysisRepo 统计分析结果仓储各方法的耗时
+type AnalysisRepo struct {
+	repo analysis.Repo
+}
+
+func NewAnalysisRepo(repo analysis.Repo) *AnalysisRepo {
+	return &AnalysisRepo{repo: repo}
+}
+
+func (r *AnalysisRepo) CreateAnalysisDetail(ctx context.Context, detail *analysis.AnalysisDetail) error {
+	defer observeStep(StepRepo, "CreateAnalysisDetail", time.Now())
+	return r.repo.CreateAnalysisDetail(ctx, detail)
+}
+
+func (r *AnalysisRepo) GetUserDetails(ctx context.Context, userId int, query *analysis.DetailQuery) ([]*analysis.AnalysisDetail, error) {
+	defer observeStep(StepRepo, "GetUserDetails", time.Now())
+	return r.repo.GetUserDetails(ctx, userId, query)
+}
+
+func (r *AnalysisRepo) GetUserDetail(ctx context.Context, userId, detailId int) (*analysis.AnalysisDetail, error) {
+	defer observeStep(StepRepo, "GetUserDetail", time.Now())
+	return r.repo.GetUserDetail(ctx, userId, detailId)
+}
+
+func (r *AnalysisRepo) GetDetailByJob(ctx context.Context, jobId int) (*analysis.AnalysisDetail, error) {
+	defer observeStep(StepRepo, "GetDetailByJob", time.Now())
+	return r.repo.GetDetailByJob(ctx, jobId)
+}
+
+func (r *AnalysisRepo) GetDetail(ctx context.Context, detailId int) (*analysis.AnalysisDetail, error) {
+	defer observeStep(StepRepo, "GetDetail", time.Now())
+	return r.repo.GetDetail(ctx, detailId)
+}
+
+func (r *AnalysisRepo) GetDetails(ctx context.Context, detailIds []int) ([]*analysis.AnalysisDetail, error) {
+	defer observeStep(StepRepo, "GetDetails", time.Now())
+	return r.repo.GetDetails(ctx, detailIds)
+}
+
+func (r *AnalysisRepo) CheckDetailExists(ctx context.Context, userId, detailId int) bool {
+	defer observeStep(StepRepo, "CheckDetailExists", time.Now())
+	return r.repo.CheckDetailExists(ctx, userId, detailId)
+}
+
+func (r *AnalysisRepo) UpdateAnalysisDetail(ctx context.Context, detail *analysis.AnalysisDetail) error {
+	defer observeStep(StepRepo, "UpdateAnalysisDetail", time.Now())
+	return r.repo.UpdateAnalysisDetail(ctx, detail)
+}
+
+func (r *AnalysisRepo) DeleteAnalysisDetail(ctx context.Context, userId, detailId int) error {
+	defer observeStep(StepRepo, "DeleteAnalysisDetail", time.Now())
+	return r.repo.DeleteAnalysisDetail(ctx, userId, detailId)
+}
+
+func (r *AnalysisRepo) GetScoreRank(ctx context.Context, scope *analysis.RankScope, score int) (*analysis.ScoreRank, error) {
+	defer observeStep(StepRepo, "GetScoreRank", time.Now())
+	return r.repo.GetScoreRank(ctx, scope, score)
+}
+
+func (r *AnalysisRepo) UpdatePercentile(ctx context.Context, detailId, percentile int) error {
+	defer observeStep(StepRepo, "UpdatePercentile", time.Now())
+	return r.repo.UpdatePercentile(ctx, detailId, percentile)
+}
+
+func (r *AnalysisRepo) GetUnrankedDetails(ctx context.Context, afterId, limit int) ([]*analysis.AnalysisDetail, error) {
+	defer observeStep(StepRepo, "GetUnrankedDetails", time.Now())
+	return r.repo.GetUnrankedDetails(ctx, afterId, limit)
+}
+
+func (r *AnalysisRepo) GetLatestDetailByHash(ctx context.Context, imageHash string, userId int, experiment, variant string, since time.Time) (*analysis.AnalysisDetail, error) {
+	defer observeStep(StepRepo, "GetLatestDetailByHash", time.Now())
+	return r.repo.GetLatestDetailByHash(ctx, imageHash, userId, experiment, variant, since)
+}
+
+func (r *AnalysisRepo) GetDetailsWithoutVariants(ctx context.Context, afterId, limit int) ([]*analysis.AnalysisDetail, error) {
+	defer observeStep(StepRepo, "GetDetailsWithoutVariants", time.Now())
+	return r.repo.GetDetailsWithoutVariants(ctx, afterId, limit)
+}
+
+func (r *AnalysisRepo) MarkVariantsReady(ctx context.Context, detailId int) error {
+	defer observeStep(StepRepo, "MarkVariantsReady", time.Now())
+	return r.repo.MarkVariantsReady(ctx, detailId)
+}
+
+func (r *AnalysisRepo) MarkShared(ctx context.Context, detailId int) error {
+	defer observeStep(StepRepo, "MarkShared", time.Now())
+	return r.repo.MarkShared(ctx, detailId)
+}
+
+func (r *AnalysisRepo) GetVariantStats(ctx context.Context, experiment string) ([]*analysis.VariantStats, error) {
+	defer observeStep(StepRepo, "GetVariantStats", time.Now())
+	return r.repo.GetVariantStats(ctx, experiment)
+}
+
+func (r *AnalysisRepo) CreatePrimaryVersion(ctx context.Context, detail *analysis.AnalysisDetail, original, version *analysis.AnalysisVersion) error {
+	defer observeStep(StepRepo, "CreatePrimaryVersion", time.Now())
+	return r.repo.CreatePrimaryVersion(ctx, detail, original, version)
+}
+
+func (r *AnalysisRepo) GetVersions(ctx context.Context, reportId int) ([]*analysis.AnalysisVersion, error) {
+	defer observeStep(StepRepo, "GetVersions", time.Now())
+	return r.repo.GetVersions(ctx, reportId)
+}
+
+func (r *AnalysisRepo) CreateComparison(ctx context.Context, comparison *analysis.Comparison) error {
+	defer observeStep(StepRepo, "CreateComparison", time.Now())
+	return r.repo.CreateComparison(ctx, comparison)
+}
+
+func (r *AnalysisRepo) GetComparison(ctx context.Context, compareId int) (*analysis.Comparison, error) {
+	defer observeStep(StepRepo, "GetComparison", time.Now())
+	return r.repo.GetComparison(ctx, compareId)
+}
+
+func (r *AnalysisRepo) GetUserComparison(ctx context.Context, userId, compareId int) (*analysis.Comparison, error) {
+	defer observeStep(StepRepo, "GetUserComparison", time.Now())
+	return r.repo.GetUserComparison(ctx, userId, compareId)
+}
+
+func (r *AnalysisRepo) GetUserComparisons(ctx context.Context, userId int, query *analysis.ComparisonQuery) ([]*analysis.Comparison, error) {
+	defer observeStep(StepRepo, "GetUserComparisons", time.Now())
+	return r.repo.GetUserComparisons(ctx, userId, query)
+}
+
+func (r *AnalysisRepo) UpdateComparison(ctx context.Context, comparison *analysis.Comparison) error {
+	defer observeStep(StepRepo, "UpdateComparison", time.Now())
+	return r.repo.UpdateComparison(ctx, comparison)
+}
+
+func (r *AnalysisRepo) GetUserStats(ctx context.Context, userId int) (*analysis.UserStats, error) {
+	defer observeStep(StepRepo, "GetUserStats", time.Now())
+	return r.repo.GetUserStats(ctx, userId)
+}
+
+func (r *AnalysisRepo) GetScoreTrend(ctx context.Context, userId int, period analysis.StatsPeriod, since time.Time) ([]*analysis.TrendPoint, error) {
+	defer observeStep(StepRepo, "GetScoreTrend", time.Now())
+	return r.repo.GetScoreTrend(ctx, userId, period, since)
+}
+
+func (r *AnalysisRepo) GetLabelAverages(ctx context.Context, userId int) ([]*analysis.LabelAverage, error) {
+	defer observeStep(StepRepo, "GetLabelAverages", time.Now())
+	return r.repo.GetLabelAverages(ctx, userId)
+}
+
+func (r *AnalysisRepo) GetTopTags(ctx context.Context, userId int, limit int) ([]*analysis.TagCount, error) {
+	defer observeStep(StepRepo, "GetTopTags", time.Now())
+	return r.repo.GetTopTags(ctx, userId, limit)
+}
+
+func (r *AnalysisRepo) CreateCollection(ctx context.Context, collection *analysis.Collection) error {
+	defer observeStep(StepRepo, "CreateCollection", time.Now())
+	return r.repo.CreateCollection(ctx, collection)
+}
+
+func (r *AnalysisRepo) GetUserCollection(ctx context.Context, userId, collectionId int) (*analysis.Collection, error) {
+	defer observeStep(StepRepo, "GetUserCollection", time.Now())
+	return r.repo.GetUserCollection(ctx, userId, collectionId)
+}
+
+func (r *AnalysisRepo) GetDefaultCollection(ctx context.Context, userId int) (*analysis.Collection, error) {
+	defer observeStep(StepRepo, "GetDefaultCollection", time.Now())
+	return r.repo.GetDefaultCollection(ctx, userId)
+}
+
+func (r *AnalysisRepo) GetUserCollections(ctx context.Context, userId int) ([]*analysis.Collection, error) {
+	defer observeStep(StepRepo, "GetUserCollections", time.Now())
+	return r.repo.GetUserCollections(ctx, userId)
+}
+
+func (r *AnalysisRepo) UpdateCollection(ctx context.Context, collection *analysis.Collection) error {
+	defer observeStep(StepRepo, "UpdateCollection", time.Now())
+	return r.repo.UpdateCollection(ctx, collection)
+}
+
+func (r *AnalysisRepo) SortCollections(ctx context.Context, userId int, positions map[int]int) error {
+	defer observeStep(StepRepo, "SortCollections", time.Now())
+	return r.repo.SortCollections(ctx, userId, positions)
+}
+
+func (r *AnalysisRepo) DeleteCollection(ctx context.Context, collectionId int) error {
+	defer observeStep(StepRepo, "DeleteCollection", time.Now())
+	return r.repo.DeleteCollection(ctx, collectionId)
+}
+
+func (r *AnalysisRepo) AddCollectionItem(ctx context.Context, item *analysis.CollectionItem) error {
+	defer observeStep(StepRepo, "AddCollectionItem", time.Now())
+	return r.repo.AddCollectionItem(ctx, item)
+}
+
+func (r *AnalysisRepo) RemoveCollectionItem(ctx context.Context, collectionId, reportId int) error {
+	defer observeStep(StepRepo, "RemoveCollectionItem", time.Now())
+	return r.repo.RemoveCollectionItem(ctx, collectionId, reportId)
+}
+
+func (r *AnalysisRepo) GetCollectionSummaries(ctx context.Context, collectionIds []int) ([]*analysis.CollectionSummary, error) {
+	defer observeStep(StepRepo, "GetCollectionSummaries", time.Now())
+	return r.repo.GetCollectionSummaries(ctx, collectionIds)
+}
+
+func (r *AnalysisRepo) FilterCollectedReports(ctx context.Context, collectionId int, reportIds []int) ([]int, error) {
+	defer observeStep(StepRepo, "FilterCollectedReports", time.Now())
+	return r.repo.FilterCollectedReports(ctx, collectionId, reportIds)
+}
@@ -0,0 +1,124 @@
+// File:		ai.go
+// Created by:	Hoven
+// Created on:	2025-06-02
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package ai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/yazl-tech/beauty-rating-server/pkg/moderator"
+
+	botpb "github.com/yazl-tech/ai-bot/pkg/proto/bot"
+	doubaopb "github.com/yazl-tech/ai-bot/pkg/proto/doubao"
+)
+
+var _ moderator.Moderator = (*AiModerator)(nil)
+
+const moderationPrompt = `你是一名图片审核员，负责判断用户上传的图片是否适合进行颜值评分。
+请按以下规则给出结论：
+1. 含有色情、裸露、暴力、血腥等违规内容：verdict 为 reject，category 为 explicit。
+2. 图片中没有真实人物的面部（风景、动物、物品、卡通、表情包等）：verdict 为 reject，category 为 no_person。
+3. 手机或电脑截图、聊天记录、海报等非照片内容：verdict 为 reject，category 为 screenshot。
+4. 无法确定是否符合以上情况：verdict 为 review，category 为最接近的一项或 other。
+5. 其余情况：verdict 为 allow，category 为空字符串。
+reason 用一句中文简要说明理由。
+只输出如下 JSON，不要输出任何其他内容：
+{"verdict": "allow", "category": "", "reason": ""}`
+
+// AiModerator 通过 ai-bot 的多模态模型审核图片，能够识别违规内容和非人像图片
+type AiModerator struct {
+	model        string
+	doubaoClient doubaopb.DoubaoHandlerClient
+}
+
+func NewAiModerator(model string, doubaoClient doubaopb.DoubaoHandlerClient) *AiModerator {
+	return &AiModerator{model: model, doubaoClient: doubaoClient}
+}
+
+func (m *AiModerator) Name() string {
+	return "AiModerator"
+}
+
+func (m *AiModerator) imageUrl(image []byte) string {
+	mimeType := http.DetectContentType(image)
+	if !strings.HasPrefix(mimeType, "image/") {
+		mimeType = "image/png"
+	}
+	return fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(image))
+}
+
+func (m *AiModerator) packRequest(image []byte) *botpb.ChatRequest {
+	return &botpb.ChatRequest{
+		Messages: []*botpb.Message{
+			{
+				Role: botpb.Message_system,
+				Content: &botpb.Message_StringContent{
+					StringContent: moderationPrompt,
+				},
+			},
+			{
+				Role: botpb.Message_user,
+				Content: &botpb.Message_TypeContent{
+					TypeContent: &botpb.TypeMessage{
+						Type: botpb.TypeMessage_image,
+						ImageUrl: &botpb.TypeMessage_ImageUrl{
+							Url: m.imageUrl(image),
+						},
+					},
+				},
+			},
+		},
+		Options: &botpb.ChatOptions{
+			Model:       m.model,
+			Temperature: 0,
+		},
+	}
+}
+
+// parseResult 取出模型输出中的 JSON，未知的结论按待复核处理
+func parseResult(content string) (*moderator.Result, error) {
+	start := strings.IndexByte(content, '{')
+	if start == -1 {
+		return nil, fmt.Errorf("no JSON found in response: %v", content)
+	}
+
+	ret := new(moderator.Result)
+	if err := json.NewDecoder(strings.NewReader(content[start:])).Decode(ret); err != nil {
+		return nil, errors.Wrap(err, "decode moderation result")
+	}
+
+	switch ret.Verdict {
+	case moderator.VerdictAllow:
+		ret.Category = moderator.CategoryNone
+	case moderator.VerdictReject, moderator.VerdictReview:
+	default:
+		ret.Verdict = moderator.VerdictReview
+	}
+
+	return ret, nil
+}
+
+func (m *AiModerator) Moderate(ctx context.Context, image []byte) (*moderator.Result, error) {
+	resp, err := m.doubaoClient.ChatCompletions(ctx, m.packRequest(image))
+	if err != nil {
+		return nil, err
+	}
+
+	choices := resp.GetChoices()
+	if len(choices) == 0 {
+		return nil, fmt.Errorf("empty choices")
+	}
+
+	return parseResult(choices[0].GetMessage().GetStringContent())
+}
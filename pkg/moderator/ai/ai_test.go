@@ -0,0 +1,52 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/fakebot"
+	"github.com/yazl-tech/beauty-rating-server/pkg/moderator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAiModerator(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		content  string
+		verdict  moderator.Verdict
+		category moderator.Category
+	}{
+		{"allow", `{"verdict": "allow", "category": "other", "reason": "正常人像"}`, moderator.VerdictAllow, moderator.CategoryNone},
+		{"reject", "```json\n{\"verdict\": \"reject\", \"category\": \"no_person\", \"reason\": \"风景照\"}\n```", moderator.VerdictReject, moderator.CategoryNoPerson},
+		{"review", `{"verdict": "review", "category": "screenshot", "reason": "疑似截图"}`, moderator.VerdictReview, moderator.CategoryScreenshot},
+		{"unknown verdict", `{"verdict": "maybe", "category": "other", "reason": ""}`, moderator.VerdictReview, moderator.CategoryOther},
+	} {
+		bot := fakebot.Start(t, fakebot.WithReplies(fakebot.Reply{Content: c.content}))
+
+		ret, err := NewAiModerator("fake-model", bot.Client()).Moderate(context.Background(), []byte("\x89PNG\r\n\x1a\n"))
+		if err != nil {
+			t.Fatalf("%v: %v", c.name, err)
+		}
+		if ret.Verdict != c.verdict || ret.Category != c.category {
+			t.Errorf("%v: 期望 %v/%v，实际 %v/%v", c.name, c.verdict, c.category, ret.Verdict, ret.Category)
+		}
+	}
+}
+
+func TestAiModerator_Error(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		opt  fakebot.Option
+	}{
+		{"unavailable", fakebot.WithError(status.Error(codes.Unavailable, "ai-bot down"))},
+		{"no json", fakebot.WithReplies(fakebot.Reply{Content: "无法判断"})},
+		{"invalid json", fakebot.WithReplies(fakebot.Reply{Content: `{"verdict": `})},
+	} {
+		bot := fakebot.Start(t, c.opt)
+
+		if _, err := NewAiModerator("fake-model", bot.Client()).Moderate(context.Background(), nil); err == nil {
+			t.Errorf("%v: 期望返回错误", c.name)
+		}
+	}
+}
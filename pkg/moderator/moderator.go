@@ -0,0 +1,110 @@
+// File:		moderator.go
+// Created by:	Hoven
+// Created on:	2025-06-02
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package moderator
+
+import (
+	"context"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/pkg/errors"
+)
+
+type Verdict string
+
+const (
+	VerdictAllow  Verdict = "allow"
+	VerdictReject Verdict = "reject"
+	// VerdictReview 不确定是否合规，照常评分但标记为待人工复核
+	VerdictReview Verdict = "review"
+)
+
+type Category string
+
+const (
+	CategoryNone       Category = ""
+	CategoryExplicit   Category = "explicit"
+	CategoryNoPerson   Category = "no_person"
+	CategoryScreenshot Category = "screenshot"
+	CategoryLowQuality Category = "low_quality"
+	CategoryOther      Category = "other"
+)
+
+type Result struct {
+	Verdict  Verdict  `json:"verdict"`
+	Category Category `json:"category"`
+	Reason   string   `json:"reason"`
+	// Moderator 给出结论的审核器名称
+	Moderator string `json:"-"`
+}
+
+func Allow() *Result {
+	return &Result{Verdict: VerdictAllow}
+}
+
+type Moderator interface {
+	Name() string
+	Moderate(ctx context.Context, image []byte) (*Result, error)
+}
+
+var _ Moderator = (*Chain)(nil)
+
+type ChainOption func(*Chain)
+
+// WithFailClosed 任一审核器出错时返回错误而不是跳过，由调用方拒绝这张图片
+func WithFailClosed() ChainOption {
+	return func(c *Chain) {
+		c.failClosed = true
+	}
+}
+
+// Chain 依次执行多个审核器，遇到第一个拒绝立即返回；待复核的结论会保留到最后，
+// 以便后续审核器仍有机会拒绝。单个审核器出错时默认跳过（fail open），避免 ai-bot 故障导致无法评分，
+// 配置 WithFailClosed 后改为返回错误（fail closed）。
+type Chain struct {
+	moderators []Moderator
+	failClosed bool
+}
+
+func NewChain(moderators []Moderator, opts ...ChainOption) *Chain {
+	c := &Chain{moderators: moderators}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Chain) Name() string {
+	return "ModeratorChain"
+}
+
+func (c *Chain) Moderate(ctx context.Context, image []byte) (*Result, error) {
+	ret := Allow()
+	for _, m := range c.moderators {
+		r, err := m.Moderate(ctx, image)
+		if err != nil && c.failClosed {
+			plog.Errorc(ctx, "moderator: %v failed, reject: %v", m.Name(), err)
+			return nil, errors.Wrapf(err, "moderator: %v", m.Name())
+		} else if err != nil {
+			plog.Errorc(ctx, "moderator: %v failed, skip: %v", m.Name(), err)
+			continue
+		}
+		r.Moderator = m.Name()
+
+		switch r.Verdict {
+		case VerdictReject:
+			return r, nil
+		case VerdictReview:
+			if ret.Verdict == VerdictAllow {
+				ret = r
+			}
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,81 @@
+package moderator
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type stubModerator struct {
+	name  string
+	ret   *Result
+	err   error
+	calls int
+}
+
+func (s *stubModerator) Name() string { return s.name }
+
+func (s *stubModerator) Moderate(context.Context, []byte) (*Result, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	ret := *s.ret
+	return &ret, nil
+}
+
+func TestChain(t *testing.T) {
+	allow := &stubModerator{name: "allow", ret: Allow()}
+	review := &stubModerator{name: "review", ret: &Result{Verdict: VerdictReview, Category: CategoryOther}}
+	reject := &stubModerator{name: "reject", ret: &Result{Verdict: VerdictReject, Category: CategoryExplicit}}
+	after := &stubModerator{name: "after", ret: Allow()}
+
+	ret, err := NewChain([]Moderator{allow, review, reject, after}).Moderate(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Verdict != VerdictReject || ret.Moderator != "reject" {
+		t.Errorf("期望被 reject 审核器拒绝，实际 %+v", ret)
+	}
+	if after.calls != 0 {
+		t.Errorf("拒绝后不应继续执行后续审核器，实际执行 %d 次", after.calls)
+	}
+
+	ret, err = NewChain([]Moderator{allow, review, after}).Moderate(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Verdict != VerdictReview || ret.Moderator != "review" || after.calls != 1 {
+		t.Errorf("期望保留待复核结论并执行完所有审核器，实际 %+v, after 执行 %d 次", ret, after.calls)
+	}
+
+	ret, err = NewChain(nil).Moderate(context.Background(), nil)
+	if err != nil || ret.Verdict != VerdictAllow {
+		t.Errorf("空审核链期望放行，实际 %+v, %v", ret, err)
+	}
+}
+
+func TestChain_Failure(t *testing.T) {
+	boom := errors.New("ai-bot down")
+	failed := &stubModerator{name: "failed", err: boom}
+	reject := &stubModerator{name: "reject", ret: &Result{Verdict: VerdictReject, Category: CategoryNoPerson}}
+
+	ret, err := NewChain([]Moderator{failed}).Moderate(context.Background(), nil)
+	if err != nil || ret.Verdict != VerdictAllow {
+		t.Errorf("fail open 期望跳过出错的审核器并放行，实际 %+v, %v", ret, err)
+	}
+
+	ret, err = NewChain([]Moderator{failed, reject}).Moderate(context.Background(), nil)
+	if err != nil || ret.Verdict != VerdictReject {
+		t.Errorf("fail open 期望后续审核器仍能拒绝，实际 %+v, %v", ret, err)
+	}
+
+	reject.calls = 0
+	_, err = NewChain([]Moderator{failed, reject}, WithFailClosed()).Moderate(context.Background(), nil)
+	if !errors.Is(err, boom) {
+		t.Errorf("fail closed 期望返回审核器的错误，实际 %v", err)
+	}
+	if reject.calls != 0 {
+		t.Errorf("fail closed 出错后不应继续执行后续审核器，实际执行 %d 次", reject.calls)
+	}
+}
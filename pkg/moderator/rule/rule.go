@@ -0,0 +1,152 @@
+// File:		rule.go
+// Created by:	Hoven
+// Created on:	2025-06-02
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package rule
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"math"
+
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/pkg/errors"
+	"github.com/yazl-tech/beauty-rating-server/pkg/moderator"
+)
+
+var _ moderator.Moderator = (*RuleModerator)(nil)
+
+// sampleStep 统计像素时的采样间隔，图片已经过预处理，长边不会过大
+const sampleStep = 2
+
+type RuleOption func(*RuleModerator)
+
+func WithMinDimension(n int) RuleOption {
+	return func(r *RuleModerator) {
+		r.minDimension = n
+	}
+}
+
+// WithAspectRange 宽高比超出 [lo, hi] 时视为截图或拼图
+func WithAspectRange(lo, hi float64) RuleOption {
+	return func(r *RuleModerator) {
+		r.minAspect = lo
+		r.maxAspect = hi
+	}
+}
+
+// RuleModerator 不依赖外部服务的本地审核器，只能识别明显不是人像照片的图片
+//
+// 规则依次为：短边过小或画面几乎纯色（亮度标准差过低）直接拒绝；宽高比过于狭长
+// （长截图、拼图）直接拒绝；相邻像素亮度相同的比例过高时（界面截图、表情包的大片纯色区域）
+// 标记为待复核。识别违规内容依赖 AI 审核器。
+type RuleModerator struct {
+	minDimension int
+	minAspect    float64
+	maxAspect    float64
+	minContrast  float64
+	maxFlatRatio float64
+}
+
+func NewRuleModerator(opts ...RuleOption) *RuleModerator {
+	r := &RuleModerator{
+		minDimension: 128,
+		minAspect:    1.0 / 3,
+		maxAspect:    3,
+		minContrast:  8,
+		maxFlatRatio: 0.6,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *RuleModerator) Name() string {
+	return "RuleModerator"
+}
+
+// stats 返回亮度标准差和相邻像素亮度几乎相同的比例
+func stats(img image.Image) (contrast, flatRatio float64) {
+	bounds := img.Bounds()
+
+	var (
+		sum, sumSq float64
+		n, flat    int
+	)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += sampleStep {
+		prev := -1.0
+		for x := bounds.Min.X; x < bounds.Max.X; x += sampleStep {
+			r, g, b, _ := img.At(x, y).RGBA()
+			luma := (0.299*float64(r>>8) + 0.587*float64(g>>8) + 0.114*float64(b>>8))
+
+			sum += luma
+			sumSq += luma * luma
+			n++
+			if prev >= 0 && math.Abs(luma-prev) < 1 {
+				flat++
+			}
+			prev = luma
+		}
+	}
+	if n == 0 {
+		return 0, 1
+	}
+
+	mean := sum / float64(n)
+	return math.Sqrt(math.Max(0, sumSq/float64(n)-mean*mean)), float64(flat) / float64(n)
+}
+
+func (r *RuleModerator) Moderate(_ context.Context, b []byte) (*moderator.Result, error) {
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.Wrap(err, "decodeImage")
+	}
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if min(w, h) < r.minDimension {
+		return &moderator.Result{
+			Verdict:  moderator.VerdictReject,
+			Category: moderator.CategoryLowQuality,
+			Reason:   fmt.Sprintf("image too small: %dx%d", w, h),
+		}, nil
+	}
+
+	aspect := float64(w) / float64(h)
+	if aspect < r.minAspect || aspect > r.maxAspect {
+		return &moderator.Result{
+			Verdict:  moderator.VerdictReject,
+			Category: moderator.CategoryScreenshot,
+			Reason:   fmt.Sprintf("aspect ratio out of range: %.2f", aspect),
+		}, nil
+	}
+
+	contrast, flatRatio := stats(img)
+	if contrast < r.minContrast {
+		return &moderator.Result{
+			Verdict:  moderator.VerdictReject,
+			Category: moderator.CategoryLowQuality,
+			Reason:   fmt.Sprintf("image is almost blank, contrast: %.1f", contrast),
+		}, nil
+	}
+
+	if flatRatio > r.maxFlatRatio {
+		return &moderator.Result{
+			Verdict:  moderator.VerdictReview,
+			Category: moderator.CategoryScreenshot,
+			Reason:   fmt.Sprintf("too many flat regions: %.2f", flatRatio),
+		}, nil
+	}
+
+	return moderator.Allow(), nil
+}
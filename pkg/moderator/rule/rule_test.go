@@ -0,0 +1,62 @@
+package rule
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"github.com/yazl-tech/beauty-rating-server/pkg/moderator"
+)
+
+func encode(t *testing.T, img image.Image) []byte {
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func noise(w, h int) *image.RGBA {
+	r := rand.New(rand.NewSource(1))
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(r.Intn(256)), G: uint8(r.Intn(256)), B: uint8(r.Intn(256)), A: 255})
+		}
+	}
+	return img
+}
+
+func TestRuleModerator(t *testing.T) {
+	flat := noise(300, 400)
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 300; x++ {
+			flat.Set(x, y, color.White)
+		}
+	}
+
+	for _, c := range []struct {
+		name     string
+		img      image.Image
+		verdict  moderator.Verdict
+		category moderator.Category
+	}{
+		{"photo", noise(300, 400), moderator.VerdictAllow, moderator.CategoryNone},
+		{"tiny", noise(64, 64), moderator.VerdictReject, moderator.CategoryLowQuality},
+		{"long screenshot", noise(200, 1000), moderator.VerdictReject, moderator.CategoryScreenshot},
+		{"blank", image.NewGray(image.Rect(0, 0, 300, 400)), moderator.VerdictReject, moderator.CategoryLowQuality},
+		{"flat", flat, moderator.VerdictReview, moderator.CategoryScreenshot},
+	} {
+		ret, err := NewRuleModerator().Moderate(context.Background(), encode(t, c.img))
+		if err != nil {
+			t.Fatalf("%v: %v", c.name, err)
+		}
+		if ret.Verdict != c.verdict || ret.Category != c.category {
+			t.Errorf("%v: 期望 %v/%v，实际 %v/%v (%v)", c.name, c.verdict, c.category, ret.Verdict, ret.Category, ret.Reason)
+		}
+	}
+}
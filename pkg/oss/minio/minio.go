@@ -107,6 +107,15 @@ func (m *MinioOss) UploadFile(ctx context.Context, size int64, dir, objName stri
 	return rawObjName, nil
 }
 
+func (m *MinioOss) PutFile(ctx context.Context, size int64, objName string, obj io.Reader) error {
+	_, err := m.client.PutObject(ctx, m.Bucket, objName, obj, size, minio.PutObjectOptions{})
+	if err != nil {
+		return errors.Wrap(err, "putMinio")
+	}
+
+	return nil
+}
+
 func (m *MinioOss) GetFile(ctx context.Context, objName string, w io.Writer) error {
 	object, err := m.client.GetObject(ctx, m.Bucket, objName, minio.GetObjectOptions{})
 	if err != nil {
@@ -122,6 +131,15 @@ func (m *MinioOss) GetFile(ctx context.Context, objName string, w io.Writer) err
 	return nil
 }
 
+func (m *MinioOss) RemoveFile(ctx context.Context, objName string) error {
+	err := m.client.RemoveObject(ctx, m.Bucket, objName, minio.RemoveObjectOptions{})
+	if err != nil {
+		return errors.Wrap(err, "removeMinioObject")
+	}
+
+	return nil
+}
+
 func (m *MinioOss) PresignedGetObject(ctx context.Context, objName string, expires time.Duration) (*url.URL, error) {
 	u, err := m.client.PresignedGetObject(ctx, m.Bucket, objName, expires, url.Values{})
 	if err != nil {
@@ -18,7 +18,10 @@ import (
 
 type IOSS interface {
 	UploadFile(ctx context.Context, size int64, dir, objName string, obj io.Reader) (uri string, err error)
+	// PutFile 以指定的完整对象名上传，已存在时覆盖
+	PutFile(ctx context.Context, size int64, objName string, obj io.Reader) error
 	GetFile(ctx context.Context, objName string, w io.Writer) error
+	RemoveFile(ctx context.Context, objName string) error
 	PresignedGetObject(ctx context.Context, objName string, expires time.Duration) (*url.URL, error)
 	ProxyPresignedGetObject(objName string, rw http.ResponseWriter, req *http.Request)
 }
@@ -0,0 +1,65 @@
+// File:		admin.go
+// Created by:	Hoven
+// Created on:	2025-05-22
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package service
+
+import (
+	"context"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"github.com/yazl-tech/beauty-rating-server/service/dto"
+)
+
+func (bs *BeautyRatingService) GetAnalystWeights(ctx context.Context) (*dto.AnalystWeightsResponse, error) {
+	operator, err := bs.userSrv.GetUserInfo(ctx)
+	if err != nil {
+		plog.Errorc(ctx, "get operator info failed: %v", err)
+		return nil, exception.ParseError(err, exception.ErrGetUserInfo)
+	}
+
+	weights, err := bs.adminSrv.GetAnalystWeights(ctx, operator)
+	if err != nil {
+		plog.Errorc(ctx, "get analyst weights failed: %v", err)
+		return nil, exception.ParseError(err, exception.ErrGetAnalystWeights)
+	}
+
+	return &dto.AnalystWeightsResponse{AnalystWeights: weights}, nil
+}
+
+func (bs *BeautyRatingService) UpdateAnalystWeights(ctx context.Context, req *dto.UpdateAnalystWeightsRequest) (*dto.AnalystWeightsResponse, error) {
+	operator, err := bs.userSrv.GetUserInfo(ctx)
+	if err != nil {
+		plog.Errorc(ctx, "get operator info failed: %v", err)
+		return nil, exception.ParseError(err, exception.ErrGetUserInfo)
+	}
+
+	weights, err := bs.adminSrv.UpdateAnalystWeights(ctx, operator, req.Weights)
+	if err != nil {
+		plog.Errorc(ctx, "update analyst weights failed: %v", err)
+		return nil, exception.ParseError(err, exception.ErrUpdateAnalystWeights)
+	}
+
+	return &dto.AnalystWeightsResponse{AnalystWeights: weights}, nil
+}
+
+func (bs *BeautyRatingService) GetExperimentReport(ctx context.Context, req *dto.GetExperimentReportRequest) (*dto.ExperimentReportResponse, error) {
+	operator, err := bs.userSrv.GetUserInfo(ctx)
+	if err != nil {
+		plog.Errorc(ctx, "get operator info failed: %v", err)
+		return nil, exception.ParseError(err, exception.ErrGetUserInfo)
+	}
+
+	report, err := bs.adminSrv.GetExperimentReport(ctx, operator, req.Experiment)
+	if err != nil {
+		plog.Errorc(ctx, "get experiment: %v report failed: %v", req.Experiment, err)
+		return nil, exception.ParseError(err, exception.ErrGetExperimentReport)
+	}
+
+	return &dto.ExperimentReportResponse{ExperimentReport: report}, nil
+}
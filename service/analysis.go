@@ -15,6 +15,8 @@ import (
 
 	"github.com/go-puzzles/puzzles/plog"
 	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"github.com/yazl-tech/beauty-rating-server/domain/user"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
 	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
 	"github.com/yazl-tech/beauty-rating-server/service/dto"
 )
@@ -47,42 +49,122 @@ func (bs *BeautyRatingService) ShareAnalysisDetail(ctx context.Context, userId,
 	}, nil
 }
 
-func (bs *BeautyRatingService) GetImage(ctx context.Context, imageId string, rw http.ResponseWriter, req *http.Request) {
-	bs.analysisSrv.GetAnalysisImage(ctx, imageId, rw, req)
+func (bs *BeautyRatingService) GetImage(ctx context.Context, imageId, size string, rw http.ResponseWriter, req *http.Request) {
+	bs.analysisSrv.GetAnalysisImage(ctx, imageId, analysis.ParseImageSize(size), rw, req)
 }
 
-func (bs *BeautyRatingService) GetFavoriteDetails(ctx context.Context, userId int) (*dto.GetDetailsResponse, error) {
-	resp, err := bs.analysisSrv.GetFavoriteDetails(ctx, userId)
+// detailQuery 将请求参数转换为报告列表的查询条件
+func detailQuery(req *dto.GetDetailsRequest) (*analysis.DetailQuery, error) {
+	query := &analysis.DetailQuery{
+		SortBy:   analysis.DetailSort(req.SortBy),
+		MinScore: req.MinScore,
+		MaxScore: req.MaxScore,
+		Since:    req.Since,
+		Tag:      req.Tag,
+		Limit:    req.Limit,
+	}
+
+	switch req.Order {
+	case "", "desc":
+	case "asc":
+		query.Asc = true
+	default:
+		return nil, exception.ErrInvalidDetailQuery
+	}
+
+	if !req.Until.IsZero() {
+		query.Until = req.Until.AddDate(0, 0, 1)
+	}
+
+	if req.Analyst != "" {
+		t, err := analyst.ParseAnalystType(req.Analyst)
+		if err != nil {
+			return nil, exception.ErrInvalidAnalystType
+		}
+		typ := int(t)
+		query.AnalystType = &typ
+	}
+
+	if req.Cursor != "" {
+		after, err := analysis.ParseDetailCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query.After = after
+	}
+
+	return query, nil
+}
+
+func (bs *BeautyRatingService) GetFavoriteDetails(ctx context.Context, userId int, req *dto.GetDetailsRequest) (*dto.GetDetailsResponse, error) {
+	query, err := detailQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := bs.analysisSrv.GetFavoriteDetails(ctx, userId, query)
 	if err != nil {
 		plog.Errorc(ctx, "get favorite details failed: %v", err)
 		return nil, exception.ParseError(err, exception.ErrGetFavoriteDetails)
 	}
 
 	return &dto.GetDetailsResponse{
-		Details: resp,
+		Details:    page.Details,
+		NextCursor: page.NextCursor,
 	}, nil
 }
 
-func (bs *BeautyRatingService) GetAnalysisDetails(ctx context.Context, userId int) (*dto.GetDetailsResponse, error) {
-	resp, err := bs.analysisSrv.GetAnalysisDetials(ctx, userId)
+func (bs *BeautyRatingService) GetAnalysisDetails(ctx context.Context, userId int, req *dto.GetDetailsRequest) (*dto.GetDetailsResponse, error) {
+	query, err := detailQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := bs.analysisSrv.GetAnalysisDetials(ctx, userId, query)
 	if err != nil {
 		plog.Errorc(ctx, "get analysis details failed: %v", err)
 		return nil, exception.ParseError(err, exception.ErrGetAnalysisDetails)
 	}
 
 	return &dto.GetDetailsResponse{
-		Details: resp,
+		Details:    page.Details,
+		NextCursor: page.NextCursor,
 	}, nil
 }
 
+func (bs *BeautyRatingService) GetStats(ctx context.Context, userId int, req *dto.GetStatsRequest) (*dto.GetStatsResponse, error) {
+	period, err := analysis.ParseStatsPeriod(req.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := bs.analysisSrv.GetStats(ctx, userId, period)
+	if err != nil {
+		plog.Errorc(ctx, "get user: %v stats failed: %v", userId, err)
+		return nil, exception.ParseError(err, exception.ErrGetStats)
+	}
+
+	return &dto.GetStatsResponse{Stats: stats}, nil
+}
+
 func (bs *BeautyRatingService) DoAnalysis(ctx context.Context, userId int, fh *multipart.FileHeader) (*dto.DoAnalysisResponse, error) {
+	return bs.StreamAnalysis(ctx, userId, fh, func(string, any) {})
+}
+
+// StreamAnalysis 与 DoAnalysis 相同，同时通过 emit 推送上传完成、选中分析器等中间进度
+func (bs *BeautyRatingService) StreamAnalysis(ctx context.Context, userId int, fh *multipart.FileHeader, emit dto.EventEmitter) (*dto.DoAnalysisResponse, error) {
 	imageId, b, err := bs.analysisSrv.UploadAnalysisImage(ctx, fh)
 	if err != nil {
 		plog.Errorc(ctx, "upload analysis image failed: %v", err)
 		return nil, exception.ParseError(err, exception.ErrUploadImage)
 	}
+	emit(dto.EventUploaded, &dto.UploadedEvent{ImageId: imageId})
 
-	result, err := bs.analysisSrv.DoAnalysis(ctx, userId, imageId, b)
+	ctx = analyst.WithProgress(ctx, func(p analyst.Progress) {
+		emit(string(p.Stage), p)
+	})
+
+	result, err := bs.analysisSrv.DoAnalysis(ctx, userId, bs.userGender(ctx), imageId, b)
 	if err != nil {
 		plog.Errorc(ctx, "do analysis failed: %v", err)
 		return nil, exception.ParseError(err, exception.ErrDoAnalysis)
@@ -91,6 +173,60 @@ func (bs *BeautyRatingService) DoAnalysis(ctx context.Context, userId int, fh *m
 	return &dto.DoAnalysisResponse{Detail: result}, nil
 }
 
+func (bs *BeautyRatingService) SubmitAnalysisJob(ctx context.Context, userId int, fh *multipart.FileHeader) (*dto.DoAnalysisResponse, error) {
+	imageId, b, err := bs.analysisSrv.UploadAnalysisImage(ctx, fh)
+	if err != nil {
+		plog.Errorc(ctx, "upload analysis image failed: %v", err)
+		return nil, exception.ParseError(err, exception.ErrUploadImage)
+	}
+
+	job, err := bs.analysisSrv.SubmitAnalysisJob(ctx, userId, bs.userGender(ctx), imageId, b)
+	if err != nil {
+		plog.Errorc(ctx, "submit analysis job failed: %v", err)
+		return nil, exception.ParseError(err, exception.ErrSubmitAnalysisJob)
+	}
+
+	return &dto.DoAnalysisResponse{Job: job}, nil
+}
+
+func (bs *BeautyRatingService) GetAnalysisJob(ctx context.Context, userId, jobId int) (*dto.GetAnalysisJobResponse, error) {
+	job, err := bs.analysisSrv.GetAnalysisJob(ctx, userId, jobId)
+	if err != nil {
+		plog.Errorc(ctx, "get analysis job failed: %v", err)
+		return nil, exception.ParseError(err, exception.ErrGetAnalysisJob)
+	}
+
+	resp := &dto.GetAnalysisJobResponse{Job: job}
+	if job.Status == analysis.JobFailed {
+		resp.Message = job.Message
+		if resp.Message == "" {
+			resp.Message = exception.ErrDoAnalysis.Message()
+		}
+	}
+
+	return resp, nil
+}
+
+// RunAnalysisJobs 作为后台 worker 运行，执行异步分析任务
+func (bs *BeautyRatingService) RunAnalysisJobs(ctx context.Context) error {
+	return bs.analysisSrv.RunJobWorkers(ctx)
+}
+
+// userGender 仅在按性别排名时才向 auth-core 查询用户性别
+func (bs *BeautyRatingService) userGender(ctx context.Context) int {
+	if !bs.beautyConf.RankByGender {
+		return int(user.GenderUnknown)
+	}
+
+	u, err := bs.userSrv.GetUserInfo(ctx)
+	if err != nil {
+		plog.Warnc(ctx, "get user gender failed: %v", err)
+		return int(user.GenderUnknown)
+	}
+
+	return int(u.Gender)
+}
+
 func (bs *BeautyRatingService) DoFavorite(ctx context.Context, userId int, recordId int) error {
 	err := bs.analysisSrv.Favorite(ctx, userId, recordId)
 	if err != nil {
@@ -120,3 +256,42 @@ func (bs *BeautyRatingService) DeleteAnalysis(ctx context.Context, userId int, r
 
 	return nil
 }
+
+func (bs *BeautyRatingService) Reanalyze(ctx context.Context, userId int, req *dto.ReanalyzeRequest) (*dto.GetDetailResponse, error) {
+	var typ *analyst.AnalystType
+	if req.Analyst != "" {
+		t, err := analyst.ParseAnalystType(req.Analyst)
+		if err != nil || t == analyst.TypeSelector {
+			return nil, exception.ErrInvalidAnalystType
+		}
+		typ = &t
+	}
+
+	detail, err := bs.analysisSrv.Reanalyze(ctx, userId, req.ReportId, typ)
+	if err != nil {
+		plog.Errorc(ctx, "reanalyze report: %v failed: %v", req.ReportId, err)
+		return nil, exception.ParseError(err, exception.ErrReanalyze)
+	}
+
+	return &dto.GetDetailResponse{Detail: detail}, nil
+}
+
+func (bs *BeautyRatingService) GetAnalysisVersions(ctx context.Context, userId, reportId int) (*dto.GetVersionsResponse, error) {
+	versions, err := bs.analysisSrv.GetVersions(ctx, userId, reportId)
+	if err != nil {
+		plog.Errorc(ctx, "get report: %v versions failed: %v", reportId, err)
+		return nil, exception.ParseError(err, exception.ErrGetAnalysisVersions)
+	}
+
+	return &dto.GetVersionsResponse{Versions: versions}, nil
+}
+
+func (bs *BeautyRatingService) SetPrimaryVersion(ctx context.Context, userId int, req *dto.SetPrimaryVersionRequest) (*dto.GetDetailResponse, error) {
+	detail, err := bs.analysisSrv.SetPrimaryVersion(ctx, userId, req.ReportId, req.Version)
+	if err != nil {
+		plog.Errorc(ctx, "set report: %v primary version: %v failed: %v", req.ReportId, req.Version, err)
+		return nil, exception.ParseError(err, exception.ErrSetPrimaryVersion)
+	}
+
+	return &dto.GetDetailResponse{Detail: detail}, nil
+}
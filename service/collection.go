@@ -0,0 +1,116 @@
+// File:		collection.go
+// Created by:	Hoven
+// Created on:	2025-06-15
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package service
+
+import (
+	"context"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"github.com/yazl-tech/beauty-rating-server/service/dto"
+)
+
+func (bs *BeautyRatingService) GetCollections(ctx context.Context, userId int) (*dto.GetCollectionsResponse, error) {
+	collections, err := bs.analysisSrv.GetCollections(ctx, userId)
+	if err != nil {
+		plog.Errorc(ctx, "get collections failed: %v", err)
+		return nil, exception.ParseError(err, exception.ErrGetCollections)
+	}
+
+	return &dto.GetCollectionsResponse{Collections: collections}, nil
+}
+
+func (bs *BeautyRatingService) GetCollection(ctx context.Context, userId, collectionId int) (*dto.CollectionResponse, error) {
+	collection, err := bs.analysisSrv.GetCollection(ctx, userId, collectionId)
+	if err != nil {
+		plog.Errorc(ctx, "get collection: %v failed: %v", collectionId, err)
+		return nil, exception.ParseError(err, exception.ErrGetCollections)
+	}
+
+	return &dto.CollectionResponse{Collection: collection}, nil
+}
+
+func (bs *BeautyRatingService) CreateCollection(ctx context.Context, userId int, req *dto.CreateCollectionRequest) (*dto.CollectionResponse, error) {
+	collection, err := bs.analysisSrv.CreateCollection(ctx, userId, req.Name)
+	if err != nil {
+		plog.Errorc(ctx, "create collection: %v failed: %v", req.Name, err)
+		return nil, exception.ParseError(err, exception.ErrCreateCollection)
+	}
+
+	return &dto.CollectionResponse{Collection: collection}, nil
+}
+
+func (bs *BeautyRatingService) UpdateCollection(ctx context.Context, userId int, req *dto.UpdateCollectionRequest) (*dto.CollectionResponse, error) {
+	collection, err := bs.analysisSrv.UpdateCollection(ctx, userId, req.CollectionId, &analysis.CollectionUpdate{
+		Name:          req.Name,
+		CoverReportID: req.CoverReportId,
+	})
+	if err != nil {
+		plog.Errorc(ctx, "update collection: %v failed: %v", req.CollectionId, err)
+		return nil, exception.ParseError(err, exception.ErrUpdateCollection)
+	}
+
+	return &dto.CollectionResponse{Collection: collection}, nil
+}
+
+func (bs *BeautyRatingService) DeleteCollection(ctx context.Context, userId, collectionId int) error {
+	if err := bs.analysisSrv.DeleteCollection(ctx, userId, collectionId); err != nil {
+		plog.Errorc(ctx, "delete collection: %v failed: %v", collectionId, err)
+		return exception.ParseError(err, exception.ErrDeleteCollection)
+	}
+
+	return nil
+}
+
+func (bs *BeautyRatingService) SortCollections(ctx context.Context, userId int, req *dto.SortCollectionsRequest) (*dto.GetCollectionsResponse, error) {
+	collections, err := bs.analysisSrv.SortCollections(ctx, userId, req.CollectionIds)
+	if err != nil {
+		plog.Errorc(ctx, "sort collections failed: %v", err)
+		return nil, exception.ParseError(err, exception.ErrSortCollections)
+	}
+
+	return &dto.GetCollectionsResponse{Collections: collections}, nil
+}
+
+func (bs *BeautyRatingService) GetCollectionDetails(ctx context.Context, userId int, req *dto.GetCollectionDetailsRequest) (*dto.GetDetailsResponse, error) {
+	query, err := detailQuery(&req.GetDetailsRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := bs.analysisSrv.GetCollectionDetails(ctx, userId, req.CollectionId, query)
+	if err != nil {
+		plog.Errorc(ctx, "get collection: %v details failed: %v", req.CollectionId, err)
+		return nil, exception.ParseError(err, exception.ErrGetAnalysisDetails)
+	}
+
+	return &dto.GetDetailsResponse{
+		Details:    page.Details,
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+func (bs *BeautyRatingService) AddToCollection(ctx context.Context, userId int, req *dto.CollectionItemRequest) error {
+	if err := bs.analysisSrv.AddToCollection(ctx, userId, req.CollectionId, req.ReportId); err != nil {
+		plog.Errorc(ctx, "add report: %v to collection: %v failed: %v", req.ReportId, req.CollectionId, err)
+		return exception.ParseError(err, exception.ErrAddToCollection)
+	}
+
+	return nil
+}
+
+func (bs *BeautyRatingService) RemoveFromCollection(ctx context.Context, userId int, req *dto.CollectionItemRequest) error {
+	if err := bs.analysisSrv.RemoveFromCollection(ctx, userId, req.CollectionId, req.ReportId); err != nil {
+		plog.Errorc(ctx, "remove report: %v from collection: %v failed: %v", req.ReportId, req.CollectionId, err)
+		return exception.ParseError(err, exception.ErrRemoveFromCollection)
+	}
+
+	return nil
+}
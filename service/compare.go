@@ -0,0 +1,160 @@
+// File:		compare.go
+// Created by:	Hoven
+// Created on:	2025-06-11
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package service
+
+import (
+	"context"
+	"mime/multipart"
+	"sync"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"github.com/yazl-tech/beauty-rating-server/service/dto"
+)
+
+// CompareImages 同时分析前后两张照片，各自生成普通报告后再进行对比；
+// 任一照片分析失败或对比失败时删除已生成的报告，不留下只有一半的对比
+func (bs *BeautyRatingService) CompareImages(ctx context.Context, userId int, before, after *multipart.FileHeader) (*dto.CompareResponse, error) {
+	var (
+		wg      sync.WaitGroup
+		results [2]*dto.DoAnalysisResponse
+		errs    [2]error
+	)
+	for i, fh := range []*multipart.FileHeader{before, after} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = bs.DoAnalysis(ctx, userId, fh)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			bs.discardReports(ctx, userId, results[:])
+			return nil, err
+		}
+	}
+
+	resp, err := bs.CompareReports(ctx, userId, &dto.CompareRequest{
+		BeforeReportId: results[0].Detail.ID,
+		AfterReportId:  results[1].Detail.ID,
+	})
+	if err != nil {
+		bs.discardReports(ctx, userId, results[:])
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// discardReports 删除对比失败时已经生成的报告及其图片，请求可能已被取消，删除不受 ctx 取消影响
+func (bs *BeautyRatingService) discardReports(ctx context.Context, userId int, results []*dto.DoAnalysisResponse) {
+	ctx = context.WithoutCancel(ctx)
+	for _, r := range results {
+		if r == nil || r.Detail == nil {
+			continue
+		}
+		if err := bs.analysisSrv.DiscardAnalysis(ctx, userId, r.Detail.ID); err != nil {
+			plog.Warnc(ctx, "discard report: %v of failed comparison failed: %v", r.Detail.ID, err)
+		}
+	}
+}
+
+func (bs *BeautyRatingService) CompareReports(ctx context.Context, userId int, req *dto.CompareRequest) (*dto.CompareResponse, error) {
+	if req.BeforeReportId == 0 || req.AfterReportId == 0 {
+		return nil, exception.ErrCompareInput
+	}
+
+	comparison, err := bs.analysisSrv.Compare(ctx, userId, req.BeforeReportId, req.AfterReportId)
+	if err != nil {
+		plog.Errorc(ctx, "compare report: %v and %v failed: %v", req.BeforeReportId, req.AfterReportId, err)
+		return nil, exception.ParseError(err, exception.ErrCompare)
+	}
+
+	return &dto.CompareResponse{Comparison: comparison}, nil
+}
+
+func (bs *BeautyRatingService) GetComparison(ctx context.Context, userId, compareId int) (*dto.CompareResponse, error) {
+	comparison, err := bs.analysisSrv.GetComparison(ctx, userId, compareId)
+	if err != nil {
+		plog.Errorc(ctx, "get comparison: %v failed: %v", compareId, err)
+		return nil, exception.ParseError(err, exception.ErrGetComparisons)
+	}
+
+	return &dto.CompareResponse{Comparison: comparison}, nil
+}
+
+func (bs *BeautyRatingService) GetComparisons(ctx context.Context, userId int, req *dto.GetComparisonsRequest) (*dto.GetComparisonsResponse, error) {
+	query := &analysis.ComparisonQuery{
+		FavoriteOnly: req.Favorite,
+		Limit:        req.Limit,
+	}
+	if req.Cursor != "" {
+		after, err := analysis.ParseDetailCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query.After = after
+	}
+
+	page, err := bs.analysisSrv.GetComparisons(ctx, userId, query)
+	if err != nil {
+		plog.Errorc(ctx, "get comparisons failed: %v", err)
+		return nil, exception.ParseError(err, exception.ErrGetComparisons)
+	}
+
+	return &dto.GetComparisonsResponse{
+		Comparisons: page.Comparisons,
+		NextCursor:  page.NextCursor,
+	}, nil
+}
+
+func (bs *BeautyRatingService) FavoriteComparison(ctx context.Context, userId, compareId int) error {
+	if err := bs.analysisSrv.FavoriteComparison(ctx, userId, compareId); err != nil {
+		plog.Errorc(ctx, "favorite comparison: %v failed: %v", compareId, err)
+		return exception.ParseError(err, exception.ErrFavoriteComparison)
+	}
+
+	return nil
+}
+
+func (bs *BeautyRatingService) UnFavoriteComparison(ctx context.Context, userId, compareId int) error {
+	if err := bs.analysisSrv.UnFavoriteComparison(ctx, userId, compareId); err != nil {
+		plog.Errorc(ctx, "unfavorite comparison: %v failed: %v", compareId, err)
+		return exception.ParseError(err, exception.ErrUnFavoriteComparison)
+	}
+
+	return nil
+}
+
+func (bs *BeautyRatingService) ShareComparison(ctx context.Context, userId, compareId int) (*dto.ShareDetailResponse, error) {
+	token, err := bs.analysisSrv.ShareComparison(ctx, userId, compareId)
+	if err != nil {
+		plog.Errorc(ctx, "share comparison: %v failed: %v", compareId, err)
+		return nil, exception.ParseError(err, exception.ErrShareComparison)
+	}
+
+	return &dto.ShareDetailResponse{UrlQuery: token.String()}, nil
+}
+
+func (bs *BeautyRatingService) GetShareComparison(ctx context.Context, req *dto.GetShareComparisonRequest) (*dto.CompareResponse, error) {
+	comparison, err := bs.analysisSrv.GetShareComparison(ctx, &analysis.ShareCompareToken{
+		CompareId: req.CompareId,
+		Expires:   req.Expires,
+		Sig:       req.Sig,
+	})
+	if err != nil {
+		plog.Errorc(ctx, "get share comparison failed: %v", err)
+		return nil, exception.ParseError(err, exception.ErrGetShareDetail)
+	}
+
+	return &dto.CompareResponse{Comparison: comparison}, nil
+}
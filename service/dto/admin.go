@@ -0,0 +1,30 @@
+// File:		admin.go
+// Created by:	Hoven
+// Created on:	2025-05-22
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package dto
+
+import (
+	"github.com/yazl-tech/beauty-rating-server/domain/admin"
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+)
+
+type UpdateAnalystWeightsRequest struct {
+	Weights map[string]int `json:"weights" binding:"required"`
+}
+
+type AnalystWeightsResponse struct {
+	*admin.AnalystWeights
+}
+
+type GetExperimentReportRequest struct {
+	Experiment string `uri:"experiment" binding:"required"`
+}
+
+type ExperimentReportResponse struct {
+	*analysis.ExperimentReport
+}
@@ -8,10 +8,16 @@
 
 package dto
 
-import "github.com/yazl-tech/beauty-rating-server/domain/analysis"
+import (
+	"time"
+
+	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+)
 
 type GetImageRequest struct {
 	ImageId string `uri:"imageId" binding:"required"`
+	// Size 图片尺寸：thumb/medium/original，默认原图
+	Size string `form:"size"`
 }
 
 type DoAnalysisRequest struct {
@@ -19,7 +25,32 @@ type DoAnalysisRequest struct {
 }
 
 type DoAnalysisResponse struct {
-	Detail *analysis.AnalysisDetail `json:"detail"`
+	Detail *analysis.AnalysisDetail `json:"detail,omitempty"`
+	Job    *analysis.AnalysisJob    `json:"job,omitempty"`
+}
+
+// 流式分析推送的事件名，分析器的进度事件以 analyst.Stage 为事件名
+const (
+	EventUploaded = "uploaded"
+	EventResult   = "result"
+	EventError    = "error"
+)
+
+// EventEmitter 向客户端推送一个事件，需要保证并发安全
+type EventEmitter func(event string, data any)
+
+type UploadedEvent struct {
+	ImageId string `json:"imageId"`
+}
+
+type GetAnalysisJobRequest struct {
+	JobId int `uri:"jobId" binding:"required"`
+}
+
+type GetAnalysisJobResponse struct {
+	Job *analysis.AnalysisJob `json:"job"`
+	// Message 任务失败时展示给用户的原因
+	Message string `json:"message,omitempty"`
 }
 
 type DoFavoriteRequest struct {
@@ -30,8 +61,29 @@ type DeleteAnalysisRequest struct {
 	ReportId int `uri:"reportId" binding:"required"`
 }
 
+// GetDetailsRequest 报告列表的分页、排序和筛选条件，均为可选
+type GetDetailsRequest struct {
+	// Cursor 上一页返回的 nextCursor，为空时返回第一页
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+	// SortBy 排序字段：date/score，默认 date
+	SortBy string `form:"sortBy"`
+	// Order 排序方向：asc/desc，默认 desc
+	Order    string `form:"order"`
+	MinScore int    `form:"minScore"`
+	MaxScore int    `form:"maxScore"`
+	// Since、Until 报告日期范围，包含两端的日期
+	Since time.Time `form:"since" time_format:"2006-01-02"`
+	Until time.Time `form:"until" time_format:"2006-01-02"`
+	// Analyst 分析器类型：mock/ai/heuristic/ensemble
+	Analyst string `form:"analyst"`
+	Tag     string `form:"tag"`
+}
+
 type GetDetailsResponse struct {
 	Details []*analysis.AnalysisDetail `json:"details"`
+	// NextCursor 下一页的游标，没有更多报告时为空
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 type ShareDetailRequest struct {
@@ -51,3 +103,64 @@ type GetShareDetailRequest struct {
 type GetDetailResponse struct {
 	Detail *analysis.AnalysisDetail `json:"detail"`
 }
+
+type ReanalyzeRequest struct {
+	ReportId int `uri:"reportId" binding:"required"`
+	// Analyst 指定分析器类型：mock/ai/heuristic/ensemble，为空时与新上传的图片一样选择
+	Analyst string `form:"analyst"`
+}
+
+type GetVersionsRequest struct {
+	ReportId int `uri:"reportId" binding:"required"`
+}
+
+type GetVersionsResponse struct {
+	Versions []*analysis.AnalysisVersion `json:"versions"`
+}
+
+type SetPrimaryVersionRequest struct {
+	ReportId int `uri:"reportId" binding:"required"`
+	Version  int `uri:"version" binding:"required"`
+}
+
+// CompareRequest 未上传 before/after 两张照片时，对比用户已有的两份报告
+type CompareRequest struct {
+	BeforeReportId int `form:"beforeReportId"`
+	AfterReportId  int `form:"afterReportId"`
+}
+
+type CompareResponse struct {
+	Comparison *analysis.Comparison `json:"comparison"`
+}
+
+type GetComparisonRequest struct {
+	CompareId int `uri:"compareId" binding:"required"`
+}
+
+type GetComparisonsRequest struct {
+	Favorite bool `form:"favorite"`
+	// Cursor 上一页返回的 nextCursor，为空时返回第一页
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+type GetComparisonsResponse struct {
+	Comparisons []*analysis.Comparison `json:"comparisons"`
+	// NextCursor 下一页的游标，没有更多对比时为空
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+type GetShareComparisonRequest struct {
+	CompareId int    `form:"compareId" binding:"required"`
+	Expires   int64  `form:"expires" binding:"required"`
+	Sig       string `form:"sig" binding:"required"`
+}
+
+type GetStatsRequest struct {
+	// Period 分数趋势的统计周期：week/month，默认 week
+	Period string `form:"period"`
+}
+
+type GetStatsResponse struct {
+	Stats *analysis.UserStats `json:"stats"`
+}
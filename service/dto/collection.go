@@ -0,0 +1,49 @@
+// File:		collection.go
+// Created by:	Hoven
+// Created on:	2025-06-15
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package dto
+
+import "github.com/yazl-tech/beauty-rating-server/domain/analysis"
+
+type GetCollectionsResponse struct {
+	Collections []*analysis.Collection `json:"collections"`
+}
+
+type CollectionResponse struct {
+	Collection *analysis.Collection `json:"collection"`
+}
+
+type CreateCollectionRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type CollectionRequest struct {
+	CollectionId int `uri:"collectionId" binding:"required"`
+}
+
+// UpdateCollectionRequest 未传的字段保持不变，coverReportId 为 0 时使用收藏夹中最新的报告作为封面
+type UpdateCollectionRequest struct {
+	CollectionId  int     `uri:"collectionId" binding:"required"`
+	Name          *string `json:"name"`
+	CoverReportId *int    `json:"coverReportId"`
+}
+
+// SortCollectionsRequest 按顺序排列的收藏夹 id，需要包含除默认收藏夹外的所有收藏夹
+type SortCollectionsRequest struct {
+	CollectionIds []int `json:"collectionIds" binding:"required"`
+}
+
+type GetCollectionDetailsRequest struct {
+	CollectionId int `uri:"collectionId" binding:"required"`
+	GetDetailsRequest
+}
+
+type CollectionItemRequest struct {
+	CollectionId int `uri:"collectionId" binding:"required"`
+	ReportId     int `uri:"reportId" binding:"required"`
+}
@@ -0,0 +1,40 @@
+// File:		leaderboard.go
+// Created by:	Hoven
+// Created on:	2025-06-14
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package dto
+
+import "github.com/yazl-tech/beauty-rating-server/domain/leaderboard"
+
+// LeaderboardAppRequest 榜单按登录时的 X-App-Name 区分小程序
+type LeaderboardAppRequest struct {
+	AppName string `header:"X-App-Name"`
+}
+
+type LeaderboardMemberResponse struct {
+	Member *leaderboard.Member `json:"member"`
+}
+
+type GetLeaderboardRequest struct {
+	AppName string `header:"X-App-Name"`
+	// Window 榜单类型：daily/weekly/all
+	Window string `uri:"window" binding:"required"`
+	Limit  int    `form:"limit"`
+}
+
+type GetLeaderboardResponse struct {
+	Board *leaderboard.Board `json:"board"`
+}
+
+type GetMyRankRequest struct {
+	AppName string `header:"X-App-Name"`
+	Window  string `uri:"window" binding:"required"`
+}
+
+type GetMyRankResponse struct {
+	Rank *leaderboard.MyRank `json:"rank"`
+}
@@ -0,0 +1,80 @@
+// File:		leaderboard.go
+// Created by:	Hoven
+// Created on:	2025-06-14
+//
+// This file is part of the Example Project.
+//
+// (c) 2024 Example Corp. All rights reserved.
+
+package service
+
+import (
+	"context"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/yazl-tech/beauty-rating-server/domain/leaderboard"
+	"github.com/yazl-tech/beauty-rating-server/pkg/exception"
+	"github.com/yazl-tech/beauty-rating-server/service/dto"
+)
+
+func (bs *BeautyRatingService) JoinLeaderboard(ctx context.Context, req *dto.LeaderboardAppRequest) (*dto.LeaderboardMemberResponse, error) {
+	u, err := bs.userSrv.GetUserInfo(ctx)
+	if err != nil {
+		plog.Errorc(ctx, "get user info failed: %v", err)
+		return nil, exception.ParseError(err, exception.ErrGetUserInfo)
+	}
+
+	member, err := bs.leaderboardSrv.OptIn(ctx, req.AppName, u)
+	if err != nil {
+		plog.Errorc(ctx, "user: %v join leaderboard: %v failed: %v", u.ID, req.AppName, err)
+		return nil, exception.ParseError(err, exception.ErrJoinLeaderboard)
+	}
+
+	return &dto.LeaderboardMemberResponse{Member: member}, nil
+}
+
+func (bs *BeautyRatingService) LeaveLeaderboard(ctx context.Context, userId int, req *dto.LeaderboardAppRequest) error {
+	err := bs.leaderboardSrv.OptOut(ctx, req.AppName, userId)
+	if err != nil {
+		plog.Errorc(ctx, "user: %v leave leaderboard: %v failed: %v", userId, req.AppName, err)
+		return exception.ParseError(err, exception.ErrLeaveLeaderboard)
+	}
+
+	return nil
+}
+
+func (bs *BeautyRatingService) GetLeaderboard(ctx context.Context, req *dto.GetLeaderboardRequest) (*dto.GetLeaderboardResponse, error) {
+	window, err := leaderboard.ParseWindow(req.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := bs.leaderboardSrv.GetBoard(ctx, req.AppName, window, req.Limit)
+	if err != nil {
+		plog.Errorc(ctx, "get leaderboard: %v %v failed: %v", req.AppName, req.Window, err)
+		return nil, exception.ParseError(err, exception.ErrGetLeaderboard)
+	}
+
+	return &dto.GetLeaderboardResponse{Board: board}, nil
+}
+
+func (bs *BeautyRatingService) GetMyLeaderboardRank(ctx context.Context, req *dto.GetMyRankRequest) (*dto.GetMyRankResponse, error) {
+	window, err := leaderboard.ParseWindow(req.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := bs.userSrv.GetUserInfo(ctx)
+	if err != nil {
+		plog.Errorc(ctx, "get user info failed: %v", err)
+		return nil, exception.ParseError(err, exception.ErrGetUserInfo)
+	}
+
+	rank, err := bs.leaderboardSrv.GetMyRank(ctx, req.AppName, window, u)
+	if err != nil {
+		plog.Errorc(ctx, "get user: %v rank of leaderboard: %v %v failed: %v", u.ID, req.AppName, req.Window, err)
+		return nil, exception.ParseError(err, exception.ErrGetLeaderboardRank)
+	}
+
+	return &dto.GetMyRankResponse{Rank: rank}, nil
+}
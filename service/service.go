@@ -9,23 +9,42 @@
 package service
 
 import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-puzzles/puzzles/plog"
+	"github.com/pkg/errors"
 	doubaopb "github.com/yazl-tech/ai-bot/pkg/proto/doubao"
 	"github.com/yazl-tech/beauty-rating-server/config"
+	"github.com/yazl-tech/beauty-rating-server/domain/admin"
 	"github.com/yazl-tech/beauty-rating-server/domain/analysis"
+	"github.com/yazl-tech/beauty-rating-server/domain/leaderboard"
 	"github.com/yazl-tech/beauty-rating-server/domain/user"
 	"github.com/yazl-tech/beauty-rating-server/pkg/analyst"
 	"github.com/yazl-tech/beauty-rating-server/pkg/analyst/ai"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst/ensemble"
+	"github.com/yazl-tech/beauty-rating-server/pkg/analyst/heuristic"
 	"github.com/yazl-tech/beauty-rating-server/pkg/analyst/mock"
+	"github.com/yazl-tech/beauty-rating-server/pkg/experiment"
+	"github.com/yazl-tech/beauty-rating-server/pkg/metrics"
+	"github.com/yazl-tech/beauty-rating-server/pkg/moderator"
+	"github.com/yazl-tech/beauty-rating-server/pkg/moderator/rule"
 	"github.com/yazl-tech/beauty-rating-server/pkg/oss"
 	"google.golang.org/grpc"
 	"gorm.io/gorm"
 
 	analysisRepo "github.com/yazl-tech/beauty-rating-server/pkg/dal/analysis"
+	leaderboardRepo "github.com/yazl-tech/beauty-rating-server/pkg/dal/leaderboard"
+	aiModerator "github.com/yazl-tech/beauty-rating-server/pkg/moderator/ai"
 )
 
 type BeautyRatingService struct {
-	analysisSrv analysis.Service
-	userSrv     user.Service
+	beautyConf     *config.BeautyConfig
+	analysisSrv    analysis.Service
+	userSrv        user.Service
+	adminSrv       admin.Service
+	leaderboardSrv leaderboard.Service
 }
 
 func NewBeautyRatingService(
@@ -36,27 +55,222 @@ func NewBeautyRatingService(
 	beautyConf *config.BeautyConfig,
 	wechatConfig *user.WechatConfig,
 ) *BeautyRatingService {
-	mockAnalyst := mock.NewMockAnalyst()
+	mockAnalyst, err := newMockAnalyst(beautyConf)
+	plog.PanicError(err)
+	heuristicAnalyst := metrics.NewAnalyst(heuristic.NewHeuristicAnalyst())
 	doubaoClient := doubaopb.NewDoubaoHandlerClient(aiBotConn)
-	aiAnalyst := ai.NewAiAnalyst(beautyConf.AiModel, doubaoClient)
+	promptRegistry, err := ai.NewPromptRegistry(beautyConf.AiPromptDir)
+	plog.PanicError(err)
+	aiFactory := &aiAnalystFactory{prompts: promptRegistry, client: doubaoClient}
+	aiAnalysts, err := aiFactory.buildAll(beautyConf.AiModels)
+	plog.PanicError(err)
 
-	analystSelector := analyst.NewAnalystSelector(
-		analyst.WithAnalysts(mockAnalyst, beautyConf.AnalystWeight(mockAnalyst.Typ())),
-		analyst.WithAnalysts(aiAnalyst, beautyConf.AnalystWeight(aiAnalyst.Typ())),
-	)
+	// 组合分析器只使用第一个 AI 模型，避免多个模型同时计费；图片质量分与颜值分不可比，只作为参考成员
+	ensembleOpts := []ensemble.EnsembleOption{
+		ensemble.WithMember(aiAnalysts[0], beautyConf.EnsembleWeights[analyst.TypeAi]),
+		ensemble.WithMember(mockAnalyst, beautyConf.EnsembleWeights[mockAnalyst.Typ()]),
+		ensemble.WithDeadline(time.Duration(beautyConf.EnsembleTimeout) * time.Second),
+	}
+	if beautyConf.EnsembleWeights[heuristicAnalyst.Typ()] > 0 {
+		ensembleOpts = append(ensembleOpts, ensemble.WithReferenceMember(heuristicAnalyst))
+	}
+	ensembleAnalyst := metrics.NewAnalyst(ensemble.NewEnsembleAnalyst(ensembleOpts...))
+
+	analysts := append([]analyst.Analyst{mockAnalyst, heuristicAnalyst, ensembleAnalyst}, aiAnalysts...)
+	selectorOpts := []analyst.SelectorOption{
+		analyst.WithAnalystTimeout(time.Duration(beautyConf.AnalystTimeout) * time.Second),
+		analyst.WithCircuitBreaker(beautyConf.BreakerThreshold, time.Duration(beautyConf.BreakerCooldown)*time.Second),
+	}
+	for _, a := range analysts {
+		selectorOpts = append(selectorOpts, analyst.WithAnalysts(a, beautyConf.AnalystWeightByName(a.Name(), a.Typ())))
+	}
+	analystSelector := analyst.NewAnalystSelector(selectorOpts...)
 
-	analysisRepo := analysisRepo.NewAnalysisRepo(db)
+	analystExperiment, err := newExperiment(beautyConf, aiFactory, analysts...)
+	plog.PanicError(err)
+
+	imageModerator, err := newModerator(beautyConf, doubaoClient)
+	plog.PanicError(err)
+
+	jobRepo := analysisRepo.NewJobRepo(db)
+	analysisRepo := metrics.NewAnalysisRepo(analysisRepo.NewAnalysisRepo(db))
 	analysisSrv := analysis.NewAnalysisService(
 		beautyConf,
 		analystSelector,
+		analystExperiment,
+		imageModerator,
 		analysisRepo,
-		oss,
+		jobRepo,
+		metrics.NewOSS(oss),
 	)
 
 	userSrv := user.NewUserService(wechatConfig, authCoreConn)
 
+	adminSrv := admin.NewAdminService(analystSelector, analysisSrv)
+	beautyConf.OnReload(func(bc *config.BeautyConfig) {
+		adminSrv.ApplyConfigWeights(analystWeights(bc, analysts...))
+	})
+
+	leaderboardSrv := leaderboard.NewLeaderboardService(beautyConf, leaderboardRepo.NewLeaderboardRepo(db))
+
 	return &BeautyRatingService{
-		analysisSrv: analysisSrv,
-		userSrv:     userSrv,
+		beautyConf:     beautyConf,
+		analysisSrv:    analysisSrv,
+		userSrv:        userSrv,
+		adminSrv:       adminSrv,
+		leaderboardSrv: leaderboardSrv,
+	}
+}
+
+// analystWeights 将配置中按分析器类型配置的权重转换为按分析器名称的权重
+func analystWeights(bc *config.BeautyConfig, analysts ...analyst.Analyst) map[string]int {
+	weights := make(map[string]int, len(analysts))
+	for _, a := range analysts {
+		weights[a.Name()] = bc.AnalystWeightByName(a.Name(), a.Typ())
 	}
+	return weights
+}
+
+// aiAnalystFactory 按 AiModels 中的配置创建 AI 分析器
+type aiAnalystFactory struct {
+	prompts *ai.PromptRegistry
+	client  doubaopb.DoubaoHandlerClient
+}
+
+func (f *aiAnalystFactory) build(m config.AiModelConfig) (*metrics.Analyst, error) {
+	prompt, err := f.prompts.Render(m.PromptVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "aiModel: %v", m.Name)
+	}
+
+	opts := []ai.AiOption{
+		ai.WithName(m.Name),
+		ai.WithPrompt(prompt),
+		ai.WithTimeout(time.Duration(m.Timeout) * time.Second),
+	}
+	if m.Temperature != nil {
+		opts = append(opts, ai.WithTemperature(*m.Temperature))
+	}
+
+	return metrics.NewAnalyst(ai.NewAiAnalyst(m.Model, f.client, opts...)), nil
+}
+
+func (f *aiAnalystFactory) buildAll(models []config.AiModelConfig) ([]analyst.Analyst, error) {
+	analysts := make([]analyst.Analyst, 0, len(models))
+	for _, m := range models {
+		a, err := f.build(m)
+		if err != nil {
+			return nil, err
+		}
+		analysts = append(analysts, a)
+	}
+	return analysts, nil
+}
+
+// variantAiAnalyst 返回实验分组使用的 AI 分析器，分组单独指定提示词版本时创建新的分析器
+func variantAiAnalyst(bc *config.BeautyConfig, factory *aiAnalystFactory, byName map[string]analyst.Analyst, vc config.VariantConfig) (analyst.Analyst, error) {
+	m := bc.AiModels[0]
+	if vc.AiModel != "" {
+		idx := slices.IndexFunc(bc.AiModels, func(m config.AiModelConfig) bool { return m.Name == vc.AiModel })
+		if idx == -1 {
+			return nil, fmt.Errorf("unknown aiModel: %v", vc.AiModel)
+		}
+		m = bc.AiModels[idx]
+	}
+
+	if vc.PromptVersion == "" || vc.PromptVersion == m.PromptVersion {
+		return byName[m.Name], nil
+	}
+
+	m.PromptVersion = vc.PromptVersion
+	return factory.build(m)
+}
+
+// newExperiment 按配置创建 A/B 实验，每个分组的分析器失败时依次降级到离线的启发式和模拟分析器
+func newExperiment(
+	bc *config.BeautyConfig,
+	aiFactory *aiAnalystFactory,
+	analysts ...analyst.Analyst,
+) (*experiment.Experiment, error) {
+	if bc.Experiment.Key == "" {
+		return nil, nil
+	}
+
+	byType := make(map[analyst.AnalystType]analyst.Analyst, len(analysts))
+	byName := make(map[string]analyst.Analyst, len(analysts))
+	for _, a := range analysts {
+		if _, ok := byType[a.Typ()]; !ok {
+			byType[a.Typ()] = a
+		}
+		byName[a.Name()] = a
+	}
+
+	variants := make([]*experiment.Variant, 0, len(bc.Experiment.Variants))
+	for _, vc := range bc.Experiment.Variants {
+		a, ok := byType[vc.Analyst]
+		if !ok {
+			return nil, fmt.Errorf("variant: %v unknown analyst type: %v", vc.Name, vc.Analyst)
+		}
+
+		if vc.Analyst == analyst.TypeAi {
+			var err error
+			if a, err = variantAiAnalyst(bc, aiFactory, byName, vc); err != nil {
+				return nil, errors.Wrapf(err, "variant: %v", vc.Name)
+			}
+		}
+
+		opts := []analyst.SelectorOption{
+			analyst.WithAnalysts(a, 1),
+			analyst.WithAnalystTimeout(time.Duration(bc.AnalystTimeout) * time.Second),
+			analyst.WithCircuitBreaker(bc.BreakerThreshold, time.Duration(bc.BreakerCooldown)*time.Second),
+		}
+		for _, typ := range []analyst.AnalystType{analyst.TypeHeuristic, analyst.TypeMock} {
+			if typ != vc.Analyst {
+				opts = append(opts, analyst.WithAnalysts(byType[typ], 0))
+			}
+		}
+
+		variants = append(variants, &experiment.Variant{
+			Name:    vc.Name,
+			Weight:  vc.Weight,
+			Analyst: analyst.NewAnalystSelector(opts...),
+		})
+	}
+
+	return experiment.NewExperiment(bc.Experiment.Key, variants...)
+}
+
+func newMockAnalyst(bc *config.BeautyConfig) (*metrics.Analyst, error) {
+	opts := []mock.MockOption{mock.WithSalt(bc.MockSalt)}
+	if bc.MockPhrasePack != "" {
+		pack, err := mock.LoadPhrasePack(bc.MockPhrasePack)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, mock.WithPhrasePack(pack))
+	}
+
+	return metrics.NewAnalyst(mock.NewMockAnalyst(opts...)), nil
+}
+
+// newModerator 按配置顺序组装图片审核链
+func newModerator(bc *config.BeautyConfig, doubaoClient doubaopb.DoubaoHandlerClient) (moderator.Moderator, error) {
+	moderators := make([]moderator.Moderator, 0, len(bc.Moderators))
+	for _, name := range bc.Moderators {
+		switch name {
+		case "rule":
+			moderators = append(moderators, metrics.NewModerator(rule.NewRuleModerator()))
+		case "ai":
+			moderators = append(moderators, metrics.NewModerator(aiModerator.NewAiModerator(bc.AiModel, doubaoClient)))
+		default:
+			return nil, fmt.Errorf("unknown moderator: %v", name)
+		}
+	}
+
+	var opts []moderator.ChainOption
+	if bc.ModerationFailClosed {
+		opts = append(opts, moderator.WithFailClosed())
+	}
+
+	return moderator.NewChain(moderators, opts...), nil
 }